@@ -3,23 +3,46 @@ package main
 import (
 	"cmp"
 	"context"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/therenotomorrow/tasker/internal/cli"
+	"github.com/therenotomorrow/tasker/internal/config"
 	"github.com/therenotomorrow/tasker/internal/storage"
 	"github.com/therenotomorrow/tasker/pkg/jsonfile"
 )
 
 func main() {
 	ctx := context.Background()
-	file := cmp.Or(os.Getenv("TASKER_FILE"), "tasker.json")
+	// the wrong global flags are reported by the dispatch
+	globals, _, _ := cli.SplitGlobals(os.Args[1:])
+	file := cmp.Or(globals.File, os.Getenv("TASKER_FILE"), "tasker.json")
+	// zero or invalid value fallbacks to the default timeout
+	timeout, _ := time.ParseDuration(os.Getenv("TASKER_LOCK_TIMEOUT"))
 
-	config := cli.Config{
-		Output:  os.Stdout,
-		Storage: storage.MustNew(jsonfile.Config{File: file, TestHook: nil}),
+	workflow, err := config.LoadWorkflow(os.Getenv("TASKER_WORKFLOW"))
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "error: %v\n", err)
+
+		os.Exit(1)
+	}
+
+	stor := storage.MustNew(jsonfile.Config{File: file, LockTimeout: timeout, TestHook: nil})
+	conf := cli.Config{
+		Output:    os.Stdout,
+		ErrOutput: os.Stderr,
+		Storage:   stor,
+		Workflow:  workflow,
+		Actor:     os.Getenv("USER"),
+		Journal:   stor,
+		Locker:    stor,
+		Width:     cli.TerminalWidth(os.Stdout),
+		Color:     cli.TerminalColor(os.Stdout),
+		ErrColor:  cli.TerminalColor(os.Stderr),
 	}
 
-	tasker := cli.New(config)
+	tasker := cli.New(conf)
 	status := tasker.Dispatch(ctx, os.Args[1:])
 
 	os.Exit(status)
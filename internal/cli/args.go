@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	flagPrefix       = "--"
+	includeTagPrefix = "+"
+	excludeTagPrefix = "-"
+	filterOperators  = ":~<>"
+
+	fileFlag   = "file"
+	outputFlag = "output"
+	colorFlag  = "color"
+)
+
+// Globals are the flags of any command given before or after it.
+type Globals struct {
+	// File is the task file to use instead of the default one.
+	File string
+	// Output is the format of the command result, the text one without the flag.
+	Output string
+	// Color paints the text output "always", "never" or by the terminal in the "auto" one without the flag.
+	Color string
+}
+
+// SplitGlobals takes "--flag[=value]" global flags out of the arguments up to "--".
+// On failure the offending argument is returned.
+func SplitGlobals(args []string) (Globals, []string, string) {
+	globals := Globals{File: "", Output: formatText, Color: colorAuto}
+	values := map[string]*string{fileFlag: &globals.File, outputFlag: &globals.Output, colorFlag: &globals.Color}
+	rest := make([]string, 0, len(args))
+
+	for idx := 0; idx < len(args); idx++ {
+		arg := args[idx]
+
+		if arg == flagPrefix {
+			rest = append(rest, args[idx:]...)
+
+			break
+		}
+
+		flag, value, hasValue := strings.Cut(strings.TrimPrefix(arg, flagPrefix), "=")
+
+		target, global := values[flag]
+		if !strings.HasPrefix(arg, flagPrefix) || !global {
+			rest = append(rest, arg)
+
+			continue
+		}
+
+		if !hasValue {
+			if idx+1 == len(args) {
+				return globals, rest, arg
+			}
+
+			idx++
+			value = args[idx]
+		}
+
+		*target = value
+	}
+
+	return globals, rest, ""
+}
+
+// flagSpec declares known flags of the command and whether they take a value.
+type flagSpec map[string]bool
+
+type parsedArgs struct {
+	positional []string
+	flags      map[string]string
+}
+
+func (p parsedArgs) has(flag string) bool {
+	_, ok := p.flags[flag]
+
+	return ok
+}
+
+func (p parsedArgs) value(flag string) string {
+	return p.flags[flag]
+}
+
+// parseArgs splits arguments into positional ones and "--flag[=value]" flags
+// in any order, "--" ends the flags. Single dash is not a flag, so "-1" or "-tag"
+// stay positional. On failure the offending argument is returned.
+func parseArgs(args []string, spec flagSpec) (parsedArgs, string) {
+	parsed := parsedArgs{positional: make([]string, 0, len(args)), flags: make(map[string]string)}
+
+	for idx := 0; idx < len(args); idx++ {
+		arg := args[idx]
+
+		if arg == flagPrefix {
+			parsed.positional = append(parsed.positional, args[idx+1:]...)
+
+			break
+		}
+
+		if !strings.HasPrefix(arg, flagPrefix) {
+			parsed.positional = append(parsed.positional, arg)
+
+			continue
+		}
+
+		flag, value, hasValue := strings.Cut(strings.TrimPrefix(arg, flagPrefix), "=")
+
+		takesValue, known := spec[flag]
+		if !known || (hasValue && !takesValue) {
+			return parsed, arg
+		}
+
+		if takesValue && !hasValue {
+			if idx+1 == len(args) {
+				return parsed, arg
+			}
+
+			idx++
+			value = args[idx]
+		}
+
+		parsed.flags[flag] = value
+	}
+
+	return parsed, ""
+}
+
+// splitTags takes "+tag" and "-tag" arguments out of the positional ones.
+func splitTags(args []string) ([]string, []string, []string) {
+	include, exclude, rest := make([]string, 0), make([]string, 0), make([]string, 0, len(args))
+
+	for _, arg := range args {
+		switch {
+		case len(arg) > 1 && strings.HasPrefix(arg, includeTagPrefix):
+			include = append(include, arg)
+		case len(arg) > 1 && strings.HasPrefix(arg, excludeTagPrefix):
+			exclude = append(exclude, strings.TrimPrefix(arg, excludeTagPrefix))
+		default:
+			rest = append(rest, arg)
+		}
+	}
+
+	return include, exclude, rest
+}
+
+// splitFilter takes the filter terms like `prio>=high` out of the positional arguments, the rest
+// are left for the tags and the status. The argument with spaces is the whole expression quoted
+// by the shell, like 'status:todo prio>=high', so it goes to the filter as is.
+func splitFilter(args []string) (string, []string) {
+	terms, rest := make([]string, 0), make([]string, 0, len(args))
+
+	for _, arg := range args {
+		if strings.ContainsAny(arg, filterOperators) || strings.ContainsFunc(arg, unicode.IsSpace) {
+			terms = append(terms, arg)
+		} else {
+			rest = append(rest, arg)
+		}
+	}
+
+	return strings.Join(terms, " "), rest
+}
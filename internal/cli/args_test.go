@@ -0,0 +1,66 @@
+package cli_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/cli"
+)
+
+func TestUnitSplitGlobals(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		globals cli.Globals
+		rest    []string
+		bad     string
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want want
+	}{
+		{
+			name: "nothing",
+			args: []string{"list", "--tree"},
+			want: want{globals: cli.Globals{Output: "text", Color: "auto"}, rest: []string{"list", "--tree"}},
+		},
+		{
+			name: "before and after",
+			args: []string{"--file", "work.json", "list", "--output=csv", "--color", "never"},
+			want: want{globals: cli.Globals{File: "work.json", Output: "csv", Color: "never"}, rest: []string{"list"}},
+		},
+		{
+			name: "after flags end",
+			args: []string{"add", "--", "--file=x"},
+			want: want{globals: cli.Globals{Output: "text", Color: "auto"}, rest: []string{"add", "--", "--file=x"}},
+		},
+		{
+			name: "similar flag",
+			args: []string{"list", "--files"},
+			want: want{globals: cli.Globals{Output: "text", Color: "auto"}, rest: []string{"list", "--files"}},
+		},
+		{
+			name: "missing value",
+			args: []string{"list", "--file"},
+			want: want{globals: cli.Globals{Output: "text", Color: "auto"}, rest: []string{"list"}, bad: "--file"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			globals, rest, bad := cli.SplitGlobals(test.args)
+
+			if globals != test.want.globals || bad != test.want.bad {
+				t.Errorf("SplitGlobals() got = %v, %v, want = %v, %v", globals, bad, test.want.globals, test.want.bad)
+			}
+
+			if !reflect.DeepEqual(rest, test.want.rest) {
+				t.Errorf("SplitGlobals() got = %v, want = %v", rest, test.want.rest)
+			}
+		})
+	}
+}
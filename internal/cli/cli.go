@@ -4,8 +4,10 @@ import (
 	"context"
 	"io"
 	"os"
+	"slices"
 	"text/template"
 
+	"github.com/therenotomorrow/tasker/internal/domain"
 	"github.com/therenotomorrow/tasker/internal/usecases"
 )
 
@@ -21,61 +23,121 @@ const (
 )
 
 type Config struct {
-	Output  io.Writer
-	Storage usecases.Storage
+	Output io.Writer
+	// ErrOutput gets the errors apart from the results, nil stands for the Output.
+	ErrOutput io.Writer
+	Storage   usecases.Storage
+	// Workflow declares the task statuses, nil stands for the default one.
+	Workflow *domain.Workflow
+	// Actor is the one making the changes, it goes to the task history.
+	Actor string
+	// Journal keeps the operations to undo, nil turns undo off.
+	Journal usecases.Journal
+	// Locker holds the storage for the whole command, nil leaves every storage call to lock alone.
+	Locker usecases.Locker
+	// Width of the terminal to fit the output in, zero when the output is not the terminal.
+	Width int
+	// Color paints the text output by the "auto" color, it is on for the terminal without "$NO_COLOR".
+	Color bool
+	// ErrColor is the Color of the ErrOutput, it follows the Color without the ErrOutput.
+	ErrColor bool
 }
 
 type Cli struct {
 	config    Config
 	use       *usecases.UseCases
 	templates *template.Template
+	paint     *styler
+	// format is the output format of the command, its result or error is kept till the end for the structured ones.
+	format  string
+	result  table
+	problem *problem
+	// stream is the output the command wrote to, the text one is ended by the new line there.
+	stream io.Writer
 }
 
 func New(config Config) *Cli {
-	use := usecases.New(config.Storage)
-	templates := compileTemplates()
+	use := usecases.New(usecases.Config{
+		Storage:  config.Storage,
+		Workflow: config.Workflow,
+		Actor:    config.Actor,
+		Journal:  config.Journal,
+		Locker:   config.Locker,
+	})
+	paint := &styler{enabled: false, errors: false}
+	templates := compileTemplates(paint)
 
 	if config.Output == nil {
 		config.Output = os.Stdout
 	}
 
-	return &Cli{use: use, config: config, templates: templates}
+	if config.ErrOutput == nil {
+		config.ErrOutput, config.ErrColor = config.Output, config.Color
+	}
+
+	return &Cli{
+		use:       use,
+		config:    config,
+		templates: templates,
+		paint:     paint,
+		format:    formatText,
+		result:    table{columns: nil, rows: nil},
+		problem:   nil,
+		stream:    config.Output,
+	}
 }
 
 func (cli *Cli) Dispatch(ctx context.Context, args []string) int {
+	globals, args, bad := SplitGlobals(args)
+
+	cli.format, cli.result, cli.problem = formatText, table{columns: nil, rows: nil}, nil
+	cli.stream = cli.config.Output
+	// the structured output is never painted
+	cli.paint.enabled = globals.Output == formatText &&
+		(globals.Color == colorAlways || globals.Color == colorAuto && cli.config.Color)
+	cli.paint.errors = globals.Output == formatText &&
+		(globals.Color == colorAlways || globals.Color == colorAuto && cli.config.ErrColor)
+
+	status := success
+
+	switch {
+	case bad != "":
+		status = cli.errInvalidFlag(namespace, bad)
+	case !slices.Contains(allFormats(), globals.Output):
+		status = cli.errInvalidOutput(allFormats())
+	case !slices.Contains(allColors(), globals.Color):
+		status = cli.errInvalidColor(allColors())
+	}
+
+	if status != success {
+		_, _ = cli.stream.Write([]byte{'\n'})
+
+		return status
+	}
+
+	cli.format = globals.Output
+
 	if len(args) < oneArg {
-		return cli.Usage()
+		return cli.flush(cli.Usage())
 	}
 
 	command, args := args[0], args[1:]
 
-	status := cli.dispatch(ctx, command, args)
+	status = cli.dispatch(ctx, command, args)
 
-	// end output with new line
-	_, _ = cli.config.Output.Write([]byte{'\n'})
+	if cli.format == formatText {
+		// end output with new line
+		_, _ = cli.stream.Write([]byte{'\n'})
+	}
 
-	return status
+	return cli.flush(status)
 }
 
-func (cli *Cli) dispatch(ctx context.Context, command string, args []string) int {
-	switch command {
-	case "add":
-		return cli.Add(ctx, args)
-	case "update":
-		return cli.Update(ctx, args)
-	case "delete":
-		return cli.Delete(ctx, args)
-	case "mark":
-		return cli.Mark(ctx, args)
-	case "work":
-		return cli.Work(ctx, args)
-	case "done":
-		return cli.Done(ctx, args)
-	case "list":
-		return cli.List(ctx, args)
-	case "help":
-		return cli.Help()
-	default:
-		return cli.Unknown(command)
+func (cli *Cli) dispatch(ctx context.Context, name string, args []string) int {
+	cmd := lookupCommand(name)
+	if cmd == nil {
+		return cli.Unknown(name)
 	}
+
+	return cmd.run(cli, ctx, args)
 }
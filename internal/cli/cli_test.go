@@ -32,6 +32,7 @@ func TestUnitNew(t *testing.T) {
 		{name: "nothing", args: args{config: cli.Config{}}},
 		{name: "stdout", args: args{config: cli.Config{Output: os.Stdout}}},
 		{name: "other", args: args{config: cli.Config{Output: new(strings.Builder)}}},
+		{name: "stderr", args: args{config: cli.Config{Output: os.Stdout, ErrOutput: os.Stderr}}},
 	}
 
 	for _, test := range tests {
@@ -66,9 +67,36 @@ func TestUnitCliDispatch(t *testing.T) {
 		{name: "mark", args: args{args: []string{"mark"}}, want: noArgs},
 		{name: "work", args: args{args: []string{"work"}}, want: noArgs},
 		{name: "done", args: args{args: []string{"done"}}, want: noArgs},
+		{name: "reopen", args: args{args: []string{"reopen"}}, want: noArgs},
+		{name: "cancel", args: args{args: []string{"cancel"}}, want: noArgs},
+		{name: "prio", args: args{args: []string{"prio"}}, want: noArgs},
+		{name: "due", args: args{args: []string{"due"}}, want: noArgs},
+		{name: "tag", args: args{args: []string{"tag"}}, want: noArgs},
+		{name: "recur", args: args{args: []string{"recur"}}, want: noArgs},
+		{name: "depend", args: args{args: []string{"depend"}}, want: noArgs},
+		{name: "graph", args: args{args: []string{"graph"}}, want: noArgs},
+		{name: "history", args: args{args: []string{"history"}}, want: noArgs},
+		{name: "undo", args: args{args: []string{"undo", "--invalid"}}, want: invalid},
+		{name: "redo", args: args{args: []string{"redo", "invalid"}}, want: invalid},
+		{name: "trash", args: args{args: []string{"trash", "invalid"}}, want: invalid},
+		{name: "restore", args: args{args: []string{"restore"}}, want: noArgs},
+		{name: "purge", args: args{args: []string{"purge", "--invalid"}}, want: invalid},
+		{name: "archive", args: args{args: []string{"archive", "--invalid"}}, want: invalid},
+		{name: "search", args: args{args: []string{"search", "--invalid"}}, want: invalid},
 		{name: "list", args: args{args: []string{"list", "invalid"}}, want: invalid},
+		{name: "projects", args: args{args: []string{"projects", "invalid"}}, want: invalid},
+		{name: "migrate", args: args{args: []string{"migrate", "--invalid"}}, want: invalid},
 		{name: "help", args: args{args: []string{"help"}}, want: success},
 		{name: "unknown", args: args{args: []string{"unknown"}}, want: failure},
+		{name: "output", args: args{args: []string{"--output", "json", "help"}}, want: success},
+		{name: "output usage", args: args{args: []string{"--output=csv"}}, want: noArgs},
+		{name: "invalid output", args: args{args: []string{"help", "--output=xml"}}, want: invalid},
+		{name: "missing output", args: args{args: []string{"help", "--output"}}, want: invalid},
+		{name: "file", args: args{args: []string{"--file", "work.json", "help"}}, want: success},
+		{name: "missing file", args: args{args: []string{"help", "--file"}}, want: invalid},
+		{name: "extra argument", args: args{args: []string{"history", "1", "2"}}, want: invalid},
+		{name: "command help", args: args{args: []string{"list", "--help"}}, want: success},
+		{name: "output after flags end", args: args{args: []string{"graph", "--", "--output"}}, want: invalid},
 	}
 
 	for _, test := range tests {
@@ -97,3 +125,85 @@ func TestUnitCliDispatch(t *testing.T) {
 		t.Errorf("Dispatch() got = %v, want = %v", got, want)
 	}
 }
+
+func TestUnitCliDispatchStreams(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args     []string
+		errColor bool
+	}
+
+	type want struct {
+		code int
+		out  string
+		err  string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "result",
+			args: args{args: []string{"list", "--view", "compact"}},
+			want: want{code: success, out: "\n1 [progress] deploy \"api\", then docs\n2 [todo] write docs\n"},
+		},
+		{
+			name: "error",
+			args: args{args: []string{"list", "--view", "grid"}},
+			want: want{code: invalid, err: "error: invalid \"view\" parameter, must be one of [block table compact]\n"},
+		},
+		{
+			name: "unknown command",
+			args: args{args: []string{"lsit"}},
+			want: want{code: failure, err: "error: unknown command \"lsit\", did you mean \"list\"?\n"},
+		},
+		{
+			name: "invalid global",
+			args: args{args: []string{"list", "--file"}},
+			want: want{code: invalid, err: "error: invalid argument \"--file\" for command \"tasker\"\n"},
+		},
+		{
+			name: "structured error",
+			args: args{args: []string{"list", "--view", "grid", "--output", "jsonl"}},
+			want: want{
+				code: invalid,
+				err: `{"code":"invalidView","exitCode":2,"message":"invalid \"view\" parameter, ` +
+					`must be one of [block table compact]"}` + "\n",
+			},
+		},
+		{
+			name: "painted error",
+			args: args{args: []string{"list", "--view", "grid"}, errColor: true},
+			want: want{
+				code: invalid,
+				err:  "\x1b[1;31merror:\x1b[0m invalid \"view\" parameter, must be one of [block table compact]\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			out, errOut := bytes.NewBuffer(nil), bytes.NewBuffer(nil)
+			tasker := cli.New(cli.Config{
+				Output: out, ErrOutput: errOut, Storage: newViewsMock(), Color: false, ErrColor: test.args.errColor,
+			})
+
+			if got := tasker.Dispatch(t.Context(), test.args.args); got != test.want.code {
+				t.Errorf("Dispatch() got = %v, want = %v", got, test.want.code)
+			}
+
+			if got := out.String(); got != test.want.out {
+				t.Errorf("Dispatch() got = %q, want = %q", got, test.want.out)
+			}
+
+			if got := errOut.String(); got != test.want.err {
+				t.Errorf("Dispatch() got = %q, want = %q", got, test.want.err)
+			}
+		})
+	}
+}
@@ -1,43 +1,64 @@
 package cli
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/therenotomorrow/tasker/internal/domain"
 	"github.com/therenotomorrow/tasker/internal/usecases"
 )
 
 func (cli *Cli) Add(ctx context.Context, args []string) int {
-	if len(args) < oneArg {
-		return cli.errNotEnoughArgs("add")
+	parsed, code, ok := cli.parse("add", args)
+	if !ok {
+		return code
 	}
 
-	description := args[0]
-	task, err := cli.use.AddTask(ctx, description)
+	params := usecases.AddParams{
+		Description: parsed.positional[0],
+		Priority:    parsed.value("priority"),
+		Project:     parsed.value("project"),
+		ParentID:    parsed.value("parent"),
+	}
+	task, err := cli.use.AddTask(ctx, params)
 
 	switch {
+	case errors.Is(err, domain.ErrInvalidTaskID):
+		return cli.errInvalidTaskID(params.ParentID)
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return cli.errTaskNotFound(params.ParentID)
 	case errors.Is(err, domain.ErrEmptyDescription):
 		return cli.errInvalidDescription()
+	case errors.Is(err, domain.ErrInvalidPriority):
+		return cli.errInvalidPriority(domain.AllPriority())
+	case errors.Is(err, domain.ErrInvalidProject):
+		return cli.errInvalidProject()
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
 	case err != nil:
 		return cli.errUnexpected(err)
 	}
 
-	_ = cli.template(addTaskTpl).Execute(cli.config.Output, map[string]uint64{"TaskID": task.ID})
+	cli.render(addTaskTpl, map[string]uint64{"TaskID": task.ID}, taskTable([]*domain.Task{task}))
 
 	return success
 }
 
 func (cli *Cli) Update(ctx context.Context, args []string) int {
-	if len(args) < twoArgs {
-		return cli.errNotEnoughArgs("update")
+	parsed, code, ok := cli.parse("update", args)
+	if !ok {
+		return code
 	}
 
-	taskID, newDesc := args[0], args[1]
-	_, err := cli.use.UpdateTask(ctx, taskID, newDesc)
+	taskID, newDesc := parsed.positional[0], parsed.positional[1]
+	task, err := cli.use.UpdateTask(ctx, taskID, newDesc)
 
 	switch {
 	case errors.Is(err, domain.ErrEmptyDescription):
@@ -46,46 +67,60 @@ func (cli *Cli) Update(ctx context.Context, args []string) int {
 		return cli.errTaskNotFound(taskID)
 	case errors.Is(err, domain.ErrInvalidTaskID):
 		return cli.errInvalidTaskID(taskID)
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
 	case err != nil:
 		return cli.errUnexpected(err)
 	}
 
-	_ = cli.template(updateTaskTpl).Execute(cli.config.Output, nil)
+	cli.render(updateTaskTpl, nil, taskTable([]*domain.Task{task}))
 
 	return success
 }
 
 func (cli *Cli) Delete(ctx context.Context, args []string) int {
-	if len(args) < oneArg {
-		return cli.errNotEnoughArgs("delete")
+	parsed, code, ok := cli.parse("delete", args)
+	if !ok {
+		return code
 	}
 
-	taskID := args[0]
-	err := cli.use.DeleteTask(ctx, taskID)
+	taskID := parsed.positional[0]
+	err := cli.use.DeleteTask(ctx, taskID, usecases.DeleteOptions{Cascade: parsed.has("cascade")})
 
 	switch {
+	case errors.Is(err, domain.ErrHasSubtasks):
+		return cli.errHasSubtasks()
 	case errors.Is(err, domain.ErrTaskNotFound):
 		return cli.errTaskNotFound(taskID)
 	case errors.Is(err, domain.ErrInvalidTaskID):
 		return cli.errInvalidTaskID(taskID)
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
 	case err != nil:
 		return cli.errUnexpected(err)
 	}
 
-	_ = cli.template(deleteTaskTpl).Execute(cli.config.Output, nil)
+	tid, _ := strconv.ParseUint(taskID, 10, 64)
+	cli.render(deleteTaskTpl, nil, idTable(tid))
 
 	return success
 }
 
 func (cli *Cli) Mark(ctx context.Context, args []string) int {
-	if len(args) < twoArgs {
-		return cli.errNotEnoughArgs("mark")
+	parsed, code, ok := cli.parse("mark", args)
+	if !ok {
+		return code
 	}
 
-	taskID, status := args[0], args[1]
-	_, err := cli.use.MarkTask(ctx, taskID, status)
+	taskID, status := parsed.positional[0], parsed.positional[1]
+	opts := usecases.MarkOptions{Force: parsed.has("force"), CloseParent: parsed.has("close-parent")}
+	task, err := cli.use.MarkTask(ctx, taskID, status, opts)
 
 	switch {
+	case errors.Is(err, domain.ErrOpenSubtasks):
+		return cli.errOpenSubtasks()
+	case errors.Is(err, domain.ErrTaskBlocked):
+		return cli.errTaskBlocked()
 	case errors.Is(err, domain.ErrTaskNotFound):
 		return cli.errTaskNotFound(taskID)
 	case errors.Is(err, domain.ErrInvalidTaskID):
@@ -93,19 +128,24 @@ func (cli *Cli) Mark(ctx context.Context, args []string) int {
 	case errors.Is(err, domain.ErrTaskAlreadyDone):
 		return cli.errTaskAlreadyDone()
 	case errors.Is(err, domain.ErrInvalidStatus):
-		return cli.errInvalidStatus(domain.AllStatus())
+		return cli.errInvalidStatus(cli.use.Workflow().Statuses())
+	case errors.Is(err, domain.ErrInvalidTransition):
+		return cli.errInvalidTransition(err)
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
 	case err != nil:
 		return cli.errUnexpected(err)
 	}
 
-	_ = cli.template(markTaskTpl).Execute(cli.config.Output, nil)
+	cli.render(markTaskTpl, nil, taskTable([]*domain.Task{task}))
 
 	return success
 }
 
 func (cli *Cli) Work(ctx context.Context, args []string) int {
-	if len(args) < oneArg {
-		return cli.errNotEnoughArgs("work")
+	_, code, ok := cli.parse("work", args)
+	if !ok {
+		return code
 	}
 
 	args = append(args, string(domain.StatusProgress))
@@ -114,82 +154,1042 @@ func (cli *Cli) Work(ctx context.Context, args []string) int {
 }
 
 func (cli *Cli) Done(ctx context.Context, args []string) int {
-	if len(args) < oneArg {
-		return cli.errNotEnoughArgs("done")
+	_, code, ok := cli.parse("done", args)
+	if !ok {
+		return code
 	}
 
-	args = append(args, string(domain.StatusDone))
+	args = append(args, string(cli.use.Workflow().Completed()))
 
 	return cli.Mark(ctx, args)
 }
 
+func (cli *Cli) Reopen(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("reopen", args)
+	if !ok {
+		return code
+	}
+
+	taskID := parsed.positional[0]
+	task, err := cli.use.ReopenTask(ctx, taskID, parsed.value("reason"))
+
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return cli.errTaskNotFound(taskID)
+	case errors.Is(err, domain.ErrInvalidTaskID):
+		return cli.errInvalidTaskID(taskID)
+	case errors.Is(err, domain.ErrTaskNotClosed):
+		return cli.errTaskNotClosed()
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	cli.render(reopenTaskTpl, nil, taskTable([]*domain.Task{task}))
+
+	return success
+}
+
+func (cli *Cli) Cancel(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("cancel", args)
+	if !ok {
+		return code
+	}
+
+	taskID := parsed.positional[0]
+	opts := usecases.CancelOptions{Force: parsed.has("force")}
+	task, err := cli.use.CancelTask(ctx, taskID, parsed.value("reason"), opts)
+
+	switch {
+	case errors.Is(err, domain.ErrOpenSubtasks):
+		return cli.errOpenSubtasks()
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return cli.errTaskNotFound(taskID)
+	case errors.Is(err, domain.ErrInvalidTaskID):
+		return cli.errInvalidTaskID(taskID)
+	case errors.Is(err, domain.ErrTaskAlreadyDone):
+		return cli.errTaskAlreadyDone()
+	case errors.Is(err, domain.ErrInvalidTransition):
+		return cli.errInvalidTransition(err)
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	cli.render(cancelTaskTpl, nil, taskTable([]*domain.Task{task}))
+
+	return success
+}
+
+func (cli *Cli) Prio(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("prio", args)
+	if !ok {
+		return code
+	}
+
+	taskID, level := parsed.positional[0], parsed.positional[1]
+	task, err := cli.use.PrioritizeTask(ctx, taskID, level)
+
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return cli.errTaskNotFound(taskID)
+	case errors.Is(err, domain.ErrInvalidTaskID):
+		return cli.errInvalidTaskID(taskID)
+	case errors.Is(err, domain.ErrInvalidPriority):
+		return cli.errInvalidPriority(domain.AllPriority())
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	cli.render(prioTaskTpl, nil, taskTable([]*domain.Task{task}))
+
+	return success
+}
+
+func (cli *Cli) Due(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("due", args)
+	if !ok {
+		return code
+	}
+
+	// relative dates could be passed without quotes, e.g. "next fri"
+	taskID, when := parsed.positional[0], strings.Join(parsed.positional[1:], " ")
+	task, err := cli.use.DueTask(ctx, taskID, when)
+
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return cli.errTaskNotFound(taskID)
+	case errors.Is(err, domain.ErrInvalidTaskID):
+		return cli.errInvalidTaskID(taskID)
+	case errors.Is(err, domain.ErrInvalidDate):
+		return cli.errInvalidDate()
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	cli.render(dueTaskTpl, nil, taskTable([]*domain.Task{task}))
+
+	return success
+}
+
+func (cli *Cli) Tag(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("tag", args)
+	if !ok {
+		return code
+	}
+
+	taskID := parsed.positional[0]
+
+	add, remove, rest := splitTags(parsed.positional[1:])
+	if len(rest) > 0 {
+		return cli.errInvalidFlag("tag", rest[0])
+	}
+
+	task, err := cli.use.TagTask(ctx, taskID, add, remove)
+
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return cli.errTaskNotFound(taskID)
+	case errors.Is(err, domain.ErrInvalidTaskID):
+		return cli.errInvalidTaskID(taskID)
+	case errors.Is(err, domain.ErrInvalidTag):
+		return cli.errInvalidTag()
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	cli.render(tagTaskTpl, nil, taskTable([]*domain.Task{task}))
+
+	return success
+}
+
+func (cli *Cli) Recur(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("recur", args)
+	if !ok {
+		return code
+	}
+
+	stop := parsed.has("stop")
+
+	switch {
+	case stop && len(parsed.positional) > oneArg:
+		return cli.errInvalidFlag("recur", parsed.positional[oneArg])
+	case !stop && len(parsed.positional) < twoArgs:
+		return cli.errNotEnoughArgs("recur")
+	}
+
+	// rules could be passed without quotes, e.g. "every 2w"
+	taskID, rule := parsed.positional[0], strings.Join(parsed.positional[1:], " ")
+	task, err := cli.use.RecurTask(ctx, taskID, rule, usecases.RecurOptions{Stop: stop})
+
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return cli.errTaskNotFound(taskID)
+	case errors.Is(err, domain.ErrInvalidTaskID):
+		return cli.errInvalidTaskID(taskID)
+	case errors.Is(err, domain.ErrInvalidRecurrence):
+		return cli.errInvalidRecurrence()
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	cli.render(recurTaskTpl, nil, taskTable([]*domain.Task{task}))
+
+	return success
+}
+
 type listView struct {
 	ID          uint64
 	Description string
 	Status      domain.Status
+	Priority    domain.Priority
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	LastUpdate  string
+	HasDue      bool
+	DueAt       time.Time
+	Due         string
+	Overdue     bool
+	Tags        string
+	Project     string
+	ParentID    uint64
+	BlockedBy   string
+	Recurrence  domain.Recurrence
+	Transition  *domain.Transition
+	Archived    bool
 }
 
-func lastUpdateString(t time.Time) string {
-	var lastUpdate string
+func durationString(duration time.Duration) string {
+	var str string
 
 	const oneDay = 24
 
-	switch duration := time.Since(t); {
+	switch {
 	case duration.Hours() < 1:
-		lastUpdate = fmt.Sprintf("%d minute(s)", int(duration.Minutes()))
+		str = fmt.Sprintf("%d minute(s)", int(duration.Minutes()))
 	case duration.Hours() < oneDay:
-		lastUpdate = fmt.Sprintf("%d hour(s)", int(duration.Hours()))
+		str = fmt.Sprintf("%d hour(s)", int(duration.Hours()))
 	default:
-		lastUpdate = fmt.Sprintf("%d day(s)", int(duration.Hours()/oneDay))
+		str = fmt.Sprintf("%d day(s)", int(duration.Hours()/oneDay))
 	}
 
-	return lastUpdate
+	return str
+}
+
+func lastUpdateString(t time.Time) string {
+	return durationString(time.Since(t))
+}
+
+func dueString(task *domain.Task, overdue bool, now time.Time) string {
+	switch {
+	case overdue:
+		return "overdue by " + durationString(now.Sub(task.DueAt))
+	case now.After(task.DueAt):
+		return durationString(now.Sub(task.DueAt)) + " ago"
+	default:
+		return "in " + durationString(task.DueAt.Sub(now))
+	}
+}
+
+// statusesString quotes statuses for the sentence like "todo", "progress", or "done".
+func statusesString(statuses []domain.Status) string {
+	quoted := make([]string, len(statuses))
+	for idx, status := range statuses {
+		quoted[idx] = strconv.Quote(string(status))
+	}
+
+	if len(quoted) < twoArgs {
+		return strings.Join(quoted, "")
+	}
+
+	last := len(quoted) - 1
+	if last == 1 {
+		return quoted[0] + " or " + quoted[last]
+	}
+
+	return strings.Join(quoted[:last], ", ") + ", or " + quoted[last]
+}
+
+func idsString(ids []uint64) string {
+	strs := make([]string, len(ids))
+	for idx, tid := range ids {
+		strs[idx] = strconv.FormatUint(tid, 10)
+	}
+
+	return strings.Join(strs, ", ")
+}
+
+func tagsString(tags []string) string {
+	prefixed := make([]string, len(tags))
+	for idx, tag := range tags {
+		prefixed[idx] = includeTagPrefix + tag
+	}
+
+	return strings.Join(prefixed, " ")
+}
+
+type treeView struct {
+	Prefix      string
+	ID          uint64
+	Status      domain.Status
+	Description string
+	Blocked     bool
+}
+
+const (
+	treeBranch     = "├── "
+	treeLastBranch = "└── "
+	treeIndent     = "│   "
+	treeLastIndent = "    "
+)
+
+// treeViews puts subtasks under their parents keeping the list order, tasks with
+// the parent out of the list are shown as roots.
+func treeViews(list []*domain.Task) []treeView {
+	listed := make(map[uint64]bool, len(list))
+	for _, task := range list {
+		listed[task.ID] = true
+	}
+
+	roots := make([]*domain.Task, 0)
+	children := make(map[uint64][]*domain.Task)
+
+	for _, task := range list {
+		if task.HasParent() && listed[task.ParentID] {
+			children[task.ParentID] = append(children[task.ParentID], task)
+		} else {
+			roots = append(roots, task)
+		}
+	}
+
+	views := make([]treeView, 0, len(list))
+	subtasks := func(task *domain.Task) []*domain.Task { return children[task.ID] }
+
+	walkTree(roots, subtasks, func(task *domain.Task, prefix string) {
+		views = append(views, treeView{
+			Prefix:      prefix,
+			ID:          task.ID,
+			Status:      task.Status,
+			Description: task.Description,
+			Blocked:     false,
+		})
+	})
+
+	return views
+}
+
+// graphTasks lists the tasks of the graph in the order they are shown.
+func graphTasks(root *usecases.DependencyNode) []*domain.Task {
+	tasks := make([]*domain.Task, 0)
+	dependencies := func(node *usecases.DependencyNode) []*usecases.DependencyNode { return node.BlockedBy }
+
+	walkTree([]*usecases.DependencyNode{root}, dependencies, func(node *usecases.DependencyNode, _ string) {
+		tasks = append(tasks, node.Task)
+	})
+
+	return tasks
+}
+
+func graphViews(root *usecases.DependencyNode) []treeView {
+	views := make([]treeView, 0)
+	dependencies := func(node *usecases.DependencyNode) []*usecases.DependencyNode { return node.BlockedBy }
+
+	walkTree([]*usecases.DependencyNode{root}, dependencies, func(node *usecases.DependencyNode, prefix string) {
+		views = append(views, treeView{
+			Prefix:      prefix,
+			ID:          node.Task.ID,
+			Status:      node.Task.Status,
+			Description: node.Task.Description,
+			Blocked:     node.Blocked,
+		})
+	})
+
+	return views
+}
+
+// walkTree visits nodes depth first together with the prefix drawing their branch.
+func walkTree[T any](roots []T, children func(node T) []T, visit func(node T, prefix string)) {
+	var walk func(node T, prefix string, indent string)
+
+	walk = func(node T, prefix string, indent string) {
+		visit(node, prefix)
+
+		nodes := children(node)
+		for idx, child := range nodes {
+			if idx == len(nodes)-1 {
+				walk(child, indent+treeLastBranch, indent+treeLastIndent)
+			} else {
+				walk(child, indent+treeBranch, indent+treeIndent)
+			}
+		}
+	}
+
+	for _, root := range roots {
+		walk(root, "", "")
+	}
 }
 
 func (cli *Cli) List(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("list", args)
+	if !ok {
+		return code
+	}
+
+	view, columns, code, ok := cli.listLayout(parsed)
+	if !ok {
+		return code
+	}
+
+	filter, positional := splitFilter(parsed.positional)
+	tags, excludeTags, rest := splitTags(positional)
+
+	if len(rest) > oneArg {
+		return cli.errInvalidFlag("list", rest[oneArg])
+	}
+
 	status := ""
-	if len(args) > 0 {
-		status = args[0]
+	if len(rest) > 0 {
+		status = rest[0]
 	}
 
-	list, err := cli.use.ListTasks(ctx, usecases.ListParams{Status: status})
+	params := usecases.ListParams{
+		Status:        status,
+		Priority:      parsed.value("priority"),
+		PriorityFirst: parsed.has("by-priority"),
+		Overdue:       parsed.has("overdue"),
+		DueBefore:     parsed.value("due-before"),
+		Tags:          tags,
+		ExcludeTags:   excludeTags,
+		Project:       parsed.value("project"),
+		Ready:         parsed.has("ready"),
+		Scope:         listScope(parsed),
+		Filter:        filter,
+		Sort:          parsed.value("sort"),
+		Limit:         parsed.value("limit"),
+		Offset:        parsed.value("offset"),
+		Reverse:       parsed.has("reverse"),
+	}
+	list, err := cli.use.ListTasks(ctx, params)
 
 	switch {
+	case errors.Is(err, domain.ErrInvalidFilter):
+		return cli.errInvalidFilter(filter, err)
+	case errors.Is(err, domain.ErrInvalidSort):
+		return cli.errInvalidSort(domain.AllSortFields())
+	case errors.Is(err, domain.ErrInvalidLimit):
+		return cli.errInvalidLimit()
+	case errors.Is(err, domain.ErrInvalidOffset):
+		return cli.errInvalidOffset()
 	case errors.Is(err, domain.ErrInvalidStatus):
-		return cli.errInvalidStatus(domain.AllStatus())
+		return cli.errInvalidStatus(cli.use.Workflow().Statuses())
+	case errors.Is(err, domain.ErrInvalidPriority):
+		return cli.errInvalidPriority(domain.AllPriority())
+	case errors.Is(err, domain.ErrInvalidDate):
+		return cli.errInvalidDate()
+	case errors.Is(err, domain.ErrInvalidTag):
+		return cli.errInvalidTag()
+	case errors.Is(err, domain.ErrInvalidProject):
+		return cli.errInvalidProject()
 	case errors.Is(err, domain.ErrEmptyTasks):
 		return cli.errTaskListIsEmpty()
 	case err != nil:
 		return cli.errUnexpected(err)
 	}
 
+	if parsed.has("tree") {
+		cli.render(listTreeTpl, treeViews(list), taskTable(list))
+
+		return success
+	}
+
+	switch view {
+	case viewTable:
+		cli.render(listLinesTpl, tableLines(list, columns, cli.config.Width, time.Now(), cli.paint), taskTable(list))
+	case viewCompact:
+		cli.render(listLinesTpl, compactLines(list, columns, cli.config.Width, time.Now(), cli.paint), taskTable(list))
+	default:
+		cli.render(listTaskTpl, cli.listViews(list), taskTable(list))
+	}
+
+	return success
+}
+
+// listLayout gives the view of the list with its columns, the columns alone choose the table.
+func (cli *Cli) listLayout(parsed parsedArgs) (string, []column, int, bool) {
+	view := parsed.value("view")
+	if view == "" {
+		view = viewBlock
+		if parsed.has("columns") {
+			view = viewTable
+		}
+	}
+
+	switch {
+	case !slices.Contains(allViews(), view):
+		return view, nil, cli.errInvalidView(allViews()), false
+	case view == viewBlock && parsed.has("columns"):
+		return view, nil, cli.errConflictingFlags("view", "columns"), false
+	}
+
+	columns, valid := parseColumns(cmp.Or(parsed.value("columns"), defaultColumns(view)))
+	if !valid {
+		return view, nil, cli.errInvalidColumns(columnNames()), false
+	}
+
+	return view, columns, success, true
+}
+
+func (cli *Cli) listViews(list []*domain.Task) []listView {
+	now := time.Now()
 	views := make([]listView, len(list))
 
 	for idx, task := range list {
+		// tasks closed in any terminal status are never late
+		overdue := task.IsOverdue(now) && !cli.use.Workflow().IsTerminal(task.Status)
 		views[idx] = listView{
 			ID:          task.ID,
 			Description: task.Description,
 			Status:      task.Status,
+			Priority:    task.Priority,
 			CreatedAt:   task.CreatedAt,
 			UpdatedAt:   task.UpdatedAt,
 			LastUpdate:  lastUpdateString(task.UpdatedAt),
+			HasDue:      task.HasDue(),
+			DueAt:       task.DueAt,
+			Due:         dueString(task, overdue, now),
+			Overdue:     overdue,
+			Tags:        tagsString(task.Tags),
+			Project:     task.Project,
+			ParentID:    task.ParentID,
+			BlockedBy:   idsString(task.BlockedBy),
+			Recurrence:  task.Recurrence,
+			Transition:  task.Transition,
+			Archived:    task.IsArchived(),
 		}
 	}
 
-	slices.SortStableFunc(views, func(a, b listView) int {
-		return b.UpdatedAt.Compare(a.UpdatedAt)
-	})
+	return views
+}
+
+func (cli *Cli) Search(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("search", args)
+	if !ok {
+		return code
+	}
+
+	params := usecases.SearchParams{Query: searchQuery(parsed.positional), Scope: listScope(parsed)}
+	result, err := cli.use.SearchTasks(ctx, params)
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidQuery):
+		return cli.errInvalidQuery()
+	case errors.Is(err, domain.ErrEmptyTasks):
+		return cli.errTaskListIsEmpty()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	views := cli.listViews(result.Tasks)
+
+	for idx := range views {
+		views[idx].Description = highlight(views[idx].Description, result.Query)
+
+		if transition := views[idx].Transition; transition != nil {
+			highlighted := *transition
+			highlighted.Reason = highlight(transition.Reason, result.Query)
+			views[idx].Transition = &highlighted
+		}
+	}
+
+	cli.render(listTaskTpl, views, taskTable(result.Tasks))
+
+	return success
+}
+
+// searchQuery joins the arguments back, the one with spaces is the phrase quoted by the shell.
+func searchQuery(args []string) string {
+	terms := make([]string, len(args))
+
+	for idx, arg := range args {
+		if strings.ContainsFunc(arg, unicode.IsSpace) && !strings.Contains(arg, `"`) {
+			arg = strconv.Quote(arg)
+		}
+
+		terms[idx] = arg
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// highlight marks the parts of the text matched by the query.
+func highlight(text string, query domain.SearchQuery) string {
+	const mark = "*"
+
+	var (
+		builder strings.Builder
+		last    int
+	)
+
+	for _, span := range query.Highlight(text) {
+		builder.WriteString(text[last:span.Start])
+		builder.WriteString(mark + text[span.Start:span.End] + mark)
+
+		last = span.End
+	}
 
-	_ = cli.template(listTaskTpl).Execute(cli.config.Output, views)
+	builder.WriteString(text[last:])
+
+	return builder.String()
+}
+
+func listScope(parsed parsedArgs) usecases.Scope {
+	switch {
+	case parsed.has("archived"):
+		return usecases.ScopeArchived
+	case parsed.has("all"):
+		return usecases.ScopeAll
+	default:
+		return usecases.ScopeActive
+	}
+}
+
+func (cli *Cli) Depend(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("depend", args)
+	if !ok {
+		return code
+	}
+
+	if !parsed.has("on") {
+		return cli.errNotEnoughArgs("depend")
+	}
+
+	taskID, onID := parsed.positional[0], parsed.value("on")
+	task, err := cli.use.DependTask(ctx, taskID, onID, usecases.DependOptions{Remove: parsed.has("remove")})
+
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return cli.errTaskNotFound(taskID)
+	case errors.Is(err, domain.ErrDependencyNotFound):
+		return cli.errTaskNotFound(onID)
+	case errors.Is(err, domain.ErrInvalidTaskID):
+		return cli.errInvalidTaskID(taskID)
+	case errors.Is(err, domain.ErrDependencyCycle):
+		return cli.errDependencyCycle(taskID, onID)
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	cli.render(dependTaskTpl, nil, taskTable([]*domain.Task{task}))
+
+	return success
+}
+
+func (cli *Cli) Graph(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("graph", args)
+	if !ok {
+		return code
+	}
+
+	taskID := parsed.positional[0]
+	graph, err := cli.use.DependencyGraph(ctx, taskID)
+
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return cli.errTaskNotFound(taskID)
+	case errors.Is(err, domain.ErrInvalidTaskID):
+		return cli.errInvalidTaskID(taskID)
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	cli.render(listTreeTpl, graphViews(graph), taskTable(graphTasks(graph)))
 
 	return success
 }
 
+type historyView struct {
+	At     time.Time
+	Actor  string
+	Change string
+}
+
+// unknownActor stands for the actor of changes made without "$USER" set.
+const unknownActor = "unknown"
+
+func changeString(change domain.Change) string {
+	if change.Field == domain.FieldTask {
+		return "task " + change.New
+	}
+
+	return fmt.Sprintf("%s: %q -> %q", change.Field, change.Old, change.New)
+}
+
+func (cli *Cli) History(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("history", args)
+	if !ok {
+		return code
+	}
+
+	taskID := parsed.positional[0]
+	history, err := cli.use.TaskHistory(ctx, taskID)
+
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return cli.errTaskNotFound(taskID)
+	case errors.Is(err, domain.ErrInvalidTaskID):
+		return cli.errInvalidTaskID(taskID)
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	views := make([]historyView, len(history))
+	for idx, change := range history {
+		views[idx] = historyView{
+			At:     change.At,
+			Actor:  cmp.Or(change.Actor, unknownActor),
+			Change: changeString(change),
+		}
+	}
+
+	cli.render(historyTpl, views, historyTable(history))
+
+	return success
+}
+
+type operationView struct {
+	ID      uint64
+	At      time.Time
+	Actor   string
+	Command string
+	Tasks   string
+}
+
+func operationViews(ops []*domain.Operation) []operationView {
+	views := make([]operationView, len(ops))
+	for idx, op := range ops {
+		views[idx] = operationView{
+			ID:      op.ID,
+			At:      op.At,
+			Actor:   cmp.Or(op.Actor, unknownActor),
+			Command: op.Command,
+			Tasks:   idsString(op.TaskIDs()),
+		}
+	}
+
+	return views
+}
+
+func (cli *Cli) Undo(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("undo", args)
+	if !ok {
+		return code
+	}
+
+	count := strings.Join(parsed.positional, "")
+
+	var (
+		ops []*domain.Operation
+		err error
+	)
+
+	if parsed.has("list") {
+		ops, err = cli.use.UndoList(ctx, count)
+	} else {
+		ops, err = cli.use.Undo(ctx, count)
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidCount):
+		return cli.errInvalidCount()
+	case errors.Is(err, domain.ErrNothingToUndo):
+		return cli.errNothingToUndo()
+	case errors.Is(err, domain.ErrJournalConflict):
+		return cli.errJournalConflict()
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	name := undoTaskTpl
+	if parsed.has("list") {
+		name = journalTpl
+	}
+
+	cli.render(name, operationViews(ops), operationTable(ops))
+
+	return success
+}
+
+func (cli *Cli) Redo(ctx context.Context, args []string) int {
+	_, code, ok := cli.parse("redo", args)
+	if !ok {
+		return code
+	}
+
+	op, err := cli.use.Redo(ctx)
+
+	switch {
+	case errors.Is(err, domain.ErrNothingToRedo):
+		return cli.errNothingToRedo()
+	case errors.Is(err, domain.ErrJournalConflict):
+		return cli.errJournalConflict()
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	ops := []*domain.Operation{op}
+	cli.render(redoTaskTpl, operationViews(ops), operationTable(ops))
+
+	return success
+}
+
+func taskIDs(tasks []*domain.Task) string {
+	ids := make([]uint64, len(tasks))
+	for idx, task := range tasks {
+		ids[idx] = task.ID
+	}
+
+	return idsString(ids)
+}
+
+func (cli *Cli) Trash(ctx context.Context, args []string) int {
+	_, code, ok := cli.parse("trash", args)
+	if !ok {
+		return code
+	}
+
+	tasks, err := cli.use.ListTrash(ctx)
+	if err != nil {
+		return cli.errUnexpected(err)
+	}
+
+	cli.render(trashTpl, tasks, taskTable(tasks))
+
+	return success
+}
+
+func (cli *Cli) Restore(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("restore", args)
+	if !ok {
+		return code
+	}
+
+	taskID := parsed.positional[0]
+	tasks, err := cli.use.RestoreTask(ctx, taskID)
+
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return cli.errTaskNotFound(taskID)
+	case errors.Is(err, domain.ErrTaskNotDeleted):
+		return cli.errTaskNotDeleted(taskID)
+	case errors.Is(err, domain.ErrParentDeleted):
+		return cli.errParentDeleted()
+	case errors.Is(err, domain.ErrInvalidTaskID):
+		return cli.errInvalidTaskID(taskID)
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	cli.render(restoreTaskTpl, map[string]string{"Tasks": taskIDs(tasks)}, taskTable(tasks))
+
+	return success
+}
+
+func (cli *Cli) Purge(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("purge", args)
+	if !ok {
+		return code
+	}
+
+	tasks, err := cli.use.PurgeTrash(ctx, parsed.value("older-than"))
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidPeriod):
+		return cli.errInvalidPeriod()
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	cli.render(purgeTaskTpl, map[string]string{"Tasks": taskIDs(tasks)}, taskTable(tasks))
+
+	return success
+}
+
+func (cli *Cli) Archive(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("archive", args)
+	if !ok {
+		return code
+	}
+
+	tasks, err := cli.use.ArchiveTasks(ctx, parsed.value("older-than"))
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidPeriod):
+		return cli.errInvalidPeriod()
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	cli.render(archiveTaskTpl, map[string]string{"Tasks": taskIDs(tasks)}, taskTable(tasks))
+
+	return success
+}
+
+type projectView struct {
+	Name   string
+	Counts string
+}
+
+func projectName(stats *usecases.ProjectStats) string {
+	if stats.Project == "" {
+		return "(none)"
+	}
+
+	// sub-projects are shown by their own name under the parent one
+	parts := strings.Split(stats.Project, ".")
+
+	return strings.Repeat("  ", stats.Depth) + parts[len(parts)-1]
+}
+
+func (cli *Cli) Projects(ctx context.Context, args []string) int {
+	_, code, ok := cli.parse("projects", args)
+	if !ok {
+		return code
+	}
+
+	list, err := cli.use.ListProjects(ctx)
+
+	switch {
+	case errors.Is(err, domain.ErrEmptyTasks):
+		return cli.errTaskListIsEmpty()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	names := make([]string, len(list))
+	width := 0
+
+	for idx, stats := range list {
+		names[idx] = projectName(stats)
+		width = max(width, len(names[idx]))
+	}
+
+	views := make([]projectView, len(list))
+
+	for idx, stats := range list {
+		counts := make([]string, 0, len(stats.Counts))
+		for _, status := range cli.use.Workflow().Statuses() {
+			counts = append(counts, fmt.Sprintf("%s: %d", status, stats.Counts[status]))
+		}
+
+		views[idx] = projectView{Name: fmt.Sprintf("%-*s", width, names[idx]), Counts: strings.Join(counts, ", ")}
+	}
+
+	cli.render(projectsTpl, views, projectTable(list, cli.use.Workflow().Statuses()))
+
+	return success
+}
+
+func (cli *Cli) Migrate(ctx context.Context, args []string) int {
+	parsed, code, ok := cli.parse("migrate", args)
+	if !ok {
+		return code
+	}
+
+	var (
+		schema domain.Schema
+		err    error
+	)
+
+	if parsed.has("apply") {
+		schema, err = cli.use.MigrateSchema(ctx)
+	} else {
+		schema, err = cli.use.CheckSchema(ctx)
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrSchemaTooNew):
+		return cli.errSchemaTooNew()
+	case errors.Is(err, domain.ErrStorageLocked):
+		return cli.errStorageLocked()
+	case err != nil:
+		return cli.errUnexpected(err)
+	}
+
+	data := map[string]int{"Version": schema.Version, "Latest": schema.Latest}
+
+	switch {
+	case !schema.IsOutdated():
+		cli.render(schemaUpToDateTpl, data, schemaTable(schema, false))
+	case parsed.has("apply"):
+		cli.render(schemaMigratedTpl, data, schemaTable(schema, true))
+	default:
+		cli.render(schemaOutdatedTpl, data, schemaTable(schema, false))
+
+		return failure
+	}
+
+	return success
+}
+
+func (cli *Cli) helpData() map[string]string {
+	return map[string]string{"Statuses": statusesString(cli.use.Workflow().Reachable())}
+}
+
 func (cli *Cli) Help() int {
-	_ = cli.template(helpTpl).Execute(cli.config.Output, nil)
+	data := cli.helpData()
+	cli.render(helpTpl, data, cli.messageTable(helpTpl, data))
+
+	return success
+}
+
+// helpCommand shows the help of the given command or of all of them.
+func (cli *Cli) helpCommand(_ context.Context, args []string) int {
+	parsed, code, ok := cli.parse("help", args)
+	if !ok {
+		return code
+	}
+
+	if len(parsed.positional) == 0 {
+		return cli.Help()
+	}
+
+	cmd := lookupCommand(parsed.positional[0])
+	if cmd == nil {
+		return cli.Unknown(parsed.positional[0])
+	}
+
+	return cli.commandHelp(cmd)
+}
+
+func (cli *Cli) commandHelp(cmd *command) int {
+	var usage strings.Builder
+
+	_ = cli.templates.Lookup(commandTemplate(cmd.name)).Execute(&usage, cli.helpData())
+
+	data := map[string]string{"Usage": usage.String()}
+	cli.render(commandHelpTpl, data, cli.messageTable(commandHelpTpl, data))
 
 	return success
 }
@@ -201,5 +1201,5 @@ func (cli *Cli) Usage() int {
 }
 
 func (cli *Cli) Unknown(command string) int {
-	return cli.errUnknownCommand(command)
+	return cli.errUnknownCommand(command, suggest(command))
 }
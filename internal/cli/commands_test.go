@@ -3,7 +3,10 @@ package cli_test
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,84 +17,400 @@ import (
 	"github.com/therenotomorrow/tasker/pkg/testkit"
 )
 
-const taskNotFoundTest = "task not found"
+const (
+	taskNotFoundTest  = "task not found"
+	storageLockedTest = "storage locked"
+)
+
+const dueLayout = "02 Jan 2006 15:04:05"
+
+var (
+	dueSoon = time.Now().Add(50 * time.Hour).Truncate(time.Second)
+	duePast = time.Now().Add(-49 * time.Hour).Truncate(time.Second)
+)
 
 func newMock(testName string) *storage.Mock {
 	stor := new(storage.Mock)
 	stor.SaveTaskFunc = func(ctx context.Context, task *domain.Task) (*domain.Task, error) {
-		if testName == testkit.SuccessTest {
+		switch testName {
+		case testkit.SuccessTest:
 			return task, nil
+		case storageLockedTest:
+			return nil, domain.ErrStorageLocked
 		}
 
 		return nil, testkit.ErrDummy
 	}
 	stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
 		switch testName {
-		case taskNotFoundTest:
+		case taskNotFoundTest, "dependency not found":
 			return nil, domain.ErrTaskNotFound
 		case "task is done":
 			return &domain.Task{Status: domain.StatusDone}, nil
+		case "task is blocked":
+			return &domain.Task{Status: domain.StatusTodo, BlockedBy: []uint64{2}}, nil
+		case "journal conflict":
+			return &domain.Task{Status: domain.StatusTodo, UpdatedAt: time.Now()}, nil
 		}
 
-		return new(domain.Task), nil
+		return &domain.Task{Status: domain.StatusTodo}, nil
 	}
 	stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
-		if testName == testkit.SuccessTest {
+		switch testName {
+		case testkit.SuccessTest:
 			return nil
+		case storageLockedTest:
+			return domain.ErrStorageLocked
 		}
 
 		return testkit.ErrDummy
 	}
 	stor.DeleteTaskFunc = func(ctx context.Context, task *domain.Task) error {
-		if testName == testkit.SuccessTest {
+		switch testName {
+		case testkit.SuccessTest:
 			return nil
+		case storageLockedTest:
+			return domain.ErrStorageLocked
 		}
 
 		return testkit.ErrDummy
 	}
 	stor.ListAllFunc = func(ctx context.Context) ([]*domain.Task, error) {
 		switch testName {
-		case "empty list":
+		case "plain invalid filter":
+			return nil, domain.ErrInvalidFilter
+		case "invalid filter position":
+			return nil, &domain.FilterError{Pos: 42, Reason: "unexpected end"}
+		case "empty list", "nothing to archive":
 			return make([]*domain.Task, 0), nil
-		case testkit.SuccessTest:
+		case testkit.SuccessTest, "by priority", "older than", "filter", "quoted filter", "sorted page", "reversed":
 			return []*domain.Task{
 				{
 					ID:        2,
 					Status:    domain.StatusDone,
+					Priority:  domain.PriorityHigh,
 					CreatedAt: time.Date(1992, 1, 28, 15, 45, 12, 0, time.UTC),
 					UpdatedAt: time.Now().Add(-30 * time.Minute),
 				},
 				{
 					ID:        3,
 					Status:    domain.StatusProgress,
+					Priority:  domain.PriorityLow,
 					CreatedAt: time.Date(1998, 4, 20, 1, 43, 12, 0, time.UTC),
 					UpdatedAt: time.Now().Add(-2 * time.Hour),
 				},
 				{
 					ID:        1,
 					Status:    domain.StatusTodo,
+					Priority:  domain.PriorityMedium,
 					CreatedAt: time.Date(1970, 4, 20, 0, 2, 12, 0, time.UTC),
 					UpdatedAt: time.Now(),
 				},
 				{
 					ID:        4,
 					Status:    domain.StatusDone,
+					Priority:  domain.PriorityMedium,
 					CreatedAt: time.Date(2003, 5, 8, 10, 10, 10, 0, time.UTC),
 					UpdatedAt: time.Now().Add(-25 * time.Hour),
 				},
 			}, nil
+		case taskNotFoundTest, storageLockedTest, "dependency not found", "dependency cycle", "graph", "ready":
+			return []*domain.Task{
+				{ID: 1, Description: "implement", Status: domain.StatusDone},
+				{ID: 2, Description: "review", Status: domain.StatusProgress, BlockedBy: []uint64{1}},
+				{ID: 3, Description: "deploy", Status: domain.StatusTodo, BlockedBy: []uint64{2}},
+				{ID: 4, Description: "announce", Status: domain.StatusTodo, BlockedBy: []uint64{2, 3}},
+				{
+					ID:          5,
+					Description: "docs",
+					Status:      domain.StatusTodo,
+					Priority:    domain.PriorityLow,
+					CreatedAt:   time.Date(2003, 5, 8, 10, 10, 10, 0, time.UTC),
+					UpdatedAt:   time.Now(),
+				},
+			}, nil
+		case "tree":
+			return []*domain.Task{
+				{ID: 1, Description: "release", Status: domain.StatusTodo},
+				{ID: 2, Description: "changelog", Status: domain.StatusDone, ParentID: 1},
+				{ID: 3, Description: "tag", Status: domain.StatusProgress, ParentID: 1},
+				{ID: 4, Description: "push", Status: domain.StatusTodo, ParentID: 3},
+				{ID: 5, Description: "orphan", Status: domain.StatusTodo, ParentID: 99},
+			}, nil
+		case "projects":
+			return []*domain.Task{
+				{ID: 1, Status: domain.StatusTodo, Project: "platform.auth.sso"},
+				{ID: 2, Status: domain.StatusDone, Project: "platform"},
+				{ID: 3, Status: domain.StatusProgress},
+				{ID: 4, Status: domain.StatusTodo, Project: "billing"},
+			}, nil
+		case "due dates", "overdue":
+			return []*domain.Task{
+				{
+					ID:        1,
+					Status:    domain.StatusTodo,
+					Priority:  domain.PriorityMedium,
+					CreatedAt: time.Date(1970, 4, 20, 0, 2, 12, 0, time.UTC),
+					UpdatedAt: time.Now(),
+					DueAt:     dueSoon,
+				},
+				{
+					ID:        2,
+					Status:    domain.StatusProgress,
+					Priority:  domain.PriorityHigh,
+					CreatedAt: time.Date(1992, 1, 28, 15, 45, 12, 0, time.UTC),
+					UpdatedAt: time.Now().Add(-30 * time.Minute),
+					DueAt:     duePast,
+				},
+				{
+					ID:        3,
+					Status:    domain.StatusDone,
+					Priority:  domain.PriorityLow,
+					CreatedAt: time.Date(1998, 4, 20, 1, 43, 12, 0, time.UTC),
+					UpdatedAt: time.Now().Add(-2 * time.Hour),
+					DueAt:     duePast,
+				},
+			}, nil
 		}
 
 		return nil, testkit.ErrDummy
 	}
+	stor.ListFunc = func(ctx context.Context, opts domain.ListOptions) ([]*domain.Task, error) {
+		tasks, err := stor.ListAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return opts.Apply(tasks), nil
+	}
 	stor.ListByStatusFunc = func(ctx context.Context, status domain.Status) ([]*domain.Task, error) {
 		return []*domain.Task{{
 			ID:        1,
 			Status:    domain.StatusTodo,
+			Priority:  domain.PriorityLow,
 			CreatedAt: time.Date(1992, 5, 8, 10, 10, 10, 0, time.UTC),
 			UpdatedAt: time.Now().Add(-time.Hour),
 		}}, nil
 	}
+	stor.ListByTagsFunc = func(ctx context.Context, query domain.TagQuery) ([]*domain.Task, error) {
+		return []*domain.Task{{
+			ID:        5,
+			Status:    domain.StatusProgress,
+			Priority:  domain.PriorityHigh,
+			CreatedAt: time.Date(2003, 5, 8, 10, 10, 10, 0, time.UTC),
+			UpdatedAt: time.Now().Add(-time.Hour),
+			Tags:      []string{"backend", "oncall"},
+		}}, nil
+	}
+	stor.ListChildrenFunc = func(ctx context.Context, parentID uint64) ([]*domain.Task, error) {
+		switch testName {
+		case "has subtasks", "open subtasks", "cascade":
+			if parentID != 2 {
+				return []*domain.Task{{ID: 2, Status: domain.StatusTodo, ParentID: parentID}}, nil
+			}
+		}
+
+		return make([]*domain.Task, 0), nil
+	}
+	stor.DeleteTasksFunc = func(ctx context.Context, tasks []*domain.Task) error {
+		return nil
+	}
+	stor.ListByProjectFunc = func(ctx context.Context, project string) ([]*domain.Task, error) {
+		return []*domain.Task{{
+			ID:         6,
+			Status:     domain.StatusTodo,
+			Priority:   domain.PriorityLow,
+			CreatedAt:  time.Date(2003, 5, 8, 10, 10, 10, 0, time.UTC),
+			UpdatedAt:  time.Now().Add(-time.Hour),
+			Project:    "platform.auth",
+			ParentID:   3,
+			BlockedBy:  []uint64{1, 2},
+			Recurrence: "every 2w",
+			Transition: &domain.Transition{
+				From:   domain.StatusDone,
+				To:     domain.StatusTodo,
+				Reason: "not finished yet",
+				At:     time.Date(2003, 5, 9, 10, 10, 10, 0, time.UTC),
+			},
+		}}, nil
+	}
+	stor.GetHistoryFunc = func(ctx context.Context, tid uint64) ([]domain.Change, error) {
+		switch testName {
+		case taskNotFoundTest:
+			return nil, domain.ErrTaskNotFound
+		case "history":
+			at := time.Date(2003, 5, 8, 10, 10, 10, 0, time.UTC)
+
+			return []domain.Change{
+				{At: at, Actor: "alice", Field: domain.FieldTask, New: domain.TaskAdded},
+				{At: at, Actor: "alice", Field: "description", New: "write docs"},
+				{At: at.Add(time.Hour), Actor: "bob", Field: "status", Old: "todo", New: "progress"},
+				{At: at.Add(2 * time.Hour), Field: domain.FieldTask, New: domain.TaskDeleted},
+			}, nil
+		case "no history":
+			return make([]domain.Change, 0), nil
+		}
+
+		return nil, testkit.ErrDummy
+	}
+
+	stor.GetTrashedFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+		deletedAt := time.Date(2003, 5, 8, 10, 10, 10, 0, time.UTC)
+
+		switch testName {
+		case taskNotFoundTest, "task not deleted":
+			return nil, domain.ErrTaskNotFound
+		case "unexpected error":
+			return nil, testkit.ErrDummy
+		case "parent deleted":
+			return &domain.Task{ID: tid, ParentID: 1, DeletedAt: deletedAt}, nil
+		}
+
+		return &domain.Task{ID: tid, DeletedAt: deletedAt}, nil
+	}
+	stor.ListTrashFunc = func(ctx context.Context) ([]*domain.Task, error) {
+		deletedAt := time.Date(2003, 5, 8, 10, 10, 10, 0, time.UTC)
+
+		switch testName {
+		case "unexpected error":
+			return nil, testkit.ErrDummy
+		case "empty trash", "nothing to purge":
+			return make([]*domain.Task, 0), nil
+		}
+
+		return []*domain.Task{
+			{ID: 3, Description: "old", DeletedAt: deletedAt},
+			{ID: 4, Description: "child", ParentID: 3, DeletedAt: deletedAt},
+			{ID: 5, Description: "recent", DeletedAt: deletedAt.Add(time.Hour)},
+		}, nil
+	}
+	stor.RestoreTasksFunc = func(ctx context.Context, tasks []*domain.Task) error {
+		if testName == storageLockedTest {
+			return domain.ErrStorageLocked
+		}
+
+		return nil
+	}
+	stor.PurgeTasksFunc = func(ctx context.Context, tasks []*domain.Task) error {
+		if testName == storageLockedTest {
+			return domain.ErrStorageLocked
+		}
+
+		return nil
+	}
+
+	stor.ArchiveTasksFunc = func(ctx context.Context, tasks []*domain.Task) error {
+		if testName == storageLockedTest {
+			return domain.ErrStorageLocked
+		}
+
+		return nil
+	}
+	stor.SearchFunc = func(ctx context.Context, query domain.SearchQuery) ([]*domain.Task, error) {
+		if testName == "unexpected error" {
+			return nil, testkit.ErrDummy
+		}
+
+		at := time.Date(2003, 5, 8, 10, 10, 10, 0, time.UTC)
+		tasks := []*domain.Task{
+			{
+				ID:          2,
+				Description: "deploy docs",
+				Status:      domain.StatusTodo,
+				Priority:    domain.PriorityLow,
+				CreatedAt:   at,
+				UpdatedAt:   time.Now(),
+				Transition:  &domain.Transition{From: domain.StatusDone, To: domain.StatusTodo, Reason: "the apis", At: at},
+			},
+			{
+				ID:          1,
+				Description: "Deploy the API",
+				Status:      domain.StatusTodo,
+				Priority:    domain.PriorityMedium,
+				CreatedAt:   at,
+				UpdatedAt:   time.Now(),
+			},
+		}
+
+		return slices.DeleteFunc(tasks, func(task *domain.Task) bool { return !query.Match(*task) }), nil
+	}
+	stor.ListArchivedFunc = func(ctx context.Context) ([]*domain.Task, error) {
+		if testName != "archived" {
+			return make([]*domain.Task, 0), nil
+		}
+
+		return []*domain.Task{
+			{
+				ID:          7,
+				Description: "shipped",
+				Status:      domain.StatusDone,
+				Priority:    domain.PriorityMedium,
+				CreatedAt:   time.Date(2003, 5, 8, 10, 10, 10, 0, time.UTC),
+				UpdatedAt:   time.Now(),
+				ArchivedAt:  time.Now(),
+			},
+		}, nil
+	}
+
+	stor.AppendOperationFunc = func(ctx context.Context, op *domain.Operation) (*domain.Operation, error) {
+		return op, nil
+	}
+	stor.UpdateOperationFunc = func(ctx context.Context, op *domain.Operation) error {
+		if testName == storageLockedTest {
+			return domain.ErrStorageLocked
+		}
+
+		return nil
+	}
+	stor.ListOperationsFunc = func(ctx context.Context) ([]*domain.Operation, error) {
+		switch testName {
+		case "nothing to undo":
+			return make([]*domain.Operation, 0), nil
+		case "unexpected error":
+			return nil, testkit.ErrDummy
+		}
+
+		at := time.Date(2003, 5, 8, 10, 10, 10, 0, time.UTC)
+		done := &domain.Task{ID: 1, Status: domain.StatusDone}
+		todo := &domain.Task{ID: 1, Status: domain.StatusTodo}
+
+		return []*domain.Operation{
+			{ID: 1, Command: "AddTask", At: at, Actor: "alice", Revisions: []domain.Revision{{After: todo}}},
+			{ID: 2, Command: "MarkTask", At: at.Add(time.Hour), Revisions: []domain.Revision{{Before: todo, After: todo}}},
+			{
+				ID:        3,
+				Command:   "MarkTask",
+				At:        at.Add(2 * time.Hour),
+				Revisions: []domain.Revision{{Before: todo, After: done}},
+				Undone:    true,
+			},
+		}, nil
+	}
+
+	stor.SchemaVersionFunc = func(ctx context.Context) (domain.Schema, error) {
+		switch testName {
+		case "schema too new":
+			return domain.Schema{}, domain.ErrSchemaTooNew
+		case "unexpected error":
+			return domain.Schema{}, testkit.ErrDummy
+		case "up to date":
+			return domain.Schema{Version: 2, Latest: 2}, nil
+		}
+
+		return domain.Schema{Version: 0, Latest: 2}, nil
+	}
+	stor.MigrateFunc = func(ctx context.Context) (domain.Schema, error) {
+		switch testName {
+		case storageLockedTest:
+			return domain.Schema{}, domain.ErrStorageLocked
+		case "unexpected error":
+			return domain.Schema{}, testkit.ErrDummy
+		case "up to date":
+			return domain.Schema{Version: 2, Latest: 2}, nil
+		}
+
+		return domain.Schema{Version: 0, Latest: 2}, nil
+	}
 
 	return stor
 }
@@ -127,14 +446,62 @@ func TestUnitCliAdd(t *testing.T) {
 			args: args{args: []string{"    "}},
 			want: want{code: invalid, text: `error: invalid "description" parameter, must be not empty`},
 		},
+		{
+			name: "invalid flag",
+			args: args{args: []string{"description", "--prio=high"}},
+			want: want{code: invalid, text: `error: invalid argument "--prio=high" for command "add"`},
+		},
+		{
+			name: "missing flag value",
+			args: args{args: []string{"description", "--priority"}},
+			want: want{code: invalid, text: `error: invalid argument "--priority" for command "add"`},
+		},
+		{
+			name: "invalid priority",
+			args: args{args: []string{"--priority", "urgent", "description"}},
+			want: want{code: invalid, text: `error: invalid "priority" parameter, must be one of [low medium high]`},
+		},
+		{
+			name: storageLockedTest,
+			args: args{args: []string{"description"}},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
+		},
 		{
 			name: "unexpected error",
 			args: args{args: []string{"description"}},
 			want: want{code: unknown, text: `error: unexpected behaviour "AddTask error: dummy"`},
 		},
+		{
+			name: "invalid project",
+			args: args{args: []string{"description", "--project", "platform."}},
+			want: want{
+				code: invalid,
+				text: `error: invalid "project" parameter, must be dot separated names like "platform.auth.sso"`,
+			},
+		},
 		{
 			name: testkit.SuccessTest,
-			args: args{args: []string{"description"}},
+			args: args{args: []string{"description", "--priority=high"}},
+			want: want{code: success, text: `task added successfully (ID: 0)`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"description", "--project", "platform.auth"}},
+			want: want{code: success, text: `task added successfully (ID: 0)`},
+		},
+		{
+			name: "invalid parent",
+			args: args{args: []string{"description", "--parent", "first"}},
+			want: want{code: invalid, text: `error: invalid "id" parameter, must be positive integer`},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{args: []string{"description", "--parent", "3"}},
+			want: want{code: failure, text: `error: task (ID: 3) not found`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"description", "--parent", "3"}},
 			want: want{code: success, text: `task added successfully (ID: 0)`},
 		},
 	}
@@ -197,6 +564,11 @@ func TestUnitCliUpdate(t *testing.T) {
 			args: args{args: []string{"1", "description"}},
 			want: want{code: failure, text: `error: task (ID: 1) not found`},
 		},
+		{
+			name: storageLockedTest,
+			args: args{args: []string{"1", "description"}},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
+		},
 		{
 			name: "unexpected error",
 			args: args{args: []string{"1", "description"}},
@@ -262,6 +634,11 @@ func TestUnitCliDelete(t *testing.T) {
 			args: args{args: []string{"1"}},
 			want: want{code: failure, text: `error: task (ID: 1) not found`},
 		},
+		{
+			name: storageLockedTest,
+			args: args{args: []string{"1"}},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
+		},
 		{
 			name: "unexpected error",
 			args: args{args: []string{"1"}},
@@ -272,6 +649,21 @@ func TestUnitCliDelete(t *testing.T) {
 			args: args{args: []string{"1"}},
 			want: want{code: success, text: `task deleted successfully`},
 		},
+		{
+			name: "invalid flag",
+			args: args{args: []string{"1", "--force"}},
+			want: want{code: invalid, text: `error: invalid argument "--force" for command "delete"`},
+		},
+		{
+			name: "has subtasks",
+			args: args{args: []string{"1"}},
+			want: want{code: failure, text: `error: task has subtasks, delete them first or use "--cascade"`},
+		},
+		{
+			name: "cascade",
+			args: args{args: []string{"--cascade", "1"}},
+			want: want{code: success, text: `task deleted successfully`},
+		},
 	}
 
 	for _, test := range tests {
@@ -325,7 +717,7 @@ func TestUnitCliMark(t *testing.T) {
 		{
 			name: "invalid status",
 			args: args{args: []string{"1", "invalid"}},
-			want: want{code: invalid, text: `error: invalid "status" parameter, must be one of [todo progress done]`},
+			want: want{code: invalid, text: `error: invalid "status" parameter, must be one of [todo progress done cancelled]`},
 		},
 		{
 			name: taskNotFoundTest,
@@ -335,7 +727,12 @@ func TestUnitCliMark(t *testing.T) {
 		{
 			name: "task is done",
 			args: args{args: []string{"1", "todo"}},
-			want: want{code: failure, text: `error: cannot change status for done task`},
+			want: want{code: failure, text: `error: cannot change status for closed task`},
+		},
+		{
+			name: storageLockedTest,
+			args: args{args: []string{"1", "todo"}},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
 		},
 		{
 			name: "unexpected error",
@@ -347,6 +744,29 @@ func TestUnitCliMark(t *testing.T) {
 			args: args{args: []string{"1", "todo"}},
 			want: want{code: success, text: `task status changed successfully`},
 		},
+		{
+			name: "invalid flag",
+			args: args{args: []string{"1", "done", "--cascade"}},
+			want: want{code: invalid, text: `error: invalid argument "--cascade" for command "mark"`},
+		},
+		{
+			name: "task is blocked",
+			args: args{args: []string{"1", "progress"}},
+			want: want{
+				code: failure,
+				text: `error: task is blocked by unfinished tasks, finish them first or use "--force"`,
+			},
+		},
+		{
+			name: "open subtasks",
+			args: args{args: []string{"1", "done"}},
+			want: want{code: failure, text: `error: task has open subtasks, close them first or use "--force"`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"1", "done", "--force", "--close-parent"}},
+			want: want{code: success, text: `task status changed successfully`},
+		},
 	}
 
 	for _, test := range tests {
@@ -370,6 +790,70 @@ func TestUnitCliMark(t *testing.T) {
 	}
 }
 
+func TestUnitCliMarkWorkflow(t *testing.T) {
+	t.Parallel()
+
+	flow, _ := domain.NewWorkflow(
+		[]string{"todo", "review", "done", "cancelled"},
+		[]string{"done", "cancelled"},
+		map[string][]string{"todo": {"review", "cancelled"}, "review": {"todo", "done"}},
+	)
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "invalid status",
+			args: args{args: []string{"1", "progress"}},
+			want: want{code: invalid, text: `error: invalid "status" parameter, must be one of [todo review done cancelled]`},
+		},
+		{
+			name: "invalid transition",
+			args: args{args: []string{"1", "done"}},
+			want: want{
+				code: invalid,
+				text: `error: cannot change status from "todo" to "done", must be one of [review cancelled]`,
+			},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"1", "review"}},
+			want: want{code: success, text: `task status changed successfully`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := cli.New(cli.Config{Output: buffer, Storage: newMock(test.name), Workflow: flow})
+
+			got := client.Mark(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Mark() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Mark() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
 func TestUnitCliWorkDoneShortcuts(t *testing.T) {
 	t.Parallel()
 
@@ -399,13 +883,22 @@ func TestUnitCliWorkDoneShortcuts(t *testing.T) {
 		},
 		{
 			name: "invalid task ID",
-			args: args{args: []string{"one", "todo"}},
+			args: args{args: []string{"one"}},
 			want: want{
 				code: invalid,
 				work: `error: invalid "id" parameter, must be positive integer`,
 				done: `error: invalid "id" parameter, must be positive integer`,
 			},
 		},
+		{
+			name: "extra argument",
+			args: args{args: []string{"1", "todo"}},
+			want: want{
+				code: invalid,
+				work: `error: invalid argument "todo" for command "work"`,
+				done: `error: invalid argument "todo" for command "done"`,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -442,7 +935,7 @@ func TestUnitCliWorkDoneShortcuts(t *testing.T) {
 	}
 }
 
-func TestUnitCliList(t *testing.T) {
+func TestUnitCliPrio(t *testing.T) {
 	t.Parallel()
 
 	type args struct {
@@ -460,58 +953,1618 @@ func TestUnitCliList(t *testing.T) {
 		want want
 	}{
 		{
-			name: "invalid status",
-			args: args{args: []string{"invalid"}},
-			want: want{code: invalid, text: `error: invalid "status" parameter, must be one of [todo progress done]`},
+			name: "not enough arguments",
+			args: args{args: []string{"1"}},
+			want: want{code: noArgs, text: `error: not enough arguments for command "prio"`},
 		},
 		{
-			name: "empty list",
-			args: args{args: make([]string, 0)},
-			want: want{code: failure, text: `error: task list is empty`},
+			name: "invalid task ID",
+			args: args{args: []string{"one", "high"}},
+			want: want{code: invalid, text: `error: invalid "id" parameter, must be positive integer`},
+		},
+		{
+			name: "invalid priority",
+			args: args{args: []string{"1", "urgent"}},
+			want: want{code: invalid, text: `error: invalid "priority" parameter, must be one of [low medium high]`},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{args: []string{"1", "high"}},
+			want: want{code: failure, text: `error: task (ID: 1) not found`},
+		},
+		{
+			name: storageLockedTest,
+			args: args{args: []string{"1", "high"}},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
 		},
 		{
 			name: "unexpected error",
-			args: args{args: make([]string, 0)},
-			want: want{code: unknown, text: `error: unexpected behaviour "ListTasks error: dummy"`},
+			args: args{args: []string{"1", "high"}},
+			want: want{code: unknown, text: `error: unexpected behaviour "PrioritizeTask error: dummy"`},
 		},
 		{
 			name: testkit.SuccessTest,
+			args: args{args: []string{"1", "high"}},
+			want: want{code: success, text: `task priority changed successfully`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			got := client.Prio(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Prio() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Prio() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliDue(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "not enough arguments",
+			args: args{args: []string{"1"}},
+			want: want{code: noArgs, text: `error: not enough arguments for command "due"`},
+		},
+		{
+			name: "invalid task ID",
+			args: args{args: []string{"one", "today"}},
+			want: want{code: invalid, text: `error: invalid "id" parameter, must be positive integer`},
+		},
+		{
+			name: "invalid date",
+			args: args{args: []string{"1", "someday"}},
+			want: want{
+				code: invalid,
+				text: `error: invalid "when" parameter, must be a date like ` +
+					`"2026-01-31", "today", "tomorrow", "+3d", "next fri" or "eow"`,
+			},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{args: []string{"1", "today"}},
+			want: want{code: failure, text: `error: task (ID: 1) not found`},
+		},
+		{
+			name: storageLockedTest,
+			args: args{args: []string{"1", "today"}},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: []string{"1", "today"}},
+			want: want{code: unknown, text: `error: unexpected behaviour "DueTask error: dummy"`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"1", "next", "fri"}},
+			want: want{code: success, text: `task due date changed successfully`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"1", "none"}},
+			want: want{code: success, text: `task due date changed successfully`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			got := client.Due(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Due() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Due() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliReopen(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args   []string
+		status domain.Status
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "not enough arguments",
+			args: args{args: []string{"--reason", "oops"}, status: domain.StatusDone},
+			want: want{code: noArgs, text: `error: not enough arguments for command "reopen"`},
+		},
+		{
+			name: "invalid argument",
+			args: args{args: []string{"1", "oops"}, status: domain.StatusDone},
+			want: want{code: invalid, text: `error: invalid argument "oops" for command "reopen"`},
+		},
+		{
+			name: "invalid flag",
+			args: args{args: []string{"1", "--force"}, status: domain.StatusDone},
+			want: want{code: invalid, text: `error: invalid argument "--force" for command "reopen"`},
+		},
+		{
+			name: "invalid task ID",
+			args: args{args: []string{"one"}, status: domain.StatusDone},
+			want: want{code: invalid, text: `error: invalid "id" parameter, must be positive integer`},
+		},
+		{
+			name: "task is open",
+			args: args{args: []string{"1"}, status: domain.StatusProgress},
+			want: want{code: failure, text: `error: task is not closed, nothing to reopen`},
+		},
+		{
+			name: storageLockedTest,
+			args: args{args: []string{"1"}, status: domain.StatusDone},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: []string{"1"}, status: domain.StatusDone},
+			want: want{code: unknown, text: `error: unexpected behaviour "ReopenTask error: dummy"`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"1", "--reason", "not finished yet"}, status: domain.StatusCancelled},
+			want: want{code: success, text: `task reopened successfully`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			mock := newMock(test.name)
+			mock.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+				return &domain.Task{ID: tid, Status: test.args.status}, nil
+			}
+			client := newCli(buffer, mock)
+
+			got := client.Reopen(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Reopen() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Reopen() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliCancel(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "not enough arguments",
+			args: args{args: make([]string, 0)},
+			want: want{code: noArgs, text: `error: not enough arguments for command "cancel"`},
+		},
+		{
+			name: "invalid argument",
+			args: args{args: []string{"1", "2"}},
+			want: want{code: invalid, text: `error: invalid argument "2" for command "cancel"`},
+		},
+		{
+			name: "invalid flag",
+			args: args{args: []string{"1", "--cascade"}},
+			want: want{code: invalid, text: `error: invalid argument "--cascade" for command "cancel"`},
+		},
+		{
+			name: "invalid task ID",
+			args: args{args: []string{"one"}},
+			want: want{code: invalid, text: `error: invalid "id" parameter, must be positive integer`},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{args: []string{"1"}},
+			want: want{code: failure, text: `error: task (ID: 1) not found`},
+		},
+		{
+			name: "task is done",
+			args: args{args: []string{"1"}},
+			want: want{code: failure, text: `error: cannot change status for closed task`},
+		},
+		{
+			name: "open subtasks",
+			args: args{args: []string{"1"}},
+			want: want{code: failure, text: `error: task has open subtasks, close them first or use "--force"`},
+		},
+		{
+			name: storageLockedTest,
+			args: args{args: []string{"1"}},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: []string{"1"}},
+			want: want{code: unknown, text: `error: unexpected behaviour "CancelTask error: dummy"`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"1", "--reason=duplicate of 2", "--force"}},
+			want: want{code: success, text: `task cancelled successfully`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			got := client.Cancel(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Cancel() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Cancel() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliCancelWorkflow(t *testing.T) {
+	t.Parallel()
+
+	flow, _ := domain.NewWorkflow([]string{"todo", "done"}, []string{"done"}, map[string][]string{"todo": {"done"}})
+	buffer := bytes.NewBuffer(nil)
+	client := cli.New(cli.Config{Output: buffer, Storage: newMock(t.Name()), Workflow: flow})
+
+	if got := client.Cancel(t.Context(), []string{"1"}); got != invalid {
+		t.Errorf("Cancel() got = %v, want = %v", got, invalid)
+	}
+
+	want := `error: cannot change status from "todo" to "cancelled", must be one of [done]`
+	if text := buffer.String(); text != want {
+		t.Errorf("Cancel() got = %v, want = %v", text, want)
+	}
+}
+
+func TestUnitCliRecur(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "not enough arguments",
+			args: args{args: []string{"1"}},
+			want: want{code: noArgs, text: `error: not enough arguments for command "recur"`},
+		},
+		{
+			name: "invalid flag",
+			args: args{args: []string{"1", "daily", "--every"}},
+			want: want{code: invalid, text: `error: invalid argument "--every" for command "recur"`},
+		},
+		{
+			name: "invalid argument",
+			args: args{args: []string{"1", "daily", "--stop"}},
+			want: want{code: invalid, text: `error: invalid argument "daily" for command "recur"`},
+		},
+		{
+			name: "invalid task ID",
+			args: args{args: []string{"one", "daily"}},
+			want: want{code: invalid, text: `error: invalid "id" parameter, must be positive integer`},
+		},
+		{
+			name: "invalid rule",
+			args: args{args: []string{"1", "sometimes"}},
+			want: want{
+				code: invalid,
+				text: `error: invalid "rule" parameter, must be one of "daily", "weekly", "weekly:mon,thu", ` +
+					`"monthly", "monthly:15" or "every 2w"`,
+			},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{args: []string{"1", "daily"}},
+			want: want{code: failure, text: `error: task (ID: 1) not found`},
+		},
+		{
+			name: storageLockedTest,
+			args: args{args: []string{"1", "daily"}},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: []string{"1", "daily"}},
+			want: want{code: unknown, text: `error: unexpected behaviour "RecurTask error: dummy"`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"1", "every", "2w"}},
+			want: want{code: success, text: `task recurrence changed successfully`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"1", "--stop"}},
+			want: want{code: success, text: `task recurrence changed successfully`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			got := client.Recur(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Recur() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Recur() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliTag(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "not enough arguments",
+			args: args{args: []string{"1"}},
+			want: want{code: noArgs, text: `error: not enough arguments for command "tag"`},
+		},
+		{
+			name: "invalid argument",
+			args: args{args: []string{"1", "+infra", "backend"}},
+			want: want{code: invalid, text: `error: invalid argument "backend" for command "tag"`},
+		},
+		{
+			name: "invalid task ID",
+			args: args{args: []string{"one", "+infra"}},
+			want: want{code: invalid, text: `error: invalid "id" parameter, must be positive integer`},
+		},
+		{
+			name: "invalid tag",
+			args: args{args: []string{"1", "+2fa"}},
+			want: want{
+				code: invalid,
+				text: `error: invalid "tag" parameter, must start with a letter ` +
+					`and contain only letters, digits, "-" or "_"`,
+			},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{args: []string{"1", "+infra"}},
+			want: want{code: failure, text: `error: task (ID: 1) not found`},
+		},
+		{
+			name: storageLockedTest,
+			args: args{args: []string{"1", "+infra"}},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: []string{"1", "+infra"}},
+			want: want{code: unknown, text: `error: unexpected behaviour "TagTask error: dummy"`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"1", "+infra", "-backend"}},
+			want: want{code: success, text: `task tags changed successfully`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			got := client.Tag(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Tag() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Tag() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliList(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "invalid status",
+			args: args{args: []string{"invalid"}},
+			want: want{code: invalid, text: `error: invalid "status" parameter, must be one of [todo progress done cancelled]`},
+		},
+		{
+			name: "extra argument",
+			args: args{args: []string{"todo", "foo", "bar", "--view", "compact"}},
+			want: want{code: invalid, text: `error: invalid argument "foo" for command "list"`},
+		},
+		{
+			name: "empty list",
+			args: args{args: make([]string, 0)},
+			want: want{code: failure, text: `error: task list is empty`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: make([]string, 0)},
+			want: want{code: unknown, text: `error: unexpected behaviour "ListTasks error: dummy"`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: make([]string, 0)},
+			want: want{code: success, text: `
+---- id: 1
+description | 
+status      | todo
+priority    | medium
+created at  | 20 Apr 1970 00:02:12
+last update | 0 minute(s) ago
+
+---- id: 2
+description | 
+status      | done
+priority    | high
+created at  | 28 Jan 1992 15:45:12
+last update | 30 minute(s) ago
+
+---- id: 3
+description | 
+status      | progress
+priority    | low
+created at  | 20 Apr 1998 01:43:12
+last update | 2 hour(s) ago
+
+---- id: 4
+description | 
+status      | done
+priority    | medium
+created at  | 08 May 2003 10:10:10
+last update | 1 day(s) ago
+`},
+		},
+		{
+			name: "invalid priority",
+			args: args{args: []string{"--priority", "urgent"}},
+			want: want{code: invalid, text: `error: invalid "priority" parameter, must be one of [low medium high]`},
+		},
+		{
+			name: "invalid flag",
+			args: args{args: []string{"--by-status"}},
+			want: want{code: invalid, text: `error: invalid argument "--by-status" for command "list"`},
+		},
+		{
+			name: "archived and all",
+			args: args{args: []string{"--archived", "--all"}},
+			want: want{code: invalid, text: `error: flags "--archived" and "--all" cannot be used together`},
+		},
+		{
+			name: "filter",
+			args: args{args: []string{"status:done,todo", "prio>=high", "--by-priority"}},
+			want: want{code: success, text: `
+---- id: 2
+description | 
+status      | done
+priority    | high
+created at  | 28 Jan 1992 15:45:12
+last update | 30 minute(s) ago
+`},
+		},
+		{
+			name: "quoted filter",
+			args: args{args: []string{"status:done,todo prio>=high", "--by-priority"}},
+			want: want{code: success, text: `
+---- id: 2
+description | 
+status      | done
+priority    | high
+created at  | 28 Jan 1992 15:45:12
+last update | 30 minute(s) ago
+`},
+		},
+		{
+			name: "filter and tags",
+			args: args{args: []string{"+backend", "prio>=medium"}},
+			want: want{code: success, text: `
+---- id: 5
+description | 
+status      | progress
+priority    | high
+created at  | 08 May 2003 10:10:10
+last update | 1 hour(s) ago
+tags        | +backend +oncall
+`},
+		},
+		{
+			name: "filter and status",
+			args: args{args: []string{"todo", "prio<=medium"}},
+			want: want{code: success, text: `
+---- id: 1
+description | 
+status      | todo
+priority    | low
+created at  | 08 May 1992 10:10:10
+last update | 1 hour(s) ago
+`},
+		},
+		{
+			name: "sort and by priority",
+			args: args{args: []string{"--sort", "id", "--by-priority"}},
+			want: want{code: invalid, text: `error: flags "--sort" and "--by-priority" cannot be used together`},
+		},
+		{
+			name: "invalid sort",
+			args: args{args: []string{"--sort", "title"}},
+			want: want{code: invalid, text: `error: invalid "sort" parameter, must be one of ` +
+				`[id created updated status priority due] with optional ":asc" or ":desc"`},
+		},
+		{
+			name: "invalid limit",
+			args: args{args: []string{"--limit", "all"}},
+			want: want{code: invalid, text: `error: invalid "limit" parameter, must be positive integer`},
+		},
+		{
+			name: "invalid offset",
+			args: args{args: []string{"--offset", "-1"}},
+			want: want{code: invalid, text: `error: invalid "offset" parameter, must be zero or positive integer`},
+		},
+		{
+			name: "sorted page",
+			args: args{args: []string{"--sort", "created:desc", "--limit", "2", "--offset=1"}},
+			want: want{code: success, text: `
+---- id: 3
+description | 
+status      | progress
+priority    | low
+created at  | 20 Apr 1998 01:43:12
+last update | 2 hour(s) ago
+
+---- id: 2
+description | 
+status      | done
+priority    | high
+created at  | 28 Jan 1992 15:45:12
+last update | 30 minute(s) ago
+`},
+		},
+		{
+			name: "reversed",
+			args: args{args: []string{"--sort", "id", "--limit", "2", "--reverse", "--tree"}},
+			want: want{code: success, text: `
+2 [done] 
+1 [todo] `},
+		},
+		{
+			name: "invalid filter",
+			args: args{args: []string{`desc~"a b" -tag +ok`}},
+			want: want{code: invalid, text: `error: invalid filter, expected operator after "tag"
+  desc~"a b" -tag +ok
+                 ^`},
+		},
+		{
+			name: "plain invalid filter",
+			args: args{args: []string{"desc~a"}},
+			want: want{code: invalid, text: `error: invalid filter`},
+		},
+		{
+			name: "invalid filter position",
+			args: args{args: []string{"desc~a"}},
+			want: want{code: invalid, text: `error: invalid filter, unexpected end
+  desc~a
+        ^`},
+		},
+		{
+			name: "archived",
+			args: args{args: []string{"--archived"}},
+			want: want{code: success, text: `
+---- id: 7 (archived)
+description | shipped
+status      | done
+priority    | medium
+created at  | 08 May 2003 10:10:10
+last update | 0 minute(s) ago
+`},
+		},
+		{
+			name: "by priority",
+			args: args{args: []string{"--by-priority"}},
+			want: want{code: success, text: `
+---- id: 2
+description | 
+status      | done
+priority    | high
+created at  | 28 Jan 1992 15:45:12
+last update | 30 minute(s) ago
+
+---- id: 1
+description | 
+status      | todo
+priority    | medium
+created at  | 20 Apr 1970 00:02:12
+last update | 0 minute(s) ago
+
+---- id: 4
+description | 
+status      | done
+priority    | medium
+created at  | 08 May 2003 10:10:10
+last update | 1 day(s) ago
+
+---- id: 3
+description | 
+status      | progress
+priority    | low
+created at  | 20 Apr 1998 01:43:12
+last update | 2 hour(s) ago
+`},
+		},
+		{
+			name: "invalid due before",
+			args: args{args: []string{"--due-before", "someday"}},
+			want: want{
+				code: invalid,
+				text: `error: invalid "when" parameter, must be a date like ` +
+					`"2026-01-31", "today", "tomorrow", "+3d", "next fri" or "eow"`,
+			},
+		},
+		{
+			name: "due dates",
+			args: args{args: make([]string, 0)},
+			want: want{code: success, text: fmt.Sprintf(`
+---- id: 1
+description | 
+status      | todo
+priority    | medium
+created at  | 20 Apr 1970 00:02:12
+last update | 0 minute(s) ago
+due at      | %s (in 2 day(s))
+
+---- id: 2 (overdue)
+description | 
+status      | progress
+priority    | high
+created at  | 28 Jan 1992 15:45:12
+last update | 30 minute(s) ago
+due at      | %s (overdue by 2 day(s))
+
+---- id: 3
+description | 
+status      | done
+priority    | low
+created at  | 20 Apr 1998 01:43:12
+last update | 2 hour(s) ago
+due at      | %s (2 day(s) ago)
+`, dueSoon.Format(dueLayout), duePast.Format(dueLayout), duePast.Format(dueLayout))},
+		},
+		{
+			name: "overdue",
+			args: args{args: []string{"--overdue"}},
+			want: want{code: success, text: fmt.Sprintf(`
+---- id: 2 (overdue)
+description | 
+status      | progress
+priority    | high
+created at  | 28 Jan 1992 15:45:12
+last update | 30 minute(s) ago
+due at      | %s (overdue by 2 day(s))
+`, duePast.Format(dueLayout))},
+		},
+		{
+			name: "invalid tag",
+			args: args{args: []string{"+backend", "-2fa"}},
+			want: want{
+				code: invalid,
+				text: `error: invalid "tag" parameter, must start with a letter ` +
+					`and contain only letters, digits, "-" or "_"`,
+			},
+		},
+		{
+			name: "with tags",
+			args: args{args: []string{"progress", "+backend", "-infra"}},
+			want: want{code: success, text: `
+---- id: 5
+description | 
+status      | progress
+priority    | high
+created at  | 08 May 2003 10:10:10
+last update | 1 hour(s) ago
+tags        | +backend +oncall
+`},
+		},
+		{
+			name: "invalid project",
+			args: args{args: []string{"--project", "platform..auth"}},
+			want: want{
+				code: invalid,
+				text: `error: invalid "project" parameter, must be dot separated names like "platform.auth.sso"`,
+			},
+		},
+		{
+			name: "with project",
+			args: args{args: []string{"--project", "platform"}},
+			want: want{code: success, text: `
+---- id: 6
+description | 
+status      | todo
+priority    | low
+project     | platform.auth
+parent      | 3
+blocked by  | 1, 2
+recurrence  | every 2w
+transition  | done -> todo at 09 May 2003 10:10:10, not finished yet
+created at  | 08 May 2003 10:10:10
+last update | 1 hour(s) ago
+`},
+		},
+		{
+			name: "ready",
+			args: args{args: []string{"--ready"}},
+			want: want{code: success, text: `
+---- id: 5
+description | docs
+status      | todo
+priority    | low
+created at  | 08 May 2003 10:10:10
+last update | 0 minute(s) ago
+`},
+		},
+		{
+			name: "tree",
+			args: args{args: []string{"--tree"}},
+			want: want{code: success, text: `
+1 [todo] release
+├── 2 [done] changelog
+└── 3 [progress] tag
+    └── 4 [todo] push
+5 [todo] orphan`},
+		},
+		{
+			name: "with status",
+			args: args{args: []string{"todo"}},
+			want: want{code: success, text: `
+---- id: 1
+description | 
+status      | todo
+priority    | low
+created at  | 08 May 1992 10:10:10
+last update | 1 hour(s) ago
+`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			got := client.List(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("List() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("List() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliDepend(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "not enough arguments",
+			args: args{args: []string{"1"}},
+			want: want{code: noArgs, text: `error: not enough arguments for command "depend"`},
+		},
+		{
+			name: "invalid argument",
+			args: args{args: []string{"1", "2", "--on", "3"}},
+			want: want{code: invalid, text: `error: invalid argument "2" for command "depend"`},
+		},
+		{
+			name: "invalid flag",
+			args: args{args: []string{"1", "--after", "3"}},
+			want: want{code: invalid, text: `error: invalid argument "--after" for command "depend"`},
+		},
+		{
+			name: "invalid task ID",
+			args: args{args: []string{"one", "--on", "2"}},
+			want: want{code: invalid, text: `error: invalid "id" parameter, must be positive integer`},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{args: []string{"42", "--on", "2"}},
+			want: want{code: failure, text: `error: task (ID: 42) not found`},
+		},
+		{
+			name: "dependency not found",
+			args: args{args: []string{"2", "--on", "42"}},
+			want: want{code: failure, text: `error: task (ID: 42) not found`},
+		},
+		{
+			name: "dependency cycle",
+			args: args{args: []string{"1", "--on", "4"}},
+			want: want{
+				code: failure,
+				text: `error: task (ID: 1) cannot depend on task (ID: 4), it makes a dependency cycle`,
+			},
+		},
+		{
+			name: storageLockedTest,
+			args: args{args: []string{"5", "--on", "4"}},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: []string{"5", "--on", "4"}},
+			want: want{code: unknown, text: `error: unexpected behaviour "DependTask error: dummy"`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"1", "--on", "2"}},
+			want: want{code: success, text: `task dependencies changed successfully`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"1", "--on", "2", "--remove"}},
+			want: want{code: success, text: `task dependencies changed successfully`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			got := client.Depend(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Depend() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Depend() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliGraph(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "not enough arguments",
+			args: args{args: make([]string, 0)},
+			want: want{code: noArgs, text: `error: not enough arguments for command "graph"`},
+		},
+		{
+			name: "invalid task ID",
+			args: args{args: []string{"one"}},
+			want: want{code: invalid, text: `error: invalid "id" parameter, must be positive integer`},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{args: []string{"42"}},
+			want: want{code: failure, text: `error: task (ID: 42) not found`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: []string{"1"}},
+			want: want{code: unknown, text: `error: unexpected behaviour "DependencyGraph error: dummy"`},
+		},
+		{
+			name: "graph",
+			args: args{args: []string{"4"}},
+			want: want{code: success, text: `
+4 [todo] announce (blocked)
+├── 2 [progress] review
+│   └── 1 [done] implement
+└── 3 [todo] deploy (blocked)
+    └── 2 [progress] review
+        └── 1 [done] implement`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			got := client.Graph(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Graph() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Graph() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliHistory(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "not enough arguments",
+			args: args{args: make([]string, 0)},
+			want: want{code: noArgs, text: `error: not enough arguments for command "history"`},
+		},
+		{
+			name: "invalid task ID",
+			args: args{args: []string{"one"}},
+			want: want{code: invalid, text: `error: invalid "id" parameter, must be positive integer`},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{args: []string{"42"}},
+			want: want{code: failure, text: `error: task (ID: 42) not found`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: []string{"1"}},
+			want: want{code: unknown, text: `error: unexpected behaviour "TaskHistory error: dummy"`},
+		},
+		{
+			name: "history",
+			args: args{args: []string{"1"}},
+			want: want{code: success, text: `
+08 May 2003 10:10:10 | alice | task added
+08 May 2003 10:10:10 | alice | description: "" -> "write docs"
+08 May 2003 11:10:10 | bob | status: "todo" -> "progress"
+08 May 2003 12:10:10 | unknown | task deleted`},
+		},
+		{
+			name: "no history",
+			args: args{args: []string{"1"}},
+			want: want{code: success, text: `no changes recorded for the task`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			got := client.History(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("History() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("History() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliUndo(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "invalid flag",
+			args: args{args: []string{"--all"}},
+			want: want{code: invalid, text: `error: invalid argument "--all" for command "undo"`},
+		},
+		{
+			name: "too many arguments",
+			args: args{args: []string{"1", "2"}},
+			want: want{code: invalid, text: `error: invalid argument "2" for command "undo"`},
+		},
+		{
+			name: "invalid count",
+			args: args{args: []string{"zero"}},
+			want: want{code: invalid, text: `error: invalid "n" parameter, must be positive integer`},
+		},
+		{
+			name: "nothing to undo",
+			args: args{args: make([]string, 0)},
+			want: want{code: failure, text: `error: nothing to undo`},
+		},
+		{
+			name: "journal conflict",
 			args: args{args: make([]string, 0)},
+			want: want{
+				code: failure,
+				text: `error: tasks were changed since the operation, it cannot be replayed safely`,
+			},
+		},
+		{
+			name: storageLockedTest,
+			args: args{args: make([]string, 0)},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: make([]string, 0)},
+			want: want{code: unknown, text: `error: unexpected behaviour "Undo error: dummy"`},
+		},
+		{
+			name: "list",
+			args: args{args: []string{"--list"}},
 			want: want{code: success, text: `
----- id: 1
-description | 
-status      | todo
-created at  | 20 Apr 1970 00:02:12
-last update | 0 minute(s) ago
+2 | 08 May 2003 11:10:10 | unknown | MarkTask (ID: 1)
+1 | 08 May 2003 10:10:10 | alice | AddTask (ID: 1)`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"1"}},
+			want: want{code: success, text: `operations undone successfully:
+2 | 08 May 2003 11:10:10 | unknown | MarkTask (ID: 1)`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			mock := newMock(test.name)
+			client := cli.New(cli.Config{Output: buffer, Storage: mock, Journal: mock})
+
+			got := client.Undo(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Undo() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Undo() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliRedo(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "invalid argument",
+			args: args{args: []string{"1"}},
+			want: want{code: invalid, text: `error: invalid argument "1" for command "redo"`},
+		},
+		{
+			name: "nothing to undo",
+			args: args{args: make([]string, 0)},
+			want: want{code: failure, text: `error: nothing to redo`},
+		},
+		{
+			name: "journal conflict",
+			args: args{args: make([]string, 0)},
+			want: want{
+				code: failure,
+				text: `error: tasks were changed since the operation, it cannot be replayed safely`,
+			},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: make([]string, 0)},
+			want: want{code: unknown, text: `error: unexpected behaviour "Redo error: dummy"`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: make([]string, 0)},
+			want: want{code: success, text: `operation redone successfully:
+3 | 08 May 2003 12:10:10 | unknown | MarkTask (ID: 1)`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			mock := newMock(test.name)
+			client := cli.New(cli.Config{Output: buffer, Storage: mock, Journal: mock})
+
+			got := client.Redo(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Redo() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Redo() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliTrash(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "invalid argument",
+			args: args{args: []string{"all"}},
+			want: want{code: invalid, text: `error: invalid argument "all" for command "trash"`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: make([]string, 0)},
+			want: want{code: unknown, text: `error: unexpected behaviour "ListTrash error: dummy"`},
+		},
+		{
+			name: "empty trash",
+			args: args{args: make([]string, 0)},
+			want: want{code: success, text: `trash is empty`},
+		},
+		{
+			name: "trash",
+			args: args{args: make([]string, 0)},
+			want: want{code: success, text: `
+5 | 08 May 2003 11:10:10 | recent
+3 | 08 May 2003 10:10:10 | old
+4 | 08 May 2003 10:10:10 | child`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			got := client.Trash(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Trash() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Trash() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliRestore(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "not enough arguments",
+			args: args{args: make([]string, 0)},
+			want: want{code: noArgs, text: `error: not enough arguments for command "restore"`},
+		},
+		{
+			name: "invalid task ID",
+			args: args{args: []string{"one"}},
+			want: want{code: invalid, text: `error: invalid "id" parameter, must be positive integer`},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{args: []string{"42"}},
+			want: want{code: failure, text: `error: task (ID: 42) not found`},
+		},
+		{
+			name: "task not deleted",
+			args: args{args: []string{"1"}},
+			want: want{code: failure, text: `error: task (ID: 1) is not deleted, nothing to restore`},
+		},
+		{
+			name: "parent deleted",
+			args: args{args: []string{"2"}},
+			want: want{code: failure, text: `error: parent task is deleted, restore it first`},
+		},
+		{
+			name: storageLockedTest,
+			args: args{args: []string{"3"}},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: []string{"3"}},
+			want: want{code: unknown, text: `error: unexpected behaviour "RestoreTask error: dummy"`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"3"}},
+			want: want{code: success, text: `tasks restored successfully (ID: 3, 4)`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			got := client.Restore(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Restore() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Restore() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliPurge(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "invalid flag",
+			args: args{args: []string{"--all"}},
+			want: want{code: invalid, text: `error: invalid argument "--all" for command "purge"`},
+		},
+		{
+			name: "invalid argument",
+			args: args{args: []string{"30d"}},
+			want: want{code: invalid, text: `error: invalid argument "30d" for command "purge"`},
+		},
+		{
+			name: "invalid period",
+			args: args{args: []string{"--older-than", "month"}},
+			want: want{code: invalid, text: `error: invalid "older-than" parameter, must be a period like "12h", "30d" or "2w"`},
+		},
+		{
+			name: storageLockedTest,
+			args: args{args: make([]string, 0)},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: make([]string, 0)},
+			want: want{code: unknown, text: `error: unexpected behaviour "PurgeTrash error: dummy"`},
+		},
+		{
+			name: "nothing to purge",
+			args: args{args: make([]string, 0)},
+			want: want{code: success, text: `nothing to purge`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"--older-than", "30d"}},
+			want: want{code: success, text: `tasks purged successfully (ID: 3, 4, 5)`},
+		},
+	}
 
----- id: 2
-description | 
-status      | done
-created at  | 28 Jan 1992 15:45:12
-last update | 30 minute(s) ago
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
 
----- id: 3
-description | 
-status      | progress
-created at  | 20 Apr 1998 01:43:12
-last update | 2 hour(s) ago
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
 
----- id: 4
-description | 
+			got := client.Purge(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Purge() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Purge() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliSearch(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "not enough arguments",
+			args: args{args: make([]string, 0)},
+			want: want{code: noArgs, text: `error: not enough arguments for command "search"`},
+		},
+		{
+			name: "invalid flag",
+			args: args{args: []string{"api", "--tree"}},
+			want: want{code: invalid, text: `error: invalid argument "--tree" for command "search"`},
+		},
+		{
+			name: "archived and all",
+			args: args{args: []string{"api", "--archived", "--all"}},
+			want: want{code: invalid, text: `error: flags "--archived" and "--all" cannot be used together`},
+		},
+		{
+			name: "invalid query",
+			args: args{args: []string{`"api`}},
+			want: want{code: invalid, text: `error: invalid "query" parameter, must be words, "prefix*", ` +
+				`"quoted phrases" or "-negated" terms`},
+		},
+		{
+			name: "nothing found",
+			args: args{args: []string{"deploy", "-docs", "-api"}},
+			want: want{code: failure, text: `error: task list is empty`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: []string{"api"}},
+			want: want{code: unknown, text: `error: unexpected behaviour "SearchTasks error: dummy"`},
+		},
+		{
+			name: "archived",
+			args: args{args: []string{"--archived", "SHIP*"}},
+			want: want{code: success, text: `
+---- id: 7 (archived)
+description | *ship*ped
 status      | done
+priority    | medium
 created at  | 08 May 2003 10:10:10
-last update | 1 day(s) ago
+last update | 0 minute(s) ago
 `},
 		},
 		{
-			name: "with status",
-			args: args{args: []string{"todo"}},
+			name: testkit.SuccessTest,
+			args: args{args: []string{"deploy", "the api"}},
 			want: want{code: success, text: `
 ---- id: 1
-description | 
+description | *Deploy* *the API*
 status      | todo
-created at  | 08 May 1992 10:10:10
-last update | 1 hour(s) ago
+priority    | medium
+created at  | 08 May 2003 10:10:10
+last update | 0 minute(s) ago
+
+---- id: 2
+description | *deploy* docs
+status      | todo
+priority    | low
+transition  | done -> todo at 08 May 2003 10:10:10, *the api*s
+created at  | 08 May 2003 10:10:10
+last update | 0 minute(s) ago
 `},
 		},
 	}
@@ -524,19 +2577,266 @@ last update | 1 hour(s) ago
 			buffer := bytes.NewBuffer(nil)
 			client := newCli(buffer, newMock(test.name))
 
-			got := client.List(ctx, test.args.args)
+			got := client.Search(ctx, test.args.args)
 
 			if got != test.want.code {
-				t.Errorf("List() got = %v, want = %v", got, test.want.code)
+				t.Errorf("Search() got = %v, want = %v", got, test.want.code)
 			}
 
 			if text := buffer.String(); text != test.want.text {
-				t.Errorf("List() got = %v, want = %v", text, test.want.text)
+				t.Errorf("Search() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliArchive(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "invalid flag",
+			args: args{args: []string{"--all"}},
+			want: want{code: invalid, text: `error: invalid argument "--all" for command "archive"`},
+		},
+		{
+			name: "invalid argument",
+			args: args{args: []string{"14d"}},
+			want: want{code: invalid, text: `error: invalid argument "14d" for command "archive"`},
+		},
+		{
+			name: "invalid period",
+			args: args{args: []string{"--older-than", "month"}},
+			want: want{code: invalid, text: `error: invalid "older-than" parameter, must be a period like "12h", "30d" or "2w"`},
+		},
+		{
+			name: storageLockedTest,
+			args: args{args: make([]string, 0)},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: make([]string, 0)},
+			want: want{code: unknown, text: `error: unexpected behaviour "ArchiveTasks error: dummy"`},
+		},
+		{
+			name: "nothing to archive",
+			args: args{args: make([]string, 0)},
+			want: want{code: success, text: `nothing to archive`},
+		},
+		{
+			name: "older than",
+			args: args{args: []string{"--older-than", "1d"}},
+			want: want{code: success, text: `tasks archived successfully (ID: 4)`},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: make([]string, 0)},
+			want: want{code: success, text: `tasks archived successfully (ID: 2, 4)`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			got := client.Archive(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Archive() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Archive() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliProjects(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "invalid argument",
+			args: args{args: []string{"platform"}},
+			want: want{code: invalid, text: `error: invalid argument "platform" for command "projects"`},
+		},
+		{
+			name: "empty list",
+			args: args{args: make([]string, 0)},
+			want: want{code: failure, text: `error: task list is empty`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: make([]string, 0)},
+			want: want{code: unknown, text: `error: unexpected behaviour "ListProjects error: dummy"`},
+		},
+		{
+			name: "projects",
+			args: args{args: make([]string, 0)},
+			want: want{code: success, text: `
+billing  | todo: 1, progress: 0, done: 0, cancelled: 0
+platform | todo: 1, progress: 0, done: 1, cancelled: 0
+  auth   | todo: 1, progress: 0, done: 0, cancelled: 0
+    sso  | todo: 1, progress: 0, done: 0, cancelled: 0
+(none)   | todo: 0, progress: 1, done: 0, cancelled: 0`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			got := client.Projects(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Projects() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Projects() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliMigrate(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "unknown flag",
+			args: args{args: []string{"--force"}},
+			want: want{code: invalid, text: `error: invalid argument "--force" for command "migrate"`},
+		},
+		{
+			name: "extra argument",
+			args: args{args: []string{"now"}},
+			want: want{code: invalid, text: `error: invalid argument "now" for command "migrate"`},
+		},
+		{
+			name: "conflicting flags",
+			args: args{args: []string{"--check", "--apply"}},
+			want: want{code: invalid, text: `error: flags "--check" and "--apply" cannot be used together`},
+		},
+		{
+			name: "schema too new",
+			args: args{args: make([]string, 0)},
+			want: want{code: failure, text: `error: task file schema is newer than supported, upgrade tasker`},
+		},
+		{
+			name: storageLockedTest,
+			args: args{args: []string{"--apply"}},
+			want: want{code: failure, text: `error: task file is locked by another tasker, try again later`},
+		},
+		{
+			name: "unexpected error",
+			args: args{args: []string{"--check"}},
+			want: want{code: unknown, text: `error: unexpected behaviour "CheckSchema error: dummy"`},
+		},
+		{
+			name: "up to date",
+			args: args{args: []string{"--apply"}},
+			want: want{code: success, text: `task file schema is up to date (version: 2)`},
+		},
+		{
+			name: "outdated",
+			args: args{args: []string{"--check"}},
+			want: want{
+				code: failure,
+				text: `task file schema is outdated (version: 0, latest: 2), run "tasker migrate --apply"`,
+			},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{args: []string{"--apply"}},
+			want: want{code: success, text: `task file schema migrated successfully (version: 0 -> 2)`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			got := client.Migrate(ctx, test.args.args)
+
+			if got != test.want.code {
+				t.Errorf("Migrate() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Migrate() got = %v, want = %v", text, test.want.text)
 			}
 		})
 	}
 }
 
+func TestUnitCliHelpWorkflow(t *testing.T) {
+	t.Parallel()
+
+	flow, _ := domain.NewWorkflow([]string{"todo", "done"}, []string{"done"}, map[string][]string{"todo": {"done"}})
+	buffer := bytes.NewBuffer(nil)
+	client := cli.New(cli.Config{Output: buffer, Storage: newMock(t.Name()), Workflow: flow})
+
+	_ = client.Help()
+
+	if want := `set a new status for the task ("todo" or "done")`; !strings.Contains(buffer.String(), want) {
+		t.Errorf("Help() got = %v, want = %v", buffer.String(), want)
+	}
+}
+
 func TestUnitCliHelp(t *testing.T) {
 	t.Parallel()
 
@@ -550,22 +2850,87 @@ func TestUnitCliHelp(t *testing.T) {
 	}
 
 	want := `manage tasks with ease from the command line:
- - tasker add "description"
-      add a new task with the given description
+ - tasker add "description" [--priority <level>] [--project <name>] [--parent <id>]
+      add a new task with the given description and priority ("low", "medium" by default, or "high"),
+      "+tag" words of the description become the task tags, projects are dotted like "platform.auth",
+      the task with a parent becomes its subtask
  - tasker update <id> "new description"
       update the description of an existing task by its ID
- - tasker delete <id>
-      delete the task with the specified ID
- - tasker mark <id> <status>
-      set a new status for the task ("todo", "progress", or "done")
+ - tasker delete <id> [--cascade]
+      move the task with the specified ID to the trash, the task with subtasks is deleted only together with them
+ - tasker mark <id> <status> [--force] [--close-parent]
+      set a new status for the task ("todo", "progress", or "done"), the task with open subtasks
+      is done and the blocked task is started or done only by force, the parent could be done
+      together with its last open subtask
  - tasker work <id>
       shortcut to mark the task as "progress"
- - tasker done <id>
-      shortcut to mark the task as "done"
- - tasker list [status]
-      list all tasks, if a status is provided, only tasks with that status will be shown
- - tasker help
-      show this help message and exit`
+ - tasker done <id> [--force] [--close-parent]
+      shortcut to mark the task as "done", or as the first terminal status of the workflow
+ - tasker reopen <id> [--reason <text>]
+      bring the done or cancelled task back to work, the reason is kept with the task
+ - tasker cancel <id> [--reason <text>] [--force]
+      close the task without doing it, the task with open subtasks is cancelled only by force
+ - tasker prio <id> <level>
+      set a new priority for the task ("low", "medium", or "high")
+ - tasker due <id> <when>
+      set the due date like "2026-01-31", "today", "tomorrow", "+3d", "next fri", "eow" or "none" to remove it
+ - tasker tag <id> [+tag...] [-tag...]
+      add or remove the task tags
+ - tasker recur <id> <rule> | --stop
+      repeat the task "daily", "weekly", "weekly:mon,thu", "monthly", "monthly:15" or "every 2w",
+      the next instance is added when the task is done, or stop repeating it
+ - tasker depend <id> --on <other> [--remove]
+      make the task blocked by the other one until it is done, or remove the dependency
+ - tasker graph <id>
+      show the chain of tasks the task depends on
+ - tasker history <id>
+      show who changed the task, when and how, the deleted task keeps its history too
+ - tasker undo [n] [--list]
+      revert the last n (one by default) commands changed tasks, unless the tasks are changed since,
+      or list what would be reverted (all the commands without n)
+ - tasker redo
+      make again the command reverted the last
+ - tasker trash
+      show the deleted tasks from the latest deleted
+ - tasker restore <id>
+      bring the deleted task back from the trash together with the subtasks deleted along with it
+ - tasker purge [--older-than <period>]
+      remove the deleted tasks for good, or only the ones deleted before the period like "30d", "2w" or "12h"
+ - tasker archive [--older-than <period>]
+      move the done tasks to the archive file, or only the ones done before the period like "14d",
+      the archived tasks are still found by their IDs
+ - tasker list [status|<filter>...] [+tag...] [-tag...] [--priority <level>] [--by-priority] [--overdue]
+        [--due-before <when>] [--project <name>] [--ready] [--tree] [--archived|--all]
+        [--sort <field>[:asc|:desc]] [--limit <n>] [--offset <n>] [--reverse]
+        [--view block|table|compact] [--columns <name,...>]
+      list all tasks, if a status is provided, only tasks with that status will be shown,
+      show only tasks having all "+tag" tags and none of "-tag" tags, or of the project with its sub-projects,
+      filter tasks by the priority level, overdue or due date, or show the most important tasks first,
+      show only todo tasks that are not blocked, show subtasks under their parents as a tree,
+      list the archived tasks instead of the active ones or both of them,
+      the filter matches tasks by all of its terms like 'status:todo,progress prio>=high -tag:infra desc~"deploy"',
+      the fields are status, prio, id, parent, created, updated, due, desc, tag and project, the operators are
+      ":" (any of the values), "~" (contains) and "<", "<=", ">", ">=", "-" before the term negates it,
+      sort tasks by id, created, updated, status, priority or due, the recently updated first by default,
+      show only "limit" tasks after skipping "offset" ones and turn the shown ones over with "--reverse",
+      show the tasks as blocks, the aligned table or one line each with the columns of id, status, priority,
+      age, due, project, tags, parent and description, the table is chosen by the columns alone
+ - tasker search <query> [--archived|--all]
+      find tasks by their description or the reason of the transition ignoring the case, the query has words,
+      "prefix*" of words, "quoted phrases" and "-negated" terms, the best matches are shown first and marked
+ - tasker projects
+      show the projects tree with the number of tasks in every status
+ - tasker migrate [--check|--apply]
+      check the schema version of the task file or upgrade it to the latest one
+ - tasker help [<command>]
+      show this help message, or the one of the command like "tasker <command> --help", and exit
+ - tasker <command> --file <path>
+      use the task file instead of the one from "$TASKER_FILE" or "tasker.json"
+ - tasker <command> --output <format>
+      print the result of the command as "json", "jsonl", "csv" or "tsv" instead of the text, the columns
+      are stable, the error is the object with its code, message and exit code
+ - tasker <command> --color always|never|auto
+      paint statuses, ids and errors, by default only for the terminal and without "$NO_COLOR"`
 
 	if text := buffer.String(); text != want {
 		t.Errorf("Help() got = %v, want = %v", text, want)
@@ -585,22 +2950,87 @@ func TestUnitCliUsage(t *testing.T) {
 	}
 
 	want := `manage tasks with ease from the command line:
- - tasker add "description"
-      add a new task with the given description
+ - tasker add "description" [--priority <level>] [--project <name>] [--parent <id>]
+      add a new task with the given description and priority ("low", "medium" by default, or "high"),
+      "+tag" words of the description become the task tags, projects are dotted like "platform.auth",
+      the task with a parent becomes its subtask
  - tasker update <id> "new description"
       update the description of an existing task by its ID
- - tasker delete <id>
-      delete the task with the specified ID
- - tasker mark <id> <status>
-      set a new status for the task ("todo", "progress", or "done")
+ - tasker delete <id> [--cascade]
+      move the task with the specified ID to the trash, the task with subtasks is deleted only together with them
+ - tasker mark <id> <status> [--force] [--close-parent]
+      set a new status for the task ("todo", "progress", or "done"), the task with open subtasks
+      is done and the blocked task is started or done only by force, the parent could be done
+      together with its last open subtask
  - tasker work <id>
       shortcut to mark the task as "progress"
- - tasker done <id>
-      shortcut to mark the task as "done"
- - tasker list [status]
-      list all tasks, if a status is provided, only tasks with that status will be shown
- - tasker help
-      show this help message and exit`
+ - tasker done <id> [--force] [--close-parent]
+      shortcut to mark the task as "done", or as the first terminal status of the workflow
+ - tasker reopen <id> [--reason <text>]
+      bring the done or cancelled task back to work, the reason is kept with the task
+ - tasker cancel <id> [--reason <text>] [--force]
+      close the task without doing it, the task with open subtasks is cancelled only by force
+ - tasker prio <id> <level>
+      set a new priority for the task ("low", "medium", or "high")
+ - tasker due <id> <when>
+      set the due date like "2026-01-31", "today", "tomorrow", "+3d", "next fri", "eow" or "none" to remove it
+ - tasker tag <id> [+tag...] [-tag...]
+      add or remove the task tags
+ - tasker recur <id> <rule> | --stop
+      repeat the task "daily", "weekly", "weekly:mon,thu", "monthly", "monthly:15" or "every 2w",
+      the next instance is added when the task is done, or stop repeating it
+ - tasker depend <id> --on <other> [--remove]
+      make the task blocked by the other one until it is done, or remove the dependency
+ - tasker graph <id>
+      show the chain of tasks the task depends on
+ - tasker history <id>
+      show who changed the task, when and how, the deleted task keeps its history too
+ - tasker undo [n] [--list]
+      revert the last n (one by default) commands changed tasks, unless the tasks are changed since,
+      or list what would be reverted (all the commands without n)
+ - tasker redo
+      make again the command reverted the last
+ - tasker trash
+      show the deleted tasks from the latest deleted
+ - tasker restore <id>
+      bring the deleted task back from the trash together with the subtasks deleted along with it
+ - tasker purge [--older-than <period>]
+      remove the deleted tasks for good, or only the ones deleted before the period like "30d", "2w" or "12h"
+ - tasker archive [--older-than <period>]
+      move the done tasks to the archive file, or only the ones done before the period like "14d",
+      the archived tasks are still found by their IDs
+ - tasker list [status|<filter>...] [+tag...] [-tag...] [--priority <level>] [--by-priority] [--overdue]
+        [--due-before <when>] [--project <name>] [--ready] [--tree] [--archived|--all]
+        [--sort <field>[:asc|:desc]] [--limit <n>] [--offset <n>] [--reverse]
+        [--view block|table|compact] [--columns <name,...>]
+      list all tasks, if a status is provided, only tasks with that status will be shown,
+      show only tasks having all "+tag" tags and none of "-tag" tags, or of the project with its sub-projects,
+      filter tasks by the priority level, overdue or due date, or show the most important tasks first,
+      show only todo tasks that are not blocked, show subtasks under their parents as a tree,
+      list the archived tasks instead of the active ones or both of them,
+      the filter matches tasks by all of its terms like 'status:todo,progress prio>=high -tag:infra desc~"deploy"',
+      the fields are status, prio, id, parent, created, updated, due, desc, tag and project, the operators are
+      ":" (any of the values), "~" (contains) and "<", "<=", ">", ">=", "-" before the term negates it,
+      sort tasks by id, created, updated, status, priority or due, the recently updated first by default,
+      show only "limit" tasks after skipping "offset" ones and turn the shown ones over with "--reverse",
+      show the tasks as blocks, the aligned table or one line each with the columns of id, status, priority,
+      age, due, project, tags, parent and description, the table is chosen by the columns alone
+ - tasker search <query> [--archived|--all]
+      find tasks by their description or the reason of the transition ignoring the case, the query has words,
+      "prefix*" of words, "quoted phrases" and "-negated" terms, the best matches are shown first and marked
+ - tasker projects
+      show the projects tree with the number of tasks in every status
+ - tasker migrate [--check|--apply]
+      check the schema version of the task file or upgrade it to the latest one
+ - tasker help [<command>]
+      show this help message, or the one of the command like "tasker <command> --help", and exit
+ - tasker <command> --file <path>
+      use the task file instead of the one from "$TASKER_FILE" or "tasker.json"
+ - tasker <command> --output <format>
+      print the result of the command as "json", "jsonl", "csv" or "tsv" instead of the text, the columns
+      are stable, the error is the object with its code, message and exit code
+ - tasker <command> --color always|never|auto
+      paint statuses, ids and errors, by default only for the terminal and without "$NO_COLOR"`
 
 	if text := buffer.String(); text != want {
 		t.Errorf("Usage() got = %v, want = %v", text, want)
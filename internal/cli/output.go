@@ -0,0 +1,368 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+	"github.com/therenotomorrow/tasker/internal/usecases"
+)
+
+const (
+	formatText  = "text"
+	formatJSON  = "json"
+	formatJSONL = "jsonl"
+	formatCSV   = "csv"
+	formatTSV   = "tsv"
+)
+
+func allFormats() []string {
+	return []string{formatText, formatJSON, formatJSONL, formatCSV, formatTSV}
+}
+
+// codes of the errors made by the arguments, not by the tasks.
+const (
+	codeNotEnoughArgs    = "notEnoughArgs"
+	codeUnknownCommand   = "unknownCommand"
+	codeInvalidArgument  = "invalidArgument"
+	codeConflictingFlags = "conflictingFlags"
+	codeInvalidOutput    = "invalidOutput"
+	codeInvalidView      = "invalidView"
+	codeInvalidColumns   = "invalidColumns"
+	codeInvalidColor     = "invalidColor"
+	codeUnexpected       = "unexpected"
+)
+
+func errorCodes() map[int]string {
+	return map[int]string{
+		notEnoughArgsTpl:      codeNotEnoughArgs,
+		unknownCommandTpl:     codeUnknownCommand,
+		invalidTaskIDTpl:      string(domain.ErrInvalidTaskID),
+		invalidDescriptionTpl: string(domain.ErrEmptyDescription),
+		invalidStatusTpl:      string(domain.ErrInvalidStatus),
+		taskNotFoundTpl:       string(domain.ErrTaskNotFound),
+		unexpectedErrorTpl:    codeUnexpected,
+		taskAlreadyDoneTpl:    string(domain.ErrTaskAlreadyDone),
+		taskListIsEmptyTpl:    string(domain.ErrEmptyTasks),
+		storageLockedTpl:      string(domain.ErrStorageLocked),
+		invalidFlagTpl:        codeInvalidArgument,
+		conflictingFlagsTpl:   codeConflictingFlags,
+		schemaTooNewTpl:       string(domain.ErrSchemaTooNew),
+		invalidPriorityTpl:    string(domain.ErrInvalidPriority),
+		invalidDateTpl:        string(domain.ErrInvalidDate),
+		invalidTagTpl:         string(domain.ErrInvalidTag),
+		invalidProjectTpl:     string(domain.ErrInvalidProject),
+		openSubtasksTpl:       string(domain.ErrOpenSubtasks),
+		hasSubtasksTpl:        string(domain.ErrHasSubtasks),
+		taskBlockedTpl:        string(domain.ErrTaskBlocked),
+		dependencyCycleTpl:    string(domain.ErrDependencyCycle),
+		invalidRecurrenceTpl:  string(domain.ErrInvalidRecurrence),
+		invalidTransitionTpl:  string(domain.ErrInvalidTransition),
+		taskNotClosedTpl:      string(domain.ErrTaskNotClosed),
+		invalidCountTpl:       string(domain.ErrInvalidCount),
+		nothingToUndoTpl:      string(domain.ErrNothingToUndo),
+		nothingToRedoTpl:      string(domain.ErrNothingToRedo),
+		journalConflictTpl:    string(domain.ErrJournalConflict),
+		taskNotDeletedTpl:     string(domain.ErrTaskNotDeleted),
+		parentDeletedTpl:      string(domain.ErrParentDeleted),
+		invalidPeriodTpl:      string(domain.ErrInvalidPeriod),
+		invalidQueryTpl:       string(domain.ErrInvalidQuery),
+		invalidFilterTpl:      string(domain.ErrInvalidFilter),
+		invalidSortTpl:        string(domain.ErrInvalidSort),
+		invalidLimitTpl:       string(domain.ErrInvalidLimit),
+		invalidOffsetTpl:      string(domain.ErrInvalidOffset),
+		invalidOutputTpl:      codeInvalidOutput,
+		invalidViewTpl:        codeInvalidView,
+		invalidColumnsTpl:     codeInvalidColumns,
+		invalidColorTpl:       codeInvalidColor,
+	}
+}
+
+// table is the result of the command in the stable schema, every row has a value for every column.
+type table struct {
+	columns []string
+	rows    [][]any
+}
+
+type problem struct {
+	code    string
+	message string
+}
+
+// render writes the template in the text format, or keeps the result for the others.
+func (cli *Cli) render(name int, data any, result table) {
+	cli.stream = cli.config.Output
+
+	if cli.format == formatText {
+		_ = cli.template(name).Execute(cli.config.Output, data)
+
+		return
+	}
+
+	cli.result = result
+}
+
+// fail writes the error template in the text format, or keeps the error with the code for the others.
+func (cli *Cli) fail(name int, data any) {
+	cli.stream = cli.config.ErrOutput
+
+	if cli.format == formatText {
+		_ = cli.template(name).Execute(cli.config.ErrOutput, data)
+
+		return
+	}
+
+	var message strings.Builder
+
+	_ = cli.template(name).Execute(&message, data)
+	cli.problem = &problem{code: errorCodes()[name], message: strings.TrimPrefix(message.String(), "error: ")}
+}
+
+// flush writes the kept result of the command, or its error with the exit status to the error output.
+func (cli *Cli) flush(status int) int {
+	if cli.format == formatText {
+		return status
+	}
+
+	result, single, out := cli.result, false, cli.config.Output
+	if cli.problem != nil {
+		result = table{
+			columns: []string{"code", "exitCode", "message"},
+			rows:    [][]any{{cli.problem.code, status, cli.problem.message}},
+		}
+		single, out = true, cli.config.ErrOutput
+	}
+
+	switch cli.format {
+	case formatJSON:
+		_ = writeJSON(out, result, single)
+	case formatJSONL:
+		_ = writeJSONL(out, result)
+	case formatCSV:
+		_ = writeCSV(out, result, ',')
+	case formatTSV:
+		_ = writeCSV(out, result, '\t')
+	}
+
+	return status
+}
+
+func writeJSON(out io.Writer, result table, single bool) error {
+	objects := make([]object, len(result.rows))
+	for idx, row := range result.rows {
+		objects[idx] = object{columns: result.columns, values: row}
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if single {
+		return encoder.Encode(objects[0])
+	}
+
+	return encoder.Encode(objects)
+}
+
+func writeJSONL(out io.Writer, result table) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+
+	for _, row := range result.rows {
+		err := encoder.Encode(object{columns: result.columns, values: row})
+		if err != nil {
+			return fmt.Errorf("jsonl error: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func writeCSV(out io.Writer, result table, comma rune) error {
+	writer := csv.NewWriter(out)
+	writer.Comma = comma
+
+	_ = writer.Write(result.columns)
+
+	for _, row := range result.rows {
+		cells := make([]string, len(row))
+		for idx, value := range row {
+			cells[idx] = cell(value)
+		}
+
+		_ = writer.Write(cells)
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// object is the row of the table keeping the order of the columns in JSON.
+type object struct {
+	columns []string
+	values  []any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+
+	buffer.WriteByte('{')
+
+	for idx, column := range o.columns {
+		if idx > 0 {
+			buffer.WriteByte(',')
+		}
+
+		_ = encoder.Encode(column)
+		buffer.Truncate(buffer.Len() - 1)
+		buffer.WriteByte(':')
+
+		err := encoder.Encode(jsonValue(o.values[idx]))
+		if err != nil {
+			return nil, fmt.Errorf("json error: %w", err)
+		}
+
+		buffer.Truncate(buffer.Len() - 1)
+	}
+
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+// jsonValue gives null for the missing time and empty lists instead of null.
+func jsonValue(value any) any {
+	switch value := value.(type) {
+	case time.Time:
+		if value.IsZero() {
+			return nil
+		}
+
+		return value.Format(time.RFC3339)
+	case []string:
+		return append(make([]string, 0, len(value)), value...)
+	case []uint64:
+		return append(make([]uint64, 0, len(value)), value...)
+	default:
+		return value
+	}
+}
+
+// cell gives the missing values as empty ones and joins lists by spaces.
+func cell(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		if value.IsZero() {
+			return ""
+		}
+
+		return value.Format(time.RFC3339)
+	case []string:
+		return strings.Join(value, " ")
+	case []uint64:
+		strs := make([]string, len(value))
+		for idx, tid := range value {
+			strs[idx] = strconv.FormatUint(tid, 10)
+		}
+
+		return strings.Join(strs, " ")
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// optionalID gives nothing for the missing ID.
+func optionalID(tid uint64) any {
+	if tid == 0 {
+		return nil
+	}
+
+	return tid
+}
+
+func taskTable(tasks []*domain.Task) table {
+	rows := make([][]any, len(tasks))
+	for idx, task := range tasks {
+		rows[idx] = []any{
+			task.ID, task.Description, task.Status, task.Priority, task.Project, optionalID(task.ParentID),
+			task.Tags, task.BlockedBy, task.Recurrence,
+			task.CreatedAt, task.UpdatedAt, task.DueAt, task.DeletedAt, task.ArchivedAt,
+		}
+	}
+
+	return table{
+		columns: []string{
+			"id", "description", "status", "priority", "project", "parent",
+			"tags", "blockedBy", "recurrence",
+			"createdAt", "updatedAt", "dueAt", "deletedAt", "archivedAt",
+		},
+		rows: rows,
+	}
+}
+
+func idTable(tid uint64) table {
+	return table{columns: []string{"id"}, rows: [][]any{{tid}}}
+}
+
+func historyTable(history []domain.Change) table {
+	rows := make([][]any, len(history))
+	for idx, change := range history {
+		rows[idx] = []any{change.At, change.Actor, change.Field, change.Old, change.New}
+	}
+
+	return table{columns: []string{"at", "actor", "field", "old", "new"}, rows: rows}
+}
+
+func operationTable(ops []*domain.Operation) table {
+	rows := make([][]any, len(ops))
+	for idx, op := range ops {
+		rows[idx] = []any{op.ID, op.At, op.Actor, op.Command, op.TaskIDs()}
+	}
+
+	return table{columns: []string{"id", "at", "actor", "command", "tasks"}, rows: rows}
+}
+
+func projectTable(list []*usecases.ProjectStats, statuses []domain.Status) table {
+	columns := []string{"project"}
+	for _, status := range statuses {
+		columns = append(columns, string(status))
+	}
+
+	rows := make([][]any, len(list))
+	for idx, stats := range list {
+		rows[idx] = []any{stats.Project}
+		for _, status := range statuses {
+			rows[idx] = append(rows[idx], stats.Counts[status])
+		}
+	}
+
+	return table{columns: columns, rows: rows}
+}
+
+func schemaTable(schema domain.Schema, migrated bool) table {
+	return table{
+		columns: []string{"version", "latest", "migrated"},
+		rows:    [][]any{{schema.Version, schema.Latest, migrated}},
+	}
+}
+
+// messageTable keeps the text of the template for the results having no schema.
+func (cli *Cli) messageTable(name int, data any) table {
+	var message strings.Builder
+
+	_ = cli.template(name).Execute(&message, data)
+
+	return table{columns: []string{"message"}, rows: [][]any{{message.String()}}}
+}
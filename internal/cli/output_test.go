@@ -0,0 +1,148 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/cli"
+	"github.com/therenotomorrow/tasker/internal/domain"
+	"github.com/therenotomorrow/tasker/internal/storage"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the output")
+
+func newOutputMock() *storage.Mock {
+	day := time.Date(2026, 1, 31, 9, 30, 0, 0, time.UTC)
+	tasks := []*domain.Task{
+		{
+			ID: 1, Description: `deploy "api", then docs`, Status: domain.StatusProgress, Priority: domain.PriorityHigh,
+			CreatedAt: day, UpdatedAt: day.Add(time.Hour), DueAt: day.AddDate(0, 0, 2),
+			Tags: []string{"infra", "ops"}, Project: "platform.api", Recurrence: "weekly:mon",
+		},
+		{
+			ID: 2, Description: "write docs", Status: domain.StatusTodo, Priority: domain.PriorityLow,
+			CreatedAt: day.Add(time.Minute), UpdatedAt: day.Add(time.Minute), ParentID: 1, BlockedBy: []uint64{1},
+		},
+	}
+
+	stor := new(storage.Mock)
+	stor.ListFunc = func(ctx context.Context, opts domain.ListOptions) ([]*domain.Task, error) {
+		return tasks, nil
+	}
+	stor.ListAllFunc = func(ctx context.Context) ([]*domain.Task, error) {
+		return tasks, nil
+	}
+	stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+		return nil, domain.ErrTaskNotFound
+	}
+	stor.GetHistoryFunc = func(ctx context.Context, tid uint64) ([]domain.Change, error) {
+		return []domain.Change{
+			{At: day, Actor: "alice", Field: domain.FieldTask, New: domain.TaskAdded},
+			{At: day.Add(time.Hour), Field: "status", Old: "todo", New: "progress"},
+		}, nil
+	}
+
+	return stor
+}
+
+func TestUnitCliOutput(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code   int
+		golden string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{name: "list json", args: args{args: []string{"list", "--output", "json"}}, want: want{golden: "list.json"}},
+		{name: "list jsonl", args: args{args: []string{"--output=jsonl", "list"}}, want: want{golden: "list.jsonl"}},
+		{name: "list csv", args: args{args: []string{"list", "--tree", "--output=csv"}}, want: want{golden: "list.csv"}},
+		{name: "list tsv", args: args{args: []string{"--output", "tsv", "list"}}, want: want{golden: "list.tsv"}},
+		{
+			name: "history json",
+			args: args{args: []string{"history", "1", "--output", "json"}},
+			want: want{golden: "history.json"},
+		},
+		{name: "projects csv", args: args{args: []string{"projects", "--output=csv"}}, want: want{golden: "projects.csv"}},
+		{
+			name: "not found json",
+			args: args{args: []string{"--output", "json", "mark", "9", "done"}},
+			want: want{code: failure, golden: "not-found.json"},
+		},
+		{
+			name: "not found jsonl",
+			args: args{args: []string{"mark", "9", "done", "--output=jsonl"}},
+			want: want{code: failure, golden: "not-found.jsonl"},
+		},
+		{
+			name: "not found csv",
+			args: args{args: []string{"mark", "9", "done", "--output=csv"}},
+			want: want{code: failure, golden: "not-found.csv"},
+		},
+		{
+			name: "invalid argument json",
+			args: args{args: []string{"list", "--output=json", "--size"}},
+			want: want{code: invalid, golden: "invalid-argument.json"},
+		},
+		{
+			name: "invalid filter json",
+			args: args{args: []string{"list", "--output=json", "prio>urgent"}},
+			want: want{code: invalid, golden: "invalid-filter.json"},
+		},
+		{
+			name: "unknown command tsv",
+			args: args{args: []string{"--output=tsv", "run"}},
+			want: want{code: failure, golden: "unknown-command.tsv"},
+		},
+		{
+			name: "invalid output",
+			args: args{args: []string{"list", "--output=xml"}},
+			want: want{code: invalid, golden: "invalid-output.txt"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.NewBuffer(nil)
+			tasker := cli.New(cli.Config{Output: buffer, Storage: newOutputMock()})
+
+			code := tasker.Dispatch(t.Context(), test.args.args)
+			if code != test.want.code {
+				t.Errorf("Dispatch() code = %v, want = %v", code, test.want.code)
+			}
+
+			golden := filepath.Join("..", "..", "test", "data", "output", test.want.golden)
+
+			if *update {
+				err := os.WriteFile(golden, buffer.Bytes(), 0o600)
+				if err != nil {
+					t.Fatalf("WriteFile() error = %v, want = %v", err, nil)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v, want = %v", err, nil)
+			}
+
+			if got := buffer.String(); got != string(want) {
+				t.Errorf("Dispatch() got = %v, want = %v", got, string(want))
+			}
+		})
+	}
+}
@@ -0,0 +1,510 @@
+package cli
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"unicode/utf8"
+)
+
+// manyArgs is the maxArgs of the command taking any number of positional arguments.
+const manyArgs = -1
+
+// command declares the positional arguments and flags of the tasker command,
+// the arguments are checked and the help is generated by the declaration.
+type command struct {
+	name string
+	// usage is the synopsis of the arguments and flags shown in the help.
+	usage     string
+	about     []string
+	flags     flagSpec
+	conflicts [][2]string
+	minArgs   int
+	maxArgs   int
+	run       func(cli *Cli, ctx context.Context, args []string) int
+}
+
+// commands lists the tasker commands in the order of the help.
+func commands() []command {
+	return []command{
+		{
+			name:  "add",
+			usage: `"description" [--priority <level>] [--project <name>] [--parent <id>]`,
+			about: []string{
+				`add a new task with the given description and priority ("low", "medium" by default, or "high"),`,
+				`"+tag" words of the description become the task tags, projects are dotted like "platform.auth",`,
+				`the task with a parent becomes its subtask`,
+			},
+			flags:     flagSpec{"priority": true, "project": true, "parent": true},
+			conflicts: nil,
+			minArgs:   oneArg,
+			maxArgs:   oneArg,
+			run:       (*Cli).Add,
+		},
+		{
+			name:      "update",
+			usage:     `<id> "new description"`,
+			about:     []string{`update the description of an existing task by its ID`},
+			flags:     flagSpec{},
+			conflicts: nil,
+			minArgs:   twoArgs,
+			maxArgs:   twoArgs,
+			run:       (*Cli).Update,
+		},
+		{
+			name:  "delete",
+			usage: `<id> [--cascade]`,
+			about: []string{
+				`move the task with the specified ID to the trash, the task with subtasks is deleted only together with them`,
+			},
+			flags:     flagSpec{"cascade": false},
+			conflicts: nil,
+			minArgs:   oneArg,
+			maxArgs:   oneArg,
+			run:       (*Cli).Delete,
+		},
+		{
+			name:  "mark",
+			usage: `<id> <status> [--force] [--close-parent]`,
+			about: []string{
+				`set a new status for the task ({{ .Statuses }}), the task with open subtasks`,
+				`is done and the blocked task is started or done only by force, the parent could be done`,
+				`together with its last open subtask`,
+			},
+			flags:     flagSpec{"force": false, "close-parent": false},
+			conflicts: nil,
+			minArgs:   twoArgs,
+			maxArgs:   twoArgs,
+			run:       (*Cli).Mark,
+		},
+		{
+			name:      "work",
+			usage:     `<id>`,
+			about:     []string{`shortcut to mark the task as "progress"`},
+			flags:     flagSpec{},
+			conflicts: nil,
+			minArgs:   oneArg,
+			maxArgs:   oneArg,
+			run:       (*Cli).Work,
+		},
+		{
+			name:      "done",
+			usage:     `<id> [--force] [--close-parent]`,
+			about:     []string{`shortcut to mark the task as "done", or as the first terminal status of the workflow`},
+			flags:     flagSpec{"force": false, "close-parent": false},
+			conflicts: nil,
+			minArgs:   oneArg,
+			maxArgs:   oneArg,
+			run:       (*Cli).Done,
+		},
+		{
+			name:      "reopen",
+			usage:     `<id> [--reason <text>]`,
+			about:     []string{`bring the done or cancelled task back to work, the reason is kept with the task`},
+			flags:     flagSpec{"reason": true},
+			conflicts: nil,
+			minArgs:   oneArg,
+			maxArgs:   oneArg,
+			run:       (*Cli).Reopen,
+		},
+		{
+			name:      "cancel",
+			usage:     `<id> [--reason <text>] [--force]`,
+			about:     []string{`close the task without doing it, the task with open subtasks is cancelled only by force`},
+			flags:     flagSpec{"reason": true, "force": false},
+			conflicts: nil,
+			minArgs:   oneArg,
+			maxArgs:   oneArg,
+			run:       (*Cli).Cancel,
+		},
+		{
+			name:      "prio",
+			usage:     `<id> <level>`,
+			about:     []string{`set a new priority for the task ("low", "medium", or "high")`},
+			flags:     flagSpec{},
+			conflicts: nil,
+			minArgs:   twoArgs,
+			maxArgs:   twoArgs,
+			run:       (*Cli).Prio,
+		},
+		{
+			name:  "due",
+			usage: `<id> <when>`,
+			about: []string{
+				`set the due date like "2026-01-31", "today", "tomorrow", "+3d", "next fri", "eow" or "none" to remove it`,
+			},
+			flags:     flagSpec{},
+			conflicts: nil,
+			minArgs:   twoArgs,
+			maxArgs:   manyArgs,
+			run:       (*Cli).Due,
+		},
+		{
+			name:      "tag",
+			usage:     `<id> [+tag...] [-tag...]`,
+			about:     []string{`add or remove the task tags`},
+			flags:     flagSpec{},
+			conflicts: nil,
+			minArgs:   twoArgs,
+			maxArgs:   manyArgs,
+			run:       (*Cli).Tag,
+		},
+		{
+			name:  "recur",
+			usage: `<id> <rule> | --stop`,
+			about: []string{
+				`repeat the task "daily", "weekly", "weekly:mon,thu", "monthly", "monthly:15" or "every 2w",`,
+				`the next instance is added when the task is done, or stop repeating it`,
+			},
+			flags:     flagSpec{"stop": false},
+			conflicts: nil,
+			minArgs:   oneArg,
+			maxArgs:   manyArgs,
+			run:       (*Cli).Recur,
+		},
+		{
+			name:      "depend",
+			usage:     `<id> --on <other> [--remove]`,
+			about:     []string{`make the task blocked by the other one until it is done, or remove the dependency`},
+			flags:     flagSpec{"on": true, "remove": false},
+			conflicts: nil,
+			minArgs:   oneArg,
+			maxArgs:   oneArg,
+			run:       (*Cli).Depend,
+		},
+		{
+			name:      "graph",
+			usage:     `<id>`,
+			about:     []string{`show the chain of tasks the task depends on`},
+			flags:     flagSpec{},
+			conflicts: nil,
+			minArgs:   oneArg,
+			maxArgs:   oneArg,
+			run:       (*Cli).Graph,
+		},
+		{
+			name:      "history",
+			usage:     `<id>`,
+			about:     []string{`show who changed the task, when and how, the deleted task keeps its history too`},
+			flags:     flagSpec{},
+			conflicts: nil,
+			minArgs:   oneArg,
+			maxArgs:   oneArg,
+			run:       (*Cli).History,
+		},
+		{
+			name:  "undo",
+			usage: `[n] [--list]`,
+			about: []string{
+				`revert the last n (one by default) commands changed tasks, unless the tasks are changed since,`,
+				`or list what would be reverted (all the commands without n)`,
+			},
+			flags:     flagSpec{"list": false},
+			conflicts: nil,
+			minArgs:   0,
+			maxArgs:   oneArg,
+			run:       (*Cli).Undo,
+		},
+		{
+			name:      "redo",
+			usage:     ``,
+			about:     []string{`make again the command reverted the last`},
+			flags:     flagSpec{},
+			conflicts: nil,
+			minArgs:   0,
+			maxArgs:   0,
+			run:       (*Cli).Redo,
+		},
+		{
+			name:      "trash",
+			usage:     ``,
+			about:     []string{`show the deleted tasks from the latest deleted`},
+			flags:     flagSpec{},
+			conflicts: nil,
+			minArgs:   0,
+			maxArgs:   0,
+			run:       (*Cli).Trash,
+		},
+		{
+			name:      "restore",
+			usage:     `<id>`,
+			about:     []string{`bring the deleted task back from the trash together with the subtasks deleted along with it`},
+			flags:     flagSpec{},
+			conflicts: nil,
+			minArgs:   oneArg,
+			maxArgs:   oneArg,
+			run:       (*Cli).Restore,
+		},
+		{
+			name:  "purge",
+			usage: `[--older-than <period>]`,
+			about: []string{
+				`remove the deleted tasks for good, or only the ones deleted before the period like "30d", "2w" or "12h"`,
+			},
+			flags:     flagSpec{"older-than": true},
+			conflicts: nil,
+			minArgs:   0,
+			maxArgs:   0,
+			run:       (*Cli).Purge,
+		},
+		{
+			name:  "archive",
+			usage: `[--older-than <period>]`,
+			about: []string{
+				`move the done tasks to the archive file, or only the ones done before the period like "14d",`,
+				`the archived tasks are still found by their IDs`,
+			},
+			flags:     flagSpec{"older-than": true},
+			conflicts: nil,
+			minArgs:   0,
+			maxArgs:   0,
+			run:       (*Cli).Archive,
+		},
+		{
+			name: "list",
+			usage: `[status|<filter>...] [+tag...] [-tag...] [--priority <level>] [--by-priority] [--overdue]
+        [--due-before <when>] [--project <name>] [--ready] [--tree] [--archived|--all]
+        [--sort <field>[:asc|:desc]] [--limit <n>] [--offset <n>] [--reverse]
+        [--view block|table|compact] [--columns <name,...>]`,
+			about: []string{
+				`list all tasks, if a status is provided, only tasks with that status will be shown,`,
+				`show only tasks having all "+tag" tags and none of "-tag" tags, or of the project with its sub-projects,`,
+				`filter tasks by the priority level, overdue or due date, or show the most important tasks first,`,
+				`show only todo tasks that are not blocked, show subtasks under their parents as a tree,`,
+				`list the archived tasks instead of the active ones or both of them,`,
+				`the filter matches tasks by all of its terms like 'status:todo,progress prio>=high -tag:infra desc~"deploy"',`,
+				`the fields are status, prio, id, parent, created, updated, due, desc, tag and project, the operators are`,
+				`":" (any of the values), "~" (contains) and "<", "<=", ">", ">=", "-" before the term negates it,`,
+				`sort tasks by id, created, updated, status, priority or due, the recently updated first by default,`,
+				`show only "limit" tasks after skipping "offset" ones and turn the shown ones over with "--reverse",`,
+				`show the tasks as blocks, the aligned table or one line each with the columns of id, status, priority,`,
+				`age, due, project, tags, parent and description, the table is chosen by the columns alone`,
+			},
+			flags: flagSpec{
+				"priority": true, "by-priority": false, "project": true, "ready": false,
+				"overdue": false, "due-before": true, "tree": false, "archived": false, "all": false,
+				"sort": true, "limit": true, "offset": true, "reverse": false, "view": true, "columns": true,
+			},
+			conflicts: [][2]string{
+				{"archived", "all"}, {"sort", "by-priority"}, {"tree", "view"}, {"tree", "columns"},
+			},
+			minArgs: 0,
+			maxArgs: manyArgs,
+			run:     (*Cli).List,
+		},
+		{
+			name:  "search",
+			usage: `<query> [--archived|--all]`,
+			about: []string{
+				`find tasks by their description or the reason of the transition ignoring the case, the query has words,`,
+				`"prefix*" of words, "quoted phrases" and "-negated" terms, the best matches are shown first and marked`,
+			},
+			flags:     flagSpec{"archived": false, "all": false},
+			conflicts: [][2]string{{"archived", "all"}},
+			minArgs:   oneArg,
+			maxArgs:   manyArgs,
+			run:       (*Cli).Search,
+		},
+		{
+			name:      "projects",
+			usage:     ``,
+			about:     []string{`show the projects tree with the number of tasks in every status`},
+			flags:     flagSpec{},
+			conflicts: nil,
+			minArgs:   0,
+			maxArgs:   0,
+			run:       (*Cli).Projects,
+		},
+		{
+			name:      "migrate",
+			usage:     `[--check|--apply]`,
+			about:     []string{`check the schema version of the task file or upgrade it to the latest one`},
+			flags:     flagSpec{"check": false, "apply": false},
+			conflicts: [][2]string{{"check", "apply"}},
+			minArgs:   0,
+			maxArgs:   0,
+			run:       (*Cli).Migrate,
+		},
+		{
+			name:      "help",
+			usage:     `[<command>]`,
+			about:     []string{`show this help message, or the one of the command like "tasker <command> --help", and exit`},
+			flags:     flagSpec{},
+			conflicts: nil,
+			minArgs:   0,
+			maxArgs:   oneArg,
+			run:       (*Cli).helpCommand,
+		},
+	}
+}
+
+// globalFlag is the flag of any command, it is taken out of the arguments before the command.
+type globalFlag struct {
+	name  string
+	usage string
+	about []string
+}
+
+func globalFlags() []globalFlag {
+	return []globalFlag{
+		{
+			name:  fileFlag,
+			usage: `<path>`,
+			about: []string{`use the task file instead of the one from "$TASKER_FILE" or "tasker.json"`},
+		},
+		{
+			name:  outputFlag,
+			usage: `<format>`,
+			about: []string{
+				`print the result of the command as "json", "jsonl", "csv" or "tsv" instead of the text, the columns`,
+				`are stable, the error is the object with its code, message and exit code`,
+			},
+		},
+		{
+			name:  colorFlag,
+			usage: `always|never|auto`,
+			about: []string{`paint statuses, ids and errors, by default only for the terminal and without "$NO_COLOR"`},
+		},
+	}
+}
+
+func lookupCommand(name string) *command {
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return &cmd
+		}
+	}
+
+	return nil
+}
+
+// commandTemplate names the template of the command usage.
+func commandTemplate(name string) string {
+	return helpFlag + " " + name
+}
+
+const (
+	helpFlag   = "help"
+	helpHeader = `manage tasks with ease from the command line:`
+	helpIndent = "      "
+)
+
+func helpEntry(synopsis string, about []string) string {
+	return "\n - tasker " + synopsis + "\n" + helpIndent + strings.Join(about, "\n"+helpIndent)
+}
+
+func commandUsageBody(cmd *command) string {
+	return helpEntry(strings.TrimSpace(cmd.name+" "+cmd.usage), cmd.about)
+}
+
+// helpBody is the help of all the commands and the global flags.
+func helpBody() string {
+	var body strings.Builder
+
+	body.WriteString(helpHeader)
+
+	for _, cmd := range commands() {
+		body.WriteString(commandUsageBody(&cmd))
+	}
+
+	for _, flag := range globalFlags() {
+		body.WriteString(helpEntry("<command> "+flagPrefix+flag.name+" "+flag.usage, flag.about))
+	}
+
+	return body.String()
+}
+
+// parse checks the arguments by the declaration of the command and shows its help on "--help".
+// The command is over with the returned status unless the arguments are fine.
+func (cli *Cli) parse(name string, args []string) (parsedArgs, int, bool) {
+	cmd := lookupCommand(name)
+
+	spec := maps.Clone(cmd.flags)
+	spec[helpFlag] = false
+
+	parsed, bad := parseArgs(args, spec)
+
+	switch {
+	case bad != "":
+		return parsed, cli.errInvalidFlag(name, bad), false
+	case parsed.has(helpFlag):
+		return parsed, cli.commandHelp(cmd), false
+	}
+
+	for _, pair := range cmd.conflicts {
+		if parsed.has(pair[0]) && parsed.has(pair[1]) {
+			return parsed, cli.errConflictingFlags(pair[0], pair[1]), false
+		}
+	}
+
+	switch {
+	case cmd.maxArgs != manyArgs && len(parsed.positional) > cmd.maxArgs:
+		return parsed, cli.errInvalidFlag(name, parsed.positional[cmd.maxArgs]), false
+	case len(parsed.positional) < cmd.minArgs:
+		return parsed, cli.errNotEnoughArgs(name), false
+	}
+
+	return parsed, success, true
+}
+
+// suggest gives the command the name is likely the typo of, or nothing.
+func suggest(name string) string {
+	// one more typo is forgiven for every typoRunes runes of the name
+	const typoRunes = 5
+
+	maxDistance := 1 + utf8.RuneCountInString(name)/typoRunes
+
+	var (
+		best     string
+		distance = maxDistance + 1
+		prefixed = make([]string, 0)
+	)
+
+	for _, cmd := range commands() {
+		if strings.HasPrefix(cmd.name, name) {
+			prefixed = append(prefixed, cmd.name)
+		}
+
+		if dist := editDistance(name, cmd.name); dist < distance {
+			best, distance = cmd.name, dist
+		}
+	}
+
+	if best == "" && len(prefixed) == 1 {
+		return prefixed[0]
+	}
+
+	return best
+}
+
+// editDistance counts the inserted, deleted, replaced and swapped neighbour runes turning a into b.
+func editDistance(a string, b string) int {
+	src, dst := []rune(a), []rune(b)
+	rows := make([][]int, len(src)+1)
+
+	for idx := range rows {
+		rows[idx] = make([]int, len(dst)+1)
+		rows[idx][0] = idx
+	}
+
+	for jdx := range rows[0] {
+		rows[0][jdx] = jdx
+	}
+
+	for idx := 1; idx <= len(src); idx++ {
+		for jdx := 1; jdx <= len(dst); jdx++ {
+			cost := 1
+			if src[idx-1] == dst[jdx-1] {
+				cost = 0
+			}
+
+			rows[idx][jdx] = min(rows[idx-1][jdx]+1, rows[idx][jdx-1]+1, rows[idx-1][jdx-1]+cost)
+
+			if idx > 1 && jdx > 1 && src[idx-1] == dst[jdx-2] && src[idx-2] == dst[jdx-1] {
+				rows[idx][jdx] = min(rows[idx][jdx], rows[idx-2][jdx-2]+1)
+			}
+		}
+	}
+
+	return rows[len(src)][len(dst)]
+}
@@ -0,0 +1,168 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/cli"
+)
+
+func TestUnitCliSuggest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+	}{
+		{name: "swapped", command: "lsit", want: `error: unknown command "lsit", did you mean "list"?`},
+		{name: "missed", command: "ad", want: `error: unknown command "ad", did you mean "add"?`},
+		{name: "long", command: "histroyy", want: `error: unknown command "histroyy", did you mean "history"?`},
+		{name: "prefix", command: "arch", want: `error: unknown command "arch", did you mean "archive"?`},
+		{name: "ambiguous prefix", command: "re", want: `error: unknown command "re"`},
+		{name: "far", command: "run", want: `error: unknown command "run"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			if got := client.Unknown(test.command); got != failure {
+				t.Errorf("Unknown() got = %v, want = %v", got, failure)
+			}
+
+			if text := buffer.String(); text != test.want {
+				t.Errorf("Unknown() got = %v, want = %v", text, test.want)
+			}
+		})
+	}
+}
+
+func TestUnitCliCommandHelp(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		code int
+		text string
+	}
+
+	mark := `usage:
+ - tasker mark <id> <status> [--force] [--close-parent]
+      set a new status for the task ("todo", "progress", or "done"), the task with open subtasks
+      is done and the blocked task is started or done only by force, the parent could be done
+      together with its last open subtask
+`
+
+	tests := []struct {
+		name string
+		args []string
+		want want
+	}{
+		{name: "flag", args: []string{"mark", "--help"}, want: want{code: success, text: mark}},
+		{name: "after arguments", args: []string{"mark", "1", "--help"}, want: want{code: success, text: mark}},
+		{name: "help command", args: []string{"help", "mark"}, want: want{code: success, text: mark}},
+		{
+			name: "no arguments",
+			args: []string{"redo", "--help"},
+			want: want{code: success, text: "usage:\n - tasker redo\n      make again the command reverted the last\n"},
+		},
+		{
+			name: "unknown command",
+			args: []string{"help", "mrak"},
+			want: want{code: failure, text: "error: unknown command \"mrak\", did you mean \"mark\"?\n"},
+		},
+		{
+			name: "extra command",
+			args: []string{"help", "mark", "done"},
+			want: want{code: invalid, text: "error: invalid argument \"done\" for command \"help\"\n"},
+		},
+		{
+			name: "invalid flag",
+			args: []string{"mark", "--help", "--quiet"},
+			want: want{code: invalid, text: "error: invalid argument \"--quiet\" for command \"mark\"\n"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.NewBuffer(nil)
+			client := newCli(buffer, newMock(test.name))
+
+			if got := client.Dispatch(t.Context(), test.args); got != test.want.code {
+				t.Errorf("Dispatch() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text {
+				t.Errorf("Dispatch() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
+
+func TestUnitCliArguments(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want want
+	}{
+		{
+			name: "extra argument",
+			args: []string{"graph", "1", "2"},
+			want: want{code: invalid, text: `error: invalid argument "2" for command "graph"`},
+		},
+		{
+			name: "unquoted description",
+			args: []string{"add", "write", "docs"},
+			want: want{code: invalid, text: `error: invalid argument "docs" for command "add"`},
+		},
+		{
+			name: "unknown flag",
+			args: []string{"history", "1", "--all"},
+			want: want{code: invalid, text: `error: invalid argument "--all" for command "history"`},
+		},
+		{
+			name: "flag without value",
+			args: []string{"trash", "--older-than=1d"},
+			want: want{code: invalid, text: `error: invalid argument "--older-than=1d" for command "trash"`},
+		},
+		{
+			name: "missing argument",
+			args: []string{"prio", "1"},
+			want: want{code: noArgs, text: `error: not enough arguments for command "prio"`},
+		},
+		{
+			name: "conflicting flags",
+			args: []string{"search", "--all", "deploy", "--archived"},
+			want: want{code: invalid, text: `error: flags "--archived" and "--all" cannot be used together`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.NewBuffer(nil)
+			client := cli.New(cli.Config{Output: buffer})
+
+			if got := client.Dispatch(t.Context(), test.args); got != test.want.code {
+				t.Errorf("Dispatch() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text+"\n" {
+				t.Errorf("Dispatch() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
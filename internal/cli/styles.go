@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+
+	styleReset = "\x1b[0m"
+	styleRed   = "\x1b[1;31m"
+	styleGreen = "\x1b[32m"
+	styleBlue  = "\x1b[34m"
+	styleCyan  = "\x1b[36m"
+	styleGold  = "\x1b[33m"
+	styleGray  = "\x1b[90m"
+
+	errorPrefix = "error:"
+)
+
+func allColors() []string {
+	return []string{colorAuto, colorAlways, colorNever}
+}
+
+// styler paints the parts of the text output by ANSI escapes, being off it keeps the text as is.
+type styler struct {
+	enabled bool
+	// errors are painted apart, they may go to another output.
+	errors bool
+}
+
+func (s *styler) funcs() template.FuncMap {
+	return template.FuncMap{
+		"styleID":      s.id,
+		"styleStatus":  s.status,
+		"styleOverdue": s.overdue,
+		"styleError":   s.error,
+	}
+}
+
+func (s *styler) paint(style string, text string) string {
+	if !s.enabled || style == "" || text == "" {
+		return text
+	}
+
+	return style + text + styleReset
+}
+
+func (s *styler) id(tid any) string {
+	return s.paint(styleCyan, fmt.Sprint(tid))
+}
+
+// status paints the default statuses, the ones of the custom workflow stay plain.
+func (s *styler) status(status domain.Status) string {
+	styles := map[domain.Status]string{
+		domain.StatusTodo:      styleBlue,
+		domain.StatusProgress:  styleGold,
+		domain.StatusDone:      styleGreen,
+		domain.StatusCancelled: styleGray,
+	}
+
+	return s.paint(styles[status], string(status))
+}
+
+func (s *styler) overdue(text string) string {
+	return s.paint(styleRed, text)
+}
+
+func (s *styler) error(text string) string {
+	if !s.errors {
+		return text
+	}
+
+	return styleRed + text + styleReset
+}
+
+// column paints the cell of the table and compact views by its column, the text may be padded or bracketed.
+func (s *styler) column(name string, text string) string {
+	value := strings.Trim(text, "[] ")
+	styled := value
+
+	switch name {
+	case "id":
+		styled = s.id(value)
+	case "status":
+		styled = s.status(domain.Status(value))
+	}
+
+	return strings.Replace(text, value, styled, 1)
+}
+
+// styleErrors paints the prefix of the error templates.
+func styleErrors(body string) string {
+	after, found := strings.CutPrefix(body, errorPrefix)
+	if !found {
+		return body
+	}
+
+	return `{{ styleError "` + errorPrefix + `" }}` + after
+}
@@ -0,0 +1,106 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/cli"
+)
+
+func TestUnitCliColor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args     []string
+		terminal bool
+		width    int
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "auto terminal",
+			args: args{args: []string{"list", "--view", "compact"}, terminal: true},
+			want: want{code: success, text: "\n\x1b[36m1\x1b[0m [\x1b[33mprogress\x1b[0m] deploy \"api\", then docs" +
+				"\n\x1b[36m2\x1b[0m [\x1b[34mtodo\x1b[0m] write docs"},
+		},
+		{
+			name: "auto pipe",
+			args: args{args: []string{"list", "--view", "compact"}},
+			want: want{code: success, text: "\n1 [progress] deploy \"api\", then docs\n2 [todo] write docs"},
+		},
+		{
+			name: "never",
+			args: args{args: []string{"list", "--view", "compact", "--color=never"}, terminal: true},
+			want: want{code: success, text: "\n1 [progress] deploy \"api\", then docs\n2 [todo] write docs"},
+		},
+		{
+			name: "always",
+			args: args{args: []string{"--color", "always", "list", "--tree"}},
+			want: want{code: success, text: "\n\x1b[36m1\x1b[0m [\x1b[33mprogress\x1b[0m] deploy \"api\", then docs" +
+				"\n└── \x1b[36m2\x1b[0m [\x1b[34mtodo\x1b[0m] write docs"},
+		},
+		{
+			name: "cut compact",
+			args: args{args: []string{"list", "--view", "compact", "--color", "always"}, width: 16},
+			want: want{code: success, text: "\n\x1b[36m1\x1b[0m [\x1b[33mprogress\x1b[0m] de…" +
+				"\n\x1b[36m2\x1b[0m [\x1b[34mtodo\x1b[0m] write …"},
+		},
+		{
+			name: "table",
+			args: args{args: []string{"list", "--columns", "id,status,priority", "--color", "always"}},
+			want: want{code: success, text: "\nID  STATUS    PRIORITY" +
+				"\n\x1b[36m1\x1b[0m   \x1b[33mprogress\x1b[0m  high" +
+				"\n\x1b[36m2\x1b[0m   \x1b[34mtodo\x1b[0m      low"},
+		},
+		{
+			name: "error",
+			args: args{args: []string{"list", "--view", "grid"}, terminal: true},
+			want: want{
+				code: invalid,
+				text: "\x1b[1;31merror:\x1b[0m invalid \"view\" parameter, must be one of [block table compact]",
+			},
+		},
+		{
+			name: "structured output",
+			args: args{args: []string{"list", "--view", "grid", "--output", "jsonl", "--color", "always"}},
+			want: want{
+				code: invalid,
+				text: `{"code":"invalidView","exitCode":2,"message":"invalid \"view\" parameter, ` +
+					`must be one of [block table compact]"}`,
+			},
+		},
+		{
+			name: "invalid color",
+			args: args{args: []string{"list", "--color", "rainbow"}},
+			want: want{code: invalid, text: `error: invalid "color" parameter, must be one of [auto always never]`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.NewBuffer(nil)
+			client := cli.New(cli.Config{
+				Output: buffer, Storage: newViewsMock(), Width: test.args.width, Color: test.args.terminal,
+			})
+
+			if got := client.Dispatch(t.Context(), test.args.args); got != test.want.code {
+				t.Errorf("Dispatch() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text+"\n" {
+				t.Errorf("Dispatch() got = %q, want = %q", text, test.want.text)
+			}
+		})
+	}
+}
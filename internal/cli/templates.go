@@ -1,16 +1,19 @@
 package cli
 
 import (
+	"errors"
 	"strconv"
+	"strings"
 	"text/template"
+	"unicode/utf8"
 
 	"github.com/therenotomorrow/tasker/internal/domain"
 )
 
 const namespace = "tasker"
 
-func compileTemplates() *template.Template {
-	templates := template.New(namespace)
+func compileTemplates(paint *styler) *template.Template {
+	templates := template.New(namespace).Funcs(paint.funcs())
 
 	for name, body := range map[int]string{
 		notEnoughArgsTpl:      notEnoughArgsBody,
@@ -22,16 +25,75 @@ func compileTemplates() *template.Template {
 		unexpectedErrorTpl:    unexpectedErrorBody,
 		taskAlreadyDoneTpl:    taskAlreadyDoneBody,
 		taskListIsEmptyTpl:    taskListIsEmptyBody,
-
-		addTaskTpl:    addTaskBody,
-		updateTaskTpl: updateTaskBody,
-		deleteTaskTpl: deleteTaskBody,
-		markTaskTpl:   markTaskBody,
-		listTaskTpl:   listTaskBody,
-
-		helpTpl: helpBody,
+		storageLockedTpl:      storageLockedBody,
+		invalidFlagTpl:        invalidFlagBody,
+		conflictingFlagsTpl:   conflictingFlagsBody,
+		schemaTooNewTpl:       schemaTooNewBody,
+		invalidPriorityTpl:    invalidPriorityBody,
+		invalidDateTpl:        invalidDateBody,
+		invalidTagTpl:         invalidTagBody,
+		invalidProjectTpl:     invalidProjectBody,
+		openSubtasksTpl:       openSubtasksBody,
+		hasSubtasksTpl:        hasSubtasksBody,
+		taskBlockedTpl:        taskBlockedBody,
+		dependencyCycleTpl:    dependencyCycleBody,
+		invalidRecurrenceTpl:  invalidRecurrenceBody,
+		invalidTransitionTpl:  invalidTransitionBody,
+		taskNotClosedTpl:      taskNotClosedBody,
+		invalidCountTpl:       invalidCountBody,
+		nothingToUndoTpl:      nothingToUndoBody,
+		nothingToRedoTpl:      nothingToRedoBody,
+		journalConflictTpl:    journalConflictBody,
+		taskNotDeletedTpl:     taskNotDeletedBody,
+		parentDeletedTpl:      parentDeletedBody,
+		invalidPeriodTpl:      invalidPeriodBody,
+		invalidQueryTpl:       invalidQueryBody,
+		invalidFilterTpl:      invalidFilterBody,
+		invalidSortTpl:        invalidSortBody,
+		invalidLimitTpl:       invalidLimitBody,
+		invalidOffsetTpl:      invalidOffsetBody,
+		invalidOutputTpl:      invalidOutputBody,
+		invalidViewTpl:        invalidViewBody,
+		invalidColumnsTpl:     invalidColumnsBody,
+		invalidColorTpl:       invalidColorBody,
+
+		addTaskTpl:     addTaskBody,
+		updateTaskTpl:  updateTaskBody,
+		deleteTaskTpl:  deleteTaskBody,
+		markTaskTpl:    markTaskBody,
+		prioTaskTpl:    prioTaskBody,
+		dueTaskTpl:     dueTaskBody,
+		tagTaskTpl:     tagTaskBody,
+		listTaskTpl:    listTaskBody,
+		projectsTpl:    projectsBody,
+		listTreeTpl:    listTreeBody,
+		listLinesTpl:   listLinesBody,
+		dependTaskTpl:  dependTaskBody,
+		recurTaskTpl:   recurTaskBody,
+		reopenTaskTpl:  reopenTaskBody,
+		cancelTaskTpl:  cancelTaskBody,
+		historyTpl:     historyBody,
+		journalTpl:     journalBody,
+		undoTaskTpl:    undoTaskBody,
+		redoTaskTpl:    redoTaskBody,
+		trashTpl:       trashBody,
+		restoreTaskTpl: restoreTaskBody,
+		purgeTaskTpl:   purgeTaskBody,
+		archiveTaskTpl: archiveTaskBody,
+
+		schemaUpToDateTpl: schemaUpToDateBody,
+		schemaOutdatedTpl: schemaOutdatedBody,
+		schemaMigratedTpl: schemaMigratedBody,
+
+		commandHelpTpl: commandHelpBody,
+
+		helpTpl: helpBody(),
 	} {
-		_, _ = templates.New(strconv.Itoa(name)).Parse(body)
+		_, _ = templates.New(strconv.Itoa(name)).Parse(styleErrors(body))
+	}
+
+	for _, cmd := range commands() {
+		_, _ = templates.New(commandTemplate(cmd.name)).Parse(commandUsageBody(&cmd))
 	}
 
 	return templates
@@ -47,68 +109,345 @@ const (
 	unexpectedErrorTpl
 	taskAlreadyDoneTpl
 	taskListIsEmptyTpl
-
-	notEnoughArgsBody      = `error: not enough arguments for command "{{ .Command }}"`
-	unknownCommandBody     = `error: unknown command "{{ .Command }}"`
+	storageLockedTpl
+	invalidFlagTpl
+	conflictingFlagsTpl
+	schemaTooNewTpl
+	invalidPriorityTpl
+	invalidDateTpl
+	invalidTagTpl
+	invalidProjectTpl
+	openSubtasksTpl
+	hasSubtasksTpl
+	taskBlockedTpl
+	dependencyCycleTpl
+	invalidRecurrenceTpl
+	invalidTransitionTpl
+	taskNotClosedTpl
+	invalidCountTpl
+	nothingToUndoTpl
+	nothingToRedoTpl
+	journalConflictTpl
+	taskNotDeletedTpl
+	parentDeletedTpl
+	invalidPeriodTpl
+	invalidQueryTpl
+	invalidFilterTpl
+	invalidSortTpl
+	invalidLimitTpl
+	invalidOffsetTpl
+	invalidOutputTpl
+	invalidViewTpl
+	invalidColumnsTpl
+	invalidColorTpl
+
+	notEnoughArgsBody  = `error: not enough arguments for command "{{ .Command }}"`
+	unknownCommandBody = `error: unknown command "{{ .Command }}"` +
+		`{{ if .Suggestion }}, did you mean "{{ .Suggestion }}"?{{ end }}`
 	invalidTaskIDBody      = `error: invalid "id" parameter, must be positive integer`
 	invalidDescriptionBody = `error: invalid "description" parameter, must be not empty`
 	invalidStatusBody      = `error: invalid "status" parameter, must be one of {{ .Statuses }}`
 	taskNotFoundBody       = `error: task (ID: {{ .TaskID }}) not found`
 	unexpectedErrorBody    = `error: unexpected behaviour "{{ .Error }}"`
-	taskAlreadyDoneBody    = `error: cannot change status for done task`
+	taskAlreadyDoneBody    = `error: cannot change status for closed task`
 	taskListIsEmptyBody    = `error: task list is empty`
+	storageLockedBody      = `error: task file is locked by another tasker, try again later`
+	invalidFlagBody        = `error: invalid argument "{{ .Arg }}" for command "{{ .Command }}"`
+	conflictingFlagsBody   = `error: flags "--{{ .First }}" and "--{{ .Second }}" cannot be used together`
+	schemaTooNewBody       = `error: task file schema is newer than supported, upgrade tasker`
+	invalidPriorityBody    = `error: invalid "priority" parameter, must be one of {{ .Priorities }}`
+	invalidDateBody        = `error: invalid "when" parameter, must be a date like ` +
+		`"2026-01-31", "today", "tomorrow", "+3d", "next fri" or "eow"`
+	invalidTagBody = `error: invalid "tag" parameter, must start with a letter ` +
+		`and contain only letters, digits, "-" or "_"`
+	invalidProjectBody  = `error: invalid "project" parameter, must be dot separated names like "platform.auth.sso"`
+	openSubtasksBody    = `error: task has open subtasks, close them first or use "--force"`
+	hasSubtasksBody     = `error: task has subtasks, delete them first or use "--cascade"`
+	taskBlockedBody     = `error: task is blocked by unfinished tasks, finish them first or use "--force"`
+	dependencyCycleBody = `error: task (ID: {{ .TaskID }}) cannot depend on task (ID: {{ .OnID }}), ` +
+		`it makes a dependency cycle`
+	invalidRecurrenceBody = `error: invalid "rule" parameter, must be one of "daily", "weekly", "weekly:mon,thu", ` +
+		`"monthly", "monthly:15" or "every 2w"`
+	invalidTransitionBody = `error: cannot change status from "{{ .From }}" to "{{ .To }}"` +
+		`{{ if .Allowed }}, must be one of {{ .Allowed }}{{ end }}`
+	taskNotClosedBody   = `error: task is not closed, nothing to reopen`
+	invalidCountBody    = `error: invalid "n" parameter, must be positive integer`
+	nothingToUndoBody   = `error: nothing to undo`
+	nothingToRedoBody   = `error: nothing to redo`
+	journalConflictBody = `error: tasks were changed since the operation, it cannot be replayed safely`
+	taskNotDeletedBody  = `error: task (ID: {{ .TaskID }}) is not deleted, nothing to restore`
+	parentDeletedBody   = `error: parent task is deleted, restore it first`
+	invalidPeriodBody   = `error: invalid "older-than" parameter, must be a period like "12h", "30d" or "2w"`
+	invalidQueryBody    = `error: invalid "query" parameter, must be words, "prefix*", "quoted phrases" ` +
+		`or "-negated" terms`
+	invalidFilterBody = `error: invalid filter{{ if .Reason }}, {{ .Reason }}
+  {{ .Filter }}
+  {{ .Caret }}^{{ end }}`
+	invalidSortBody    = `error: invalid "sort" parameter, must be one of {{ .Fields }} with optional ":asc" or ":desc"`
+	invalidLimitBody   = `error: invalid "limit" parameter, must be positive integer`
+	invalidOffsetBody  = `error: invalid "offset" parameter, must be zero or positive integer`
+	invalidOutputBody  = `error: invalid "output" parameter, must be one of {{ .Formats }}`
+	invalidViewBody    = `error: invalid "view" parameter, must be one of {{ .Views }}`
+	invalidColumnsBody = `error: invalid "columns" parameter, must be comma separated names of {{ .Columns }}`
+	invalidColorBody   = `error: invalid "color" parameter, must be one of {{ .Colors }}`
 )
 
 func (cli *Cli) errNotEnoughArgs(command string) int {
-	_ = cli.template(notEnoughArgsTpl).Execute(cli.config.Output, map[string]string{"Command": command})
+	cli.fail(notEnoughArgsTpl, map[string]string{"Command": command})
 
 	return noArgs
 }
 
-func (cli *Cli) errUnknownCommand(command string) int {
-	_ = cli.template(unknownCommandTpl).Execute(cli.config.Output, map[string]string{"Command": command})
+func (cli *Cli) errUnknownCommand(command string, suggestion string) int {
+	cli.fail(unknownCommandTpl, map[string]string{"Command": command, "Suggestion": suggestion})
 
 	return failure
 }
 
 func (cli *Cli) errInvalidTaskID(id string) int {
-	_ = cli.template(invalidTaskIDTpl).Execute(cli.config.Output, map[string]string{"TaskID": id})
+	cli.fail(invalidTaskIDTpl, map[string]string{"TaskID": id})
 
 	return invalid
 }
 
 func (cli *Cli) errInvalidDescription() int {
-	_ = cli.template(invalidDescriptionTpl).Execute(cli.config.Output, nil)
+	cli.fail(invalidDescriptionTpl, nil)
 
 	return invalid
 }
 
 func (cli *Cli) errInvalidStatus(statuses []domain.Status) int {
-	_ = cli.template(invalidStatusTpl).Execute(cli.config.Output, map[string]any{"Statuses": statuses})
+	cli.fail(invalidStatusTpl, map[string]any{"Statuses": statuses})
+
+	return invalid
+}
+
+func (cli *Cli) errInvalidPriority(priorities []domain.Priority) int {
+	cli.fail(invalidPriorityTpl, map[string]any{"Priorities": priorities})
+
+	return invalid
+}
+
+func (cli *Cli) errInvalidDate() int {
+	cli.fail(invalidDateTpl, nil)
+
+	return invalid
+}
+
+func (cli *Cli) errInvalidTag() int {
+	cli.fail(invalidTagTpl, nil)
+
+	return invalid
+}
+
+func (cli *Cli) errInvalidProject() int {
+	cli.fail(invalidProjectTpl, nil)
+
+	return invalid
+}
+
+func (cli *Cli) errOpenSubtasks() int {
+	cli.fail(openSubtasksTpl, nil)
+
+	return failure
+}
+
+func (cli *Cli) errHasSubtasks() int {
+	cli.fail(hasSubtasksTpl, nil)
+
+	return failure
+}
+
+func (cli *Cli) errTaskBlocked() int {
+	cli.fail(taskBlockedTpl, nil)
+
+	return failure
+}
+
+func (cli *Cli) errDependencyCycle(id string, onID string) int {
+	cli.fail(dependencyCycleTpl, map[string]string{"TaskID": id, "OnID": onID})
+
+	return failure
+}
+
+func (cli *Cli) errInvalidRecurrence() int {
+	cli.fail(invalidRecurrenceTpl, nil)
+
+	return invalid
+}
+
+func (cli *Cli) errInvalidTransition(err error) int {
+	var transition *domain.TransitionError
+
+	_ = errors.As(err, &transition)
+	cli.fail(invalidTransitionTpl, transition)
+
+	return invalid
+}
+
+func (cli *Cli) errTaskNotClosed() int {
+	cli.fail(taskNotClosedTpl, nil)
+
+	return failure
+}
+
+func (cli *Cli) errInvalidCount() int {
+	cli.fail(invalidCountTpl, nil)
+
+	return invalid
+}
+
+func (cli *Cli) errNothingToUndo() int {
+	cli.fail(nothingToUndoTpl, nil)
+
+	return failure
+}
+
+func (cli *Cli) errNothingToRedo() int {
+	cli.fail(nothingToRedoTpl, nil)
+
+	return failure
+}
+
+func (cli *Cli) errJournalConflict() int {
+	cli.fail(journalConflictTpl, nil)
+
+	return failure
+}
+
+func (cli *Cli) errTaskNotDeleted(id string) int {
+	cli.fail(taskNotDeletedTpl, map[string]string{"TaskID": id})
+
+	return failure
+}
+
+func (cli *Cli) errParentDeleted() int {
+	cli.fail(parentDeletedTpl, nil)
+
+	return failure
+}
+
+func (cli *Cli) errInvalidPeriod() int {
+	cli.fail(invalidPeriodTpl, nil)
+
+	return invalid
+}
+
+func (cli *Cli) errInvalidQuery() int {
+	cli.fail(invalidQueryTpl, nil)
+
+	return invalid
+}
+
+func (cli *Cli) errInvalidFilter(filter string, err error) int {
+	var filterErr *domain.FilterError
+
+	// the error without the position is shown without the caret
+	if !errors.As(err, &filterErr) {
+		cli.fail(invalidFilterTpl, nil)
+
+		return invalid
+	}
+
+	pos := min(max(filterErr.Pos, 0), len(filter))
+
+	cli.fail(invalidFilterTpl, map[string]string{
+		"Reason": filterErr.Reason,
+		"Filter": filter,
+		"Caret":  strings.Repeat(" ", utf8.RuneCountInString(filter[:pos])),
+	})
+
+	return invalid
+}
+
+func (cli *Cli) errInvalidSort(fields []domain.SortField) int {
+	cli.fail(invalidSortTpl, map[string]any{"Fields": fields})
+
+	return invalid
+}
+
+func (cli *Cli) errInvalidLimit() int {
+	cli.fail(invalidLimitTpl, nil)
+
+	return invalid
+}
+
+func (cli *Cli) errInvalidOffset() int {
+	cli.fail(invalidOffsetTpl, nil)
+
+	return invalid
+}
+
+func (cli *Cli) errInvalidOutput(formats []string) int {
+	cli.fail(invalidOutputTpl, map[string]any{"Formats": formats})
+
+	return invalid
+}
+
+func (cli *Cli) errInvalidView(views []string) int {
+	cli.fail(invalidViewTpl, map[string]any{"Views": views})
+
+	return invalid
+}
+
+func (cli *Cli) errInvalidColumns(columns []string) int {
+	cli.fail(invalidColumnsTpl, map[string]any{"Columns": columns})
+
+	return invalid
+}
+
+func (cli *Cli) errInvalidColor(colors []string) int {
+	cli.fail(invalidColorTpl, map[string]any{"Colors": colors})
 
 	return invalid
 }
 
 func (cli *Cli) errTaskNotFound(id string) int {
-	_ = cli.template(taskNotFoundTpl).Execute(cli.config.Output, map[string]string{"TaskID": id})
+	cli.fail(taskNotFoundTpl, map[string]string{"TaskID": id})
 
 	return failure
 }
 
 func (cli *Cli) errUnexpected(err error) int {
-	_ = cli.template(unexpectedErrorTpl).Execute(cli.config.Output, map[string]string{"Error": err.Error()})
+	cli.fail(unexpectedErrorTpl, map[string]string{"Error": err.Error()})
 
 	return unknown
 }
 
 func (cli *Cli) errTaskAlreadyDone() int {
-	_ = cli.template(taskAlreadyDoneTpl).Execute(cli.config.Output, nil)
+	cli.fail(taskAlreadyDoneTpl, nil)
 
 	return failure
 }
 
 func (cli *Cli) errTaskListIsEmpty() int {
-	_ = cli.template(taskListIsEmptyTpl).Execute(cli.config.Output, nil)
+	cli.fail(taskListIsEmptyTpl, nil)
+
+	return failure
+}
+
+func (cli *Cli) errStorageLocked() int {
+	cli.fail(storageLockedTpl, nil)
+
+	return failure
+}
+
+func (cli *Cli) errInvalidFlag(command string, arg string) int {
+	data := map[string]string{"Command": command, "Arg": arg}
+	cli.fail(invalidFlagTpl, data)
+
+	return invalid
+}
+
+func (cli *Cli) errConflictingFlags(first string, second string) int {
+	data := map[string]string{"First": first, "Second": second}
+	cli.fail(conflictingFlagsTpl, data)
+
+	return invalid
+}
+
+func (cli *Cli) errSchemaTooNew() int {
+	cli.fail(schemaTooNewTpl, nil)
 
 	return failure
 }
@@ -119,42 +458,91 @@ const (
 	deleteTaskTpl
 	markTaskTpl
 	listTaskTpl
-
-	addTaskBody    = `task added successfully (ID: {{ .TaskID }})`
+	prioTaskTpl
+	dueTaskTpl
+	tagTaskTpl
+	projectsTpl
+	listTreeTpl
+	dependTaskTpl
+	recurTaskTpl
+	reopenTaskTpl
+	cancelTaskTpl
+	historyTpl
+	journalTpl
+	undoTaskTpl
+	redoTaskTpl
+	trashTpl
+	restoreTaskTpl
+	purgeTaskTpl
+	archiveTaskTpl
+	schemaUpToDateTpl
+	schemaOutdatedTpl
+	schemaMigratedTpl
+	commandHelpTpl
+	listLinesTpl
+
+	addTaskBody    = `task added successfully (ID: {{ styleID .TaskID }})`
 	updateTaskBody = `task updated successfully`
 	deleteTaskBody = `task deleted successfully`
 	markTaskBody   = `task status changed successfully`
+	prioTaskBody   = `task priority changed successfully`
+	dueTaskBody    = `task due date changed successfully`
+	tagTaskBody    = `task tags changed successfully`
+	dependTaskBody = `task dependencies changed successfully`
+	recurTaskBody  = `task recurrence changed successfully`
+	reopenTaskBody = `task reopened successfully`
+	cancelTaskBody = `task cancelled successfully`
 	listTaskBody   = `{{ range . }}
----- id: {{ .ID }}
+---- id: {{ styleID .ID }}{{ if .Archived }} (archived){{ end }}
+{{- if .Overdue }} {{ styleOverdue "(overdue)" }}{{ end }}
 description | {{ .Description }}
-status      | {{ .Status }}
+status      | {{ styleStatus .Status }}
+priority    | {{ .Priority }}
+{{ if .Project }}project     | {{ .Project }}
+{{ end -}}
+{{ if .ParentID }}parent      | {{ .ParentID }}
+{{ end -}}
+{{ if .BlockedBy }}blocked by  | {{ .BlockedBy }}
+{{ end -}}
+{{ if .Recurrence }}recurrence  | {{ .Recurrence }}
+{{ end -}}
+{{ with .Transition }}transition  | {{ .From }} -> {{ .To }} at {{ .At.Format "02 Jan 2006 15:04:05" }}
+{{- if .Reason }}, {{ .Reason }}{{ end }}
+{{ end -}}
 created at  | {{ .CreatedAt.Format "02 Jan 2006 15:04:05" }}
 last update | {{ .LastUpdate }} ago
-{{ end -}}`
+{{ if .HasDue }}due at      | {{ .DueAt.Format "02 Jan 2006 15:04:05" }} ({{ .Due }})
+{{ end }}{{ if .Tags }}tags        | {{ .Tags }}
+{{ end }}{{ end -}}`
+	projectsBody = `{{ range . }}
+{{ .Name }} | {{ .Counts }}{{ end }}`
+	listTreeBody = `{{ range . }}
+{{ .Prefix }}{{ styleID .ID }} [{{ styleStatus .Status }}] {{ .Description }}
+{{- if .Blocked }} (blocked){{ end }}{{ end }}`
+	journalBody = `{{ range . }}
+{{ .ID }} | {{ .At.Format "02 Jan 2006 15:04:05" }} | {{ .Actor }} | {{ .Command }} (ID: {{ .Tasks }}){{ end }}`
+	undoTaskBody = `operations undone successfully:` + journalBody
+	redoTaskBody = `operation redone successfully:` + journalBody
+	trashBody    = `{{ range . }}
+{{ styleID .ID }} | {{ .DeletedAt.Format "02 Jan 2006 15:04:05" }} | {{ .Description }}
+{{- else }}trash is empty{{ end }}`
+	restoreTaskBody = `tasks restored successfully (ID: {{ .Tasks }})`
+	purgeTaskBody   = `{{ if .Tasks }}tasks purged successfully (ID: {{ .Tasks }}){{ else }}nothing to purge{{ end }}`
+	archiveTaskBody = `{{ if .Tasks }}tasks archived successfully (ID: {{ .Tasks }}){{ else }}nothing to archive{{ end }}`
+	historyBody     = `{{ range . }}
+{{ .At.Format "02 Jan 2006 15:04:05" }} | {{ .Actor }} | {{ .Change }}
+{{- else }}no changes recorded for the task{{ end }}`
+	schemaUpToDateBody = `task file schema is up to date (version: {{ .Version }})`
+	schemaOutdatedBody = `task file schema is outdated (version: {{ .Version }}, latest: {{ .Latest }}), ` +
+		`run "tasker migrate --apply"`
+	schemaMigratedBody = `task file schema migrated successfully (version: {{ .Version }} -> {{ .Latest }})`
+	commandHelpBody    = `usage:{{ .Usage }}`
+	listLinesBody      = `{{ range . }}
+{{ . }}{{ end }}`
 )
 
 func (cli *Cli) template(name int) *template.Template {
 	return cli.templates.Lookup(strconv.Itoa(name))
 }
 
-const (
-	helpTpl = iota
-
-	helpBody = `manage tasks with ease from the command line:
- - tasker add "description"
-      add a new task with the given description
- - tasker update <id> "new description"
-      update the description of an existing task by its ID
- - tasker delete <id>
-      delete the task with the specified ID
- - tasker mark <id> <status>
-      set a new status for the task ("todo", "progress", or "done")
- - tasker work <id>
-      shortcut to mark the task as "progress"
- - tasker done <id>
-      shortcut to mark the task as "done"
- - tasker list [status]
-      list all tasks, if a status is provided, only tasks with that status will be shown
- - tasker help
-      show this help message and exit`
-)
+const helpTpl = 0
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"os"
+	"strconv"
+)
+
+// TerminalWidth gives the width from "$COLUMNS" or the terminal of the file, zero when it is piped.
+func TerminalWidth(file *os.File) int {
+	if !isTerminal(file) {
+		return 0
+	}
+
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err == nil && width > 0 {
+		return width
+	}
+
+	return ttyWidth(file)
+}
+
+// TerminalColor tells whether to paint the output to the file, it is the terminal and "$NO_COLOR" is not set.
+func TerminalColor(file *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	return isTerminal(file)
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
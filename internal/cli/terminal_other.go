@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package cli
+
+import "os"
+
+// the size of the terminal is not known here, so nothing is truncated.
+func ttyWidth(_ *os.File) int {
+	return 0
+}
@@ -0,0 +1,77 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/cli"
+)
+
+func TestUnitTerminalWidth(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "output.txt"))
+	if err != nil {
+		t.Fatalf("Create() got = %v, want = %v", err, nil)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	// the null device is the character one like the terminal, but it has no size of its own
+	device, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("OpenFile() got = %v, want = %v", err, nil)
+	}
+
+	defer func() { _ = device.Close() }()
+
+	tests := []struct {
+		name    string
+		file    *os.File
+		columns string
+		want    int
+	}{
+		{name: "columns", file: device, columns: "100", want: 100},
+		{name: "piped columns", file: file, columns: "100", want: 0},
+		{name: "not a terminal", file: file, columns: "", want: 0},
+		{name: "invalid columns", file: device, columns: "wide", want: 0},
+		{name: "negative columns", file: device, columns: "-80", want: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("COLUMNS", test.columns)
+
+			if got := cli.TerminalWidth(test.file); got != test.want {
+				t.Errorf("TerminalWidth() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnitTerminalColor(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "output.txt"))
+	if err != nil {
+		t.Fatalf("Create() got = %v, want = %v", err, nil)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	tests := []struct {
+		name    string
+		noColor string
+		want    bool
+	}{
+		{name: "not a terminal", noColor: "", want: false},
+		{name: "no color", noColor: "1", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", test.noColor)
+
+			if got := cli.TerminalColor(file); got != test.want {
+				t.Errorf("TerminalColor() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+type winsize struct {
+	rows, cols, xpixel, ypixel uint16
+}
+
+func ttyWidth(file *os.File) int {
+	var size winsize
+
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_IOCTL, file.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&size)),
+	)
+	if errno != 0 {
+		return 0
+	}
+
+	return int(size.cols)
+}
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+const (
+	viewBlock   = "block"
+	viewTable   = "table"
+	viewCompact = "compact"
+
+	columnsSeparator = ","
+	cellsSeparator   = "  "
+	ellipsis         = "…"
+)
+
+func allViews() []string {
+	return []string{viewBlock, viewTable, viewCompact}
+}
+
+// column is the part of the task shown by the table and compact views.
+type column struct {
+	name  string
+	value func(task *domain.Task, now time.Time) string
+}
+
+func allColumns() []column {
+	return []column{
+		{name: "id", value: func(task *domain.Task, _ time.Time) string { return strconv.FormatUint(task.ID, 10) }},
+		{name: "status", value: func(task *domain.Task, _ time.Time) string { return string(task.Status) }},
+		{name: "priority", value: func(task *domain.Task, _ time.Time) string { return string(task.Priority) }},
+		{name: "age", value: func(task *domain.Task, now time.Time) string { return shortDuration(now.Sub(task.CreatedAt)) }},
+		{name: "due", value: func(task *domain.Task, _ time.Time) string {
+			if !task.HasDue() {
+				return ""
+			}
+
+			return task.DueAt.Format(time.DateOnly)
+		}},
+		{name: "project", value: func(task *domain.Task, _ time.Time) string { return task.Project }},
+		{name: "tags", value: func(task *domain.Task, _ time.Time) string { return tagsString(task.Tags) }},
+		{name: "parent", value: func(task *domain.Task, _ time.Time) string {
+			if !task.HasParent() {
+				return ""
+			}
+
+			return strconv.FormatUint(task.ParentID, 10)
+		}},
+		{name: "description", value: func(task *domain.Task, _ time.Time) string { return task.Description }},
+	}
+}
+
+func columnNames() []string {
+	columns := allColumns()
+
+	names := make([]string, len(columns))
+	for idx, col := range columns {
+		names[idx] = col.name
+	}
+
+	return names
+}
+
+func defaultColumns(view string) string {
+	if view == viewCompact {
+		return "id,status,description"
+	}
+
+	return "id,status,priority,age,description"
+}
+
+// parseColumns gives the columns by their comma separated names, false for the unknown one.
+func parseColumns(raw string) ([]column, bool) {
+	known := allColumns()
+	columns := make([]column, 0, len(known))
+
+	for name := range strings.SplitSeq(raw, columnsSeparator) {
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		idx := slices.IndexFunc(known, func(col column) bool { return col.name == name })
+		if idx < 0 {
+			return nil, false
+		}
+
+		columns = append(columns, known[idx])
+	}
+
+	return columns, true
+}
+
+// shortDuration is the duration in the largest whole unit like "45m", "3h" or "12d".
+func shortDuration(duration time.Duration) string {
+	const oneDay = 24 * time.Hour
+
+	switch {
+	case duration < time.Hour:
+		return fmt.Sprintf("%dm", int(duration.Minutes()))
+	case duration < oneDay:
+		return fmt.Sprintf("%dh", int(duration.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(duration/oneDay))
+	}
+}
+
+// truncate cuts the text to the width in runes marking the cut, zero width keeps the text.
+func truncate(text string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(text) <= width {
+		return text
+	}
+
+	return string([]rune(text)[:width-1]) + ellipsis
+}
+
+// tableLines aligns the columns under the header, the description is cut to fit the width.
+func tableLines(tasks []*domain.Task, columns []column, width int, now time.Time, paint *styler) []string {
+	rows := make([][]string, 0, len(tasks)+1)
+
+	header := make([]string, len(columns))
+	for idx, col := range columns {
+		header[idx] = strings.ToUpper(col.name)
+	}
+
+	rows = append(rows, header)
+
+	for _, task := range tasks {
+		row := make([]string, len(columns))
+		for idx, col := range columns {
+			row[idx] = col.value(task, now)
+		}
+
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(columns))
+
+	for _, row := range rows {
+		for idx, cell := range row {
+			widths[idx] = max(widths[idx], utf8.RuneCountInString(cell))
+		}
+	}
+
+	if width > 0 {
+		fitDescription(columns, widths, width)
+	}
+
+	lines := make([]string, len(rows))
+
+	for idx, row := range rows {
+		cells := make([]string, len(row))
+		for jdx, cell := range row {
+			cell = truncate(cell, widths[jdx])
+			padding := strings.Repeat(" ", widths[jdx]-utf8.RuneCountInString(cell))
+
+			if idx > 0 {
+				cell = paint.column(columns[jdx].name, cell)
+			}
+
+			cells[jdx] = cell + padding
+		}
+
+		lines[idx] = strings.TrimRight(strings.Join(cells, cellsSeparator), " ")
+	}
+
+	return lines
+}
+
+// fitDescription narrows the description column to the width left by the others, but not below its header.
+func fitDescription(columns []column, widths []int, width int) {
+	idx := slices.IndexFunc(columns, func(col column) bool { return col.name == "description" })
+	if idx < 0 {
+		return
+	}
+
+	others := len(cellsSeparator) * (len(columns) - 1)
+
+	for jdx, size := range widths {
+		if jdx != idx {
+			others += size
+		}
+	}
+
+	widths[idx] = max(min(widths[idx], width-others), len(columns[idx].name))
+}
+
+// compactLines puts every task on the line cut to the width, the status is bracketed.
+func compactLines(tasks []*domain.Task, columns []column, width int, now time.Time, paint *styler) []string {
+	lines := make([]string, len(tasks))
+
+	for idx, task := range tasks {
+		cells, names := make([]string, 0, len(columns)), make([]string, 0, len(columns))
+
+		for _, col := range columns {
+			cell := col.value(task, now)
+
+			switch {
+			case cell == "":
+				continue
+			case col.name == "status":
+				cell = "[" + cell + "]"
+			}
+
+			cells, names = append(cells, cell), append(names, col.name)
+		}
+
+		lines[idx] = paintCompact(truncate(strings.Join(cells, " "), width), cells, names, paint)
+	}
+
+	return lines
+}
+
+// paintCompact paints the cells kept whole by the cut of the line, the rest of it stays plain.
+func paintCompact(line string, cells []string, names []string, paint *styler) string {
+	var painted strings.Builder
+
+	for idx, cell := range cells {
+		rest, whole := strings.CutPrefix(line, cell)
+		if !whole {
+			break
+		}
+
+		painted.WriteString(paint.column(names[idx], cell))
+
+		line = strings.TrimPrefix(rest, " ")
+		if line != rest {
+			painted.WriteString(" ")
+		}
+	}
+
+	painted.WriteString(line)
+
+	return painted.String()
+}
@@ -0,0 +1,134 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/cli"
+	"github.com/therenotomorrow/tasker/internal/domain"
+	"github.com/therenotomorrow/tasker/internal/storage"
+)
+
+func newViewsMock() *storage.Mock {
+	now := time.Now()
+	tasks := []*domain.Task{
+		{
+			ID: 1, Description: `deploy "api", then docs`, Status: domain.StatusProgress, Priority: domain.PriorityHigh,
+			CreatedAt: now.Add(-73 * time.Hour), DueAt: time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC),
+			Tags: []string{"infra", "ops"}, Project: "platform",
+		},
+		{
+			ID: 2, Description: "write docs", Status: domain.StatusTodo, Priority: domain.PriorityLow,
+			CreatedAt: now.Add(-90 * time.Minute), ParentID: 1,
+		},
+	}
+
+	stor := new(storage.Mock)
+	stor.ListFunc = func(ctx context.Context, opts domain.ListOptions) ([]*domain.Task, error) {
+		return tasks, nil
+	}
+
+	return stor
+}
+
+func TestUnitCliListViews(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args  []string
+		width int
+	}
+
+	type want struct {
+		code int
+		text string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "table",
+			args: args{args: []string{"list", "--view", "table"}},
+			want: want{code: success, text: `
+ID  STATUS    PRIORITY  AGE  DESCRIPTION
+1   progress  high      3d   deploy "api", then docs
+2   todo      low       1h   write docs`},
+		},
+		{
+			name: "narrow table",
+			args: args{args: []string{"list", "--view=table"}, width: 40},
+			want: want{code: success, text: `
+ID  STATUS    PRIORITY  AGE  DESCRIPTION
+1   progress  high      3d   deploy "ap…
+2   todo      low       1h   write docs`},
+		},
+		{
+			name: "columns",
+			args: args{args: []string{"list", "--columns", "id,due,tags,parent,project"}, width: 10},
+			want: want{code: success, text: `
+ID  DUE         TAGS         PARENT  PROJECT
+1   2026-02-02  +infra +ops          platform
+2                            1`},
+		},
+		{
+			name: "compact",
+			args: args{args: []string{"list", "--view", "compact"}},
+			want: want{code: success, text: `
+1 [progress] deploy "api", then docs
+2 [todo] write docs`},
+		},
+		{
+			name: "narrow compact",
+			args: args{args: []string{"list", "--view", "compact", "--columns", "id,status,age,description"}, width: 20},
+			want: want{code: success, text: `
+1 [progress] 3d dep…
+2 [todo] 1h write d…`},
+		},
+		{
+			name: "invalid view",
+			args: args{args: []string{"list", "--view", "grid"}},
+			want: want{code: invalid, text: "error: invalid \"view\" parameter, must be one of [block table compact]"},
+		},
+		{
+			name: "invalid columns",
+			args: args{args: []string{"list", "--view", "table", "--columns", "id,size"}},
+			want: want{
+				code: invalid,
+				text: "error: invalid \"columns\" parameter, must be comma separated names of " +
+					"[id status priority age due project tags parent description]",
+			},
+		},
+		{
+			name: "block columns",
+			args: args{args: []string{"list", "--view", "block", "--columns", "id"}},
+			want: want{code: invalid, text: "error: flags \"--view\" and \"--columns\" cannot be used together"},
+		},
+		{
+			name: "tree view",
+			args: args{args: []string{"list", "--tree", "--view", "compact"}},
+			want: want{code: invalid, text: "error: flags \"--tree\" and \"--view\" cannot be used together"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := bytes.NewBuffer(nil)
+			client := cli.New(cli.Config{Output: buffer, Storage: newViewsMock(), Width: test.args.width})
+
+			if got := client.Dispatch(t.Context(), test.args.args); got != test.want.code {
+				t.Errorf("Dispatch() got = %v, want = %v", got, test.want.code)
+			}
+
+			if text := buffer.String(); text != test.want.text+"\n" {
+				t.Errorf("Dispatch() got = %v, want = %v", text, test.want.text)
+			}
+		})
+	}
+}
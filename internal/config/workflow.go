@@ -0,0 +1,49 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+// workflowFile is the JSON file declaring the task statuses, the first terminal one completes the task, like:
+//
+//	{
+//	  "statuses": ["todo", "progress", "review", "done", "cancelled"],
+//	  "terminal": ["done", "cancelled"],
+//	  "transitions": {"todo": ["progress", "cancelled"], "progress": ["review"], "review": ["done"]}
+//	}
+type workflowFile struct {
+	Statuses    []string            `json:"statuses"`
+	Terminal    []string            `json:"terminal"`
+	Transitions map[string][]string `json:"transitions"`
+}
+
+// LoadWorkflow reads the workflow from the file, the empty name stands for the default one.
+func LoadWorkflow(file string) (*domain.Workflow, error) {
+	if file == "" {
+		return domain.DefaultWorkflow(), nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, fmt.Errorf("workflow error: %w", err)
+	}
+
+	var raw workflowFile
+
+	err = json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("workflow error: %w: %w", domain.ErrInvalidWorkflow, err)
+	}
+
+	workflow, err := domain.NewWorkflow(raw.Statuses, raw.Terminal, raw.Transitions)
+	if err != nil {
+		return nil, fmt.Errorf("workflow error: %w", err)
+	}
+
+	return workflow, nil
+}
@@ -0,0 +1,85 @@
+package config_test
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/config"
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+func TestUnitLoadWorkflow(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		file string
+	}
+
+	type want struct {
+		statuses  []domain.Status
+		completed domain.Status
+		err       error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "default",
+			args: args{file: ""},
+			want: want{statuses: domain.AllStatus(), completed: domain.StatusDone},
+		},
+		{
+			name: "valid",
+			args: args{file: config.Path("test", "data", "workflow", "valid.json")},
+			want: want{
+				statuses:  []domain.Status{"todo", "progress", "review", "blocked", "done", "cancelled"},
+				completed: domain.StatusDone,
+			},
+		},
+		{
+			name: "without done",
+			args: args{file: config.Path("test", "data", "workflow", "shipping.json")},
+			want: want{statuses: []domain.Status{"open", "building", "shipped", "dropped"}, completed: "shipped"},
+		},
+		{
+			name: "not exist",
+			args: args{file: config.Path("test", "data", "workflow", "unknown.json")},
+			want: want{err: fs.ErrNotExist},
+		},
+		{
+			name: "broken",
+			args: args{file: config.Path("test", "data", "workflow", "broken.json")},
+			want: want{err: domain.ErrInvalidWorkflow},
+		},
+		{
+			name: "invalid",
+			args: args{file: config.Path("test", "data", "workflow", "invalid.json")},
+			want: want{err: domain.ErrInvalidWorkflow},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := config.LoadWorkflow(test.args.file)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("LoadWorkflow() error = %v, want = %v", err, test.want.err)
+			}
+
+			if err == nil && !reflect.DeepEqual(got.Statuses(), test.want.statuses) {
+				t.Errorf("LoadWorkflow() got = %v, want = %v", got.Statuses(), test.want.statuses)
+			}
+
+			if err == nil && got.Completed() != test.want.completed {
+				t.Errorf("LoadWorkflow() got = %v, want = %v", got.Completed(), test.want.completed)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "fmt"
+
 type Error string
 
 func (e Error) Error() string {
@@ -7,10 +9,66 @@ func (e Error) Error() string {
 }
 
 const (
-	ErrEmptyDescription Error = "emptyDescription"
-	ErrTaskNotFound     Error = "taskNotFound"
-	ErrInvalidStatus    Error = "invalidStatus"
-	ErrInvalidTaskID    Error = "invalidTaskID"
-	ErrTaskAlreadyDone  Error = "taskAlreadyDone"
-	ErrEmptyTasks       Error = "emptyTasks"
+	ErrEmptyDescription   Error = "emptyDescription"
+	ErrTaskNotFound       Error = "taskNotFound"
+	ErrInvalidStatus      Error = "invalidStatus"
+	ErrInvalidTaskID      Error = "invalidTaskID"
+	ErrTaskAlreadyDone    Error = "taskAlreadyDone"
+	ErrEmptyTasks         Error = "emptyTasks"
+	ErrStorageLocked      Error = "storageLocked"
+	ErrSchemaTooNew       Error = "schemaTooNew"
+	ErrInvalidPriority    Error = "invalidPriority"
+	ErrInvalidDate        Error = "invalidDate"
+	ErrInvalidTag         Error = "invalidTag"
+	ErrInvalidProject     Error = "invalidProject"
+	ErrOpenSubtasks       Error = "openSubtasks"
+	ErrHasSubtasks        Error = "hasSubtasks"
+	ErrDependencyCycle    Error = "dependencyCycle"
+	ErrDependencyNotFound Error = "dependencyNotFound"
+	ErrTaskBlocked        Error = "taskBlocked"
+	ErrInvalidRecurrence  Error = "invalidRecurrence"
+	ErrInvalidWorkflow    Error = "invalidWorkflow"
+	ErrInvalidTransition  Error = "invalidTransition"
+	ErrTaskNotClosed      Error = "taskNotClosed"
+	ErrInvalidCount       Error = "invalidCount"
+	ErrNothingToUndo      Error = "nothingToUndo"
+	ErrNothingToRedo      Error = "nothingToRedo"
+	ErrJournalConflict    Error = "journalConflict"
+	ErrTaskNotDeleted     Error = "taskNotDeleted"
+	ErrParentDeleted      Error = "parentDeleted"
+	ErrInvalidPeriod      Error = "invalidPeriod"
+	ErrInvalidQuery       Error = "invalidQuery"
+	ErrInvalidFilter      Error = "invalidFilter"
+	ErrInvalidSort        Error = "invalidSort"
+	ErrInvalidLimit       Error = "invalidLimit"
+	ErrInvalidOffset      Error = "invalidOffset"
 )
+
+// TransitionError is the ErrInvalidTransition with the statuses the task could move to instead.
+type TransitionError struct {
+	From    Status
+	To      Status
+	Allowed []Status
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", ErrInvalidTransition, e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// FilterError is the ErrInvalidFilter with the reason and the byte position in the expression.
+type FilterError struct {
+	Pos    int
+	Reason string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("%s at %d: %s", ErrInvalidFilter, e.Pos, e.Reason)
+}
+
+func (e *FilterError) Unwrap() error {
+	return ErrInvalidFilter
+}
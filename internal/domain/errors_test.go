@@ -1,6 +1,7 @@
 package domain_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/therenotomorrow/tasker/internal/domain"
@@ -15,3 +16,31 @@ func TestUnitError(t *testing.T) {
 		t.Errorf("Error() got = %v, want = %v", got, want)
 	}
 }
+
+func TestUnitTransitionError(t *testing.T) {
+	t.Parallel()
+
+	err := &domain.TransitionError{From: "todo", To: "done", Allowed: []domain.Status{"progress"}}
+
+	if got, want := err.Error(), "invalidTransition: todo -> done"; got != want {
+		t.Errorf("Error() got = %v, want = %v", got, want)
+	}
+
+	if !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("Unwrap() got = %v, want = %v", errors.Unwrap(err), domain.ErrInvalidTransition)
+	}
+}
+
+func TestUnitFilterError(t *testing.T) {
+	t.Parallel()
+
+	err := &domain.FilterError{Pos: 7, Reason: `unknown field "size"`}
+
+	if got, want := err.Error(), `invalidFilter at 7: unknown field "size"`; got != want {
+		t.Errorf("Error() got = %v, want = %v", got, want)
+	}
+
+	if !errors.Is(err, domain.ErrInvalidFilter) {
+		t.Errorf("Unwrap() got = %v, want = %v", errors.Unwrap(err), domain.ErrInvalidFilter)
+	}
+}
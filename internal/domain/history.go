@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// FieldTask is the field of the changes made to the task as a whole.
+	FieldTask = "task"
+
+	TaskAdded    = "added"
+	TaskDeleted  = "deleted"
+	TaskRestored = "restored"
+	TaskArchived = "archived"
+)
+
+// Change is the history entry of the task, values are kept as text,
+// so the entry reads the same whatever the field type is.
+type Change struct {
+	At    time.Time
+	Actor string
+	Field string
+	Old   string
+	New   string
+}
+
+type field struct {
+	name  string
+	value string
+}
+
+func (t Task) fields() []field {
+	due := ""
+	if t.HasDue() {
+		due = t.DueAt.Format(time.DateTime)
+	}
+
+	parent := ""
+	if t.HasParent() {
+		parent = strconv.FormatUint(t.ParentID, 10)
+	}
+
+	blockedBy := make([]string, len(t.BlockedBy))
+	for idx, tid := range t.BlockedBy {
+		blockedBy[idx] = strconv.FormatUint(tid, 10)
+	}
+
+	return []field{
+		{name: "description", value: t.Description},
+		{name: "status", value: string(t.Status)},
+		{name: "priority", value: string(t.Priority)},
+		{name: "due", value: due},
+		{name: "tags", value: strings.Join(t.Tags, " ")},
+		{name: "project", value: t.Project},
+		{name: "parent", value: parent},
+		{name: "blockedBy", value: strings.Join(blockedBy, " ")},
+		{name: "recurrence", value: string(t.Recurrence)},
+	}
+}
+
+// Diff lists the changed fields between the task versions, the zero task stands
+// for the one not existing yet. The time and the actor are left to the caller.
+func Diff(before Task, after Task) []Change {
+	olds, news := before.fields(), after.fields()
+	changes := make([]Change, 0)
+
+	for idx, old := range olds {
+		if old.value == news[idx].value {
+			continue
+		}
+
+		changes = append(changes, Change{
+			At:    time.Time{},
+			Actor: "",
+			Field: old.name,
+			Old:   old.value,
+			New:   news[idx].value,
+		})
+	}
+
+	return changes
+}
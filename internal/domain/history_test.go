@@ -0,0 +1,87 @@
+package domain_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+func TestUnitDiff(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		before domain.Task
+		after  domain.Task
+	}
+
+	dueAt := time.Date(2026, 5, 8, 23, 59, 59, 0, time.UTC)
+	task := domain.Task{
+		ID:          1,
+		Description: "write docs",
+		Status:      domain.StatusTodo,
+		Priority:    domain.PriorityMedium,
+		Tags:        []string{"docs"},
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want []domain.Change
+	}{
+		{
+			name: "nothing changed",
+			args: args{before: task, after: task},
+			want: []domain.Change{},
+		},
+		{
+			name: "new task",
+			args: args{before: domain.Task{}, after: task},
+			want: []domain.Change{
+				{Field: "description", New: "write docs"},
+				{Field: "status", New: "todo"},
+				{Field: "priority", New: "medium"},
+				{Field: "tags", New: "docs"},
+			},
+		},
+		{
+			name: "changed fields",
+			args: args{
+				before: task,
+				after: domain.Task{
+					ID:          1,
+					Description: "write the docs",
+					Status:      domain.StatusProgress,
+					Priority:    domain.PriorityMedium,
+					DueAt:       dueAt,
+					Project:     "platform",
+					ParentID:    3,
+					BlockedBy:   []uint64{4, 5},
+					Recurrence:  "daily",
+					UpdatedAt:   dueAt,
+				},
+			},
+			want: []domain.Change{
+				{Field: "description", Old: "write docs", New: "write the docs"},
+				{Field: "status", Old: "todo", New: "progress"},
+				{Field: "due", New: "2026-05-08 23:59:59"},
+				{Field: "tags", Old: "docs"},
+				{Field: "project", New: "platform"},
+				{Field: "parent", New: "3"},
+				{Field: "blockedBy", New: "4 5"},
+				{Field: "recurrence", New: "daily"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := domain.Diff(test.args.before, test.args.after); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Diff() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Revision is the task state before and after the operation, the nil Before stands
+// for the added task and the nil After for the deleted one.
+type Revision struct {
+	Before *Task
+	After  *Task
+}
+
+// TaskID is the ID of the revised task.
+func (r Revision) TaskID() uint64 {
+	if r.Before != nil {
+		return r.Before.ID
+	}
+
+	return r.After.ID
+}
+
+// Operation is the journal entry of the command changed the tasks, the revisions
+// are enough to revert the command or to make it again.
+type Operation struct {
+	ID        uint64
+	Command   string
+	At        time.Time
+	Actor     string
+	Revisions []Revision
+	Undone    bool
+}
+
+// TaskIDs lists the tasks changed by the operation, in the order of changes.
+func (o Operation) TaskIDs() []uint64 {
+	ids := make([]uint64, 0, len(o.Revisions))
+	for _, revision := range o.Revisions {
+		if !slices.Contains(ids, revision.TaskID()) {
+			ids = append(ids, revision.TaskID())
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,48 @@
+package domain_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+func TestUnitRevisionTaskID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		revision domain.Revision
+		want     uint64
+	}{
+		{name: "added", revision: domain.Revision{After: &domain.Task{ID: 1}}, want: 1},
+		{name: "deleted", revision: domain.Revision{Before: &domain.Task{ID: 2}}, want: 2},
+		{name: "updated", revision: domain.Revision{Before: &domain.Task{ID: 3}, After: &domain.Task{ID: 3}}, want: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := test.revision.TaskID(); got != test.want {
+				t.Errorf("TaskID() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnitOperationTaskIDs(t *testing.T) {
+	t.Parallel()
+
+	op := domain.Operation{Revisions: []domain.Revision{
+		{Before: &domain.Task{ID: 3}, After: &domain.Task{ID: 3}},
+		{After: &domain.Task{ID: 5}},
+		{Before: &domain.Task{ID: 3}},
+		{Before: &domain.Task{ID: 1}},
+	}}
+
+	want := []uint64{3, 5, 1}
+	if got := op.TaskIDs(); !slices.Equal(got, want) {
+		t.Errorf("TaskIDs() got = %v, want = %v", got, want)
+	}
+}
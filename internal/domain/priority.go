@@ -0,0 +1,34 @@
+package domain
+
+type Priority string
+
+const (
+	PriorityLow    Priority = "low"
+	PriorityMedium Priority = "medium"
+	PriorityHigh   Priority = "high"
+)
+
+func NewPriority(raw string) (Priority, error) {
+	p := Priority(raw)
+	switch p {
+	case PriorityLow, PriorityMedium, PriorityHigh:
+		return p, nil
+	default:
+		return "", ErrInvalidPriority
+	}
+}
+
+func AllPriority() []Priority {
+	return []Priority{PriorityLow, PriorityMedium, PriorityHigh}
+}
+
+// Weight orders priorities from the least important, unknown one weights nothing.
+func (p Priority) Weight() int {
+	for idx, priority := range AllPriority() {
+		if p == priority {
+			return idx + 1
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,86 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+func TestUnitNewPriority(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		raw string
+	}
+
+	type want struct {
+		priority domain.Priority
+		err      error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{name: "low", args: args{raw: "low"}, want: want{priority: domain.PriorityLow}},
+		{name: "medium", args: args{raw: "medium"}, want: want{priority: domain.PriorityMedium}},
+		{name: "high", args: args{raw: "high"}, want: want{priority: domain.PriorityHigh}},
+		{name: "invalid", args: args{raw: "urgent"}, want: want{err: domain.ErrInvalidPriority}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := domain.NewPriority(test.args.raw)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("NewPriority() error = %v, want = %v", err, test.want.err)
+			}
+
+			if got != test.want.priority {
+				t.Errorf("NewPriority() got = %v, want = %v", got, test.want.priority)
+			}
+		})
+	}
+}
+
+func TestUnitAllPriority(t *testing.T) {
+	t.Parallel()
+
+	got := domain.AllPriority()
+	want := []string{"low", "medium", "high"}
+
+	for idx, priority := range got {
+		if string(priority) != want[idx] {
+			t.Errorf("AllPriority() got = %v, want = %v", priority, want[idx])
+		}
+	}
+}
+
+func TestUnitPriorityWeight(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		priority domain.Priority
+		want     int
+	}{
+		{name: "unknown", priority: "", want: 0},
+		{name: "low", priority: domain.PriorityLow, want: 1},
+		{name: "medium", priority: domain.PriorityMedium, want: 2},
+		{name: "high", priority: domain.PriorityHigh, want: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := test.priority.Weight(); got != test.want {
+				t.Errorf("Weight() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
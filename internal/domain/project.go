@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	projectSeparator = "."
+	maxProjectLength = 128
+)
+
+// NewProject normalizes the dotted project like "platform.auth.sso", every part
+// follows the same rules as tags do.
+func NewProject(raw string) (string, error) {
+	project := strings.ToLower(strings.TrimSpace(raw))
+
+	if len(project) > maxProjectLength {
+		return "", ErrInvalidProject
+	}
+
+	for _, part := range strings.Split(project, projectSeparator) {
+		if !isName(part) {
+			return "", ErrInvalidProject
+		}
+	}
+
+	return project, nil
+}
+
+// ProjectHierarchy lists the project with all its parents from the root one,
+// so "a.b.c" gives "a", "a.b" and "a.b.c".
+func ProjectHierarchy(project string) []string {
+	if project == "" {
+		return nil
+	}
+
+	parts := strings.Split(project, projectSeparator)
+	hierarchy := make([]string, len(parts))
+
+	for idx := range parts {
+		hierarchy[idx] = strings.Join(parts[:idx+1], projectSeparator)
+	}
+
+	return hierarchy
+}
+
+// CompareProjects orders projects part by part, so parents go before their children.
+func CompareProjects(a, b string) int {
+	return slices.Compare(strings.Split(a, projectSeparator), strings.Split(b, projectSeparator))
+}
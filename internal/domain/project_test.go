@@ -0,0 +1,106 @@
+package domain_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+func TestUnitNewProject(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		raw string
+	}
+
+	type want struct {
+		project string
+		err     error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{name: "root", args: args{raw: "platform"}, want: want{project: "platform"}},
+		{name: "nested", args: args{raw: " Platform.Auth.sso-2 "}, want: want{project: "platform.auth.sso-2"}},
+		{name: "empty", args: args{raw: ""}, want: want{err: domain.ErrInvalidProject}},
+		{name: "empty part", args: args{raw: "platform..sso"}, want: want{err: domain.ErrInvalidProject}},
+		{name: "trailing dot", args: args{raw: "platform."}, want: want{err: domain.ErrInvalidProject}},
+		{name: "bad part", args: args{raw: "platform.2fa"}, want: want{err: domain.ErrInvalidProject}},
+		{name: "too long", args: args{raw: strings.Repeat("a", 129)}, want: want{err: domain.ErrInvalidProject}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := domain.NewProject(test.args.raw)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("NewProject() error = %v, want = %v", err, test.want.err)
+			}
+
+			if got != test.want.project {
+				t.Errorf("NewProject() got = %v, want = %v", got, test.want.project)
+			}
+		})
+	}
+}
+
+func TestUnitProjectHierarchy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		project string
+		want    []string
+	}{
+		{name: "empty", project: "", want: nil},
+		{name: "root", project: "platform", want: []string{"platform"}},
+		{
+			name:    "nested",
+			project: "platform.auth.sso",
+			want:    []string{"platform", "platform.auth", "platform.auth.sso"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := domain.ProjectHierarchy(test.project); !slices.Equal(got, test.want) {
+				t.Errorf("ProjectHierarchy() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnitCompareProjects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{name: "equal", a: "platform.auth", b: "platform.auth", want: 0},
+		{name: "parent", a: "platform", b: "platform.auth", want: -1},
+		{name: "child", a: "platform.auth", b: "platform", want: 1},
+		{name: "separator first", a: "platform.auth", b: "platform-x", want: -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := domain.CompareProjects(test.a, test.b); got != test.want {
+				t.Errorf("CompareProjects() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
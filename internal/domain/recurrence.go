@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence is the normalized rule of the recurring task, one of "daily",
+// "weekly", "weekly:mon,thu", "monthly", "monthly:15" or "every 2w" (days,
+// weeks or months).
+type Recurrence string
+
+const (
+	recurDaily   = "daily"
+	recurWeekly  = "weekly"
+	recurMonthly = "monthly"
+	recurEvery   = "every"
+
+	recurSeparator = ":"
+	listSeparator  = ","
+
+	unitDay   = "d"
+	unitWeek  = "w"
+	unitMonth = "m"
+
+	daysInWeek  = 7
+	maxMonthDay = 31
+	maxInterval = 999
+)
+
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+type rule struct {
+	unit     string
+	interval int
+	weekdays []time.Weekday
+	day      int
+}
+
+func NewRecurrence(raw string) (Recurrence, error) {
+	rul, err := parseRule(strings.ToLower(strings.Join(strings.Fields(raw), " ")))
+	if err != nil {
+		return "", err
+	}
+
+	return rul.recurrence(), nil
+}
+
+func parseRule(raw string) (rule, error) {
+	kind, arg, hasArg := strings.Cut(raw, recurSeparator)
+
+	switch {
+	case kind == recurDaily && !hasArg:
+		return rule{unit: unitDay, interval: 1, weekdays: nil, day: 0}, nil
+	case kind == recurWeekly && !hasArg:
+		return rule{unit: unitWeek, interval: 1, weekdays: nil, day: 0}, nil
+	case kind == recurWeekly:
+		weekdays, err := parseWeekdays(arg)
+
+		return rule{unit: unitWeek, interval: 1, weekdays: weekdays, day: 0}, err
+	case kind == recurMonthly && !hasArg:
+		return rule{unit: unitMonth, interval: 1, weekdays: nil, day: 0}, nil
+	case kind == recurMonthly:
+		day, err := strconv.Atoi(strings.TrimSpace(arg))
+		if err != nil || day < 1 || day > maxMonthDay {
+			return rule{}, ErrInvalidRecurrence
+		}
+
+		return rule{unit: unitMonth, interval: 1, weekdays: nil, day: day}, nil
+	case strings.HasPrefix(raw, recurEvery+" "):
+		return parseEvery(strings.TrimPrefix(raw, recurEvery+" "))
+	}
+
+	return rule{}, ErrInvalidRecurrence
+}
+
+func parseWeekdays(raw string) ([]time.Weekday, error) {
+	weekdays := make([]time.Weekday, 0, daysInWeek)
+
+	for _, name := range strings.Split(raw, listSeparator) {
+		idx := slices.Index(weekdayNames, strings.TrimSpace(name))
+		if idx < 0 {
+			return nil, ErrInvalidRecurrence
+		}
+
+		weekdays = append(weekdays, time.Weekday(idx))
+	}
+
+	slices.Sort(weekdays)
+
+	return slices.Compact(weekdays), nil
+}
+
+func parseEvery(raw string) (rule, error) {
+	raw = strings.ReplaceAll(raw, " ", "")
+	if raw == "" {
+		return rule{}, ErrInvalidRecurrence
+	}
+
+	unit := raw[len(raw)-1:]
+
+	interval, err := strconv.Atoi(raw[:len(raw)-1])
+	if err != nil || interval < 1 || interval > maxInterval {
+		return rule{}, ErrInvalidRecurrence
+	}
+
+	switch unit {
+	case unitDay, unitWeek, unitMonth:
+		return rule{unit: unit, interval: interval, weekdays: nil, day: 0}, nil
+	default:
+		return rule{}, ErrInvalidRecurrence
+	}
+}
+
+func (r rule) recurrence() Recurrence {
+	switch {
+	case r.interval > 1:
+		return Recurrence(recurEvery + " " + strconv.Itoa(r.interval) + r.unit)
+	case r.unit == unitDay:
+		return recurDaily
+	case r.unit == unitWeek && len(r.weekdays) > 0:
+		names := make([]string, len(r.weekdays))
+		for idx, weekday := range r.weekdays {
+			names[idx] = weekdayNames[weekday]
+		}
+
+		return Recurrence(recurWeekly + recurSeparator + strings.Join(names, listSeparator))
+	case r.unit == unitWeek:
+		return recurWeekly
+	case r.day > 0:
+		return Recurrence(recurMonthly + recurSeparator + strconv.Itoa(r.day))
+	default:
+		return recurMonthly
+	}
+}
+
+// Next gives the first occurrence strictly after the given time keeping its clock,
+// months shorter than the rule day use their last day. Invalid rule never recurs.
+func (r Recurrence) Next(from time.Time) time.Time {
+	rul, err := parseRule(string(r))
+	if err != nil {
+		return time.Time{}
+	}
+
+	switch {
+	case len(rul.weekdays) > 0:
+		for days := 1; ; days++ {
+			next := from.AddDate(0, 0, days)
+			if slices.Contains(rul.weekdays, next.Weekday()) {
+				return next
+			}
+		}
+	case rul.day > 0:
+		next := addMonths(from, 0, rul.day)
+		if !next.After(from) {
+			next = addMonths(from, 1, rul.day)
+		}
+
+		return next
+	case rul.unit == unitDay:
+		return from.AddDate(0, 0, rul.interval)
+	case rul.unit == unitWeek:
+		return from.AddDate(0, 0, daysInWeek*rul.interval)
+	default:
+		return addMonths(from, rul.interval, from.Day())
+	}
+}
+
+// addMonths moves the time by months to the given day, it is clamped to the month end.
+func addMonths(from time.Time, months int, day int) time.Time {
+	first := time.Date(from.Year(), from.Month()+time.Month(months), 1,
+		from.Hour(), from.Minute(), from.Second(), from.Nanosecond(), from.Location())
+	last := first.AddDate(0, 1, -1).Day()
+
+	return first.AddDate(0, 0, min(day, last)-1)
+}
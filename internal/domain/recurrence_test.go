@@ -0,0 +1,106 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+func TestUnitNewRecurrence(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		raw string
+	}
+
+	type want struct {
+		recurrence domain.Recurrence
+		err        error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{name: "daily", args: args{raw: " Daily "}, want: want{recurrence: "daily"}},
+		{name: "weekly", args: args{raw: "weekly"}, want: want{recurrence: "weekly"}},
+		{name: "weekdays", args: args{raw: "weekly:thu, mon,thu"}, want: want{recurrence: "weekly:mon,thu"}},
+		{name: "monthly", args: args{raw: "monthly"}, want: want{recurrence: "monthly"}},
+		{name: "month day", args: args{raw: "monthly: 15"}, want: want{recurrence: "monthly:15"}},
+		{name: "every", args: args{raw: "every  2w"}, want: want{recurrence: "every 2w"}},
+		{name: "every single", args: args{raw: "every 1d"}, want: want{recurrence: "daily"}},
+		{name: "empty", args: args{raw: ""}, want: want{err: domain.ErrInvalidRecurrence}},
+		{name: "unknown", args: args{raw: "yearly"}, want: want{err: domain.ErrInvalidRecurrence}},
+		{name: "daily argument", args: args{raw: "daily:2"}, want: want{err: domain.ErrInvalidRecurrence}},
+		{name: "bad weekday", args: args{raw: "weekly:mon,xyz"}, want: want{err: domain.ErrInvalidRecurrence}},
+		{name: "bad month day", args: args{raw: "monthly:32"}, want: want{err: domain.ErrInvalidRecurrence}},
+		{name: "bad interval", args: args{raw: "every 0d"}, want: want{err: domain.ErrInvalidRecurrence}},
+		{name: "bad unit", args: args{raw: "every 2y"}, want: want{err: domain.ErrInvalidRecurrence}},
+		{name: "no interval", args: args{raw: "every "}, want: want{err: domain.ErrInvalidRecurrence}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := domain.NewRecurrence(test.args.raw)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("NewRecurrence() error = %v, want = %v", err, test.want.err)
+			}
+
+			if got != test.want.recurrence {
+				t.Errorf("NewRecurrence() got = %v, want = %v", got, test.want.recurrence)
+			}
+		})
+	}
+}
+
+func TestUnitRecurrenceNext(t *testing.T) {
+	t.Parallel()
+
+	// friday
+	from := time.Date(2025, 1, 31, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		recurrence domain.Recurrence
+		want       time.Time
+	}{
+		{name: "daily", recurrence: "daily", want: time.Date(2025, 2, 1, 9, 30, 0, 0, time.UTC)},
+		{name: "weekly", recurrence: "weekly", want: time.Date(2025, 2, 7, 9, 30, 0, 0, time.UTC)},
+		{name: "weekdays", recurrence: "weekly:mon,thu", want: time.Date(2025, 2, 3, 9, 30, 0, 0, time.UTC)},
+		{name: "same weekday", recurrence: "weekly:fri", want: time.Date(2025, 2, 7, 9, 30, 0, 0, time.UTC)},
+		{name: "monthly clamped", recurrence: "monthly", want: time.Date(2025, 2, 28, 9, 30, 0, 0, time.UTC)},
+		{name: "month day", recurrence: "monthly:15", want: time.Date(2025, 2, 15, 9, 30, 0, 0, time.UTC)},
+		{name: "month end", recurrence: "monthly:31", want: time.Date(2025, 2, 28, 9, 30, 0, 0, time.UTC)},
+		{name: "every days", recurrence: "every 3d", want: time.Date(2025, 2, 3, 9, 30, 0, 0, time.UTC)},
+		{name: "every weeks", recurrence: "every 2w", want: time.Date(2025, 2, 14, 9, 30, 0, 0, time.UTC)},
+		{name: "every months", recurrence: "every 2m", want: time.Date(2025, 3, 31, 9, 30, 0, 0, time.UTC)},
+		{name: "invalid", recurrence: "yearly", want: time.Time{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := test.recurrence.Next(from); !got.Equal(test.want) {
+				t.Errorf("Next() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnitRecurrenceNextLaterInMonth(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2025, 1, 10, 9, 30, 0, 0, time.UTC)
+	want := time.Date(2025, 1, 15, 9, 30, 0, 0, time.UTC)
+
+	if got := domain.Recurrence("monthly:15").Next(from); !got.Equal(want) {
+		t.Errorf("Next() got = %v, want = %v", got, want)
+	}
+}
@@ -0,0 +1,10 @@
+package domain
+
+type Schema struct {
+	Version int
+	Latest  int
+}
+
+func (s Schema) IsOutdated() bool {
+	return s.Version < s.Latest
+}
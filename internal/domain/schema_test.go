@@ -0,0 +1,37 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+func TestUnitSchemaIsOutdated(t *testing.T) {
+	t.Parallel()
+
+	type fields struct {
+		Version int
+		Latest  int
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{name: "outdated", fields: fields{Version: 0, Latest: 2}, want: true},
+		{name: "latest", fields: fields{Version: 2, Latest: 2}, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			schema := domain.Schema{Version: test.fields.Version, Latest: test.fields.Latest}
+
+			if got := schema.IsOutdated(); got != test.want {
+				t.Errorf("IsOutdated() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	searchNegation = '-'
+	searchQuote    = '"'
+	searchPrefix   = "*"
+)
+
+// the whole word is the best match and the middle of the word is the worst one.
+const (
+	substringWeight = 1
+	prefixWeight    = 2
+	wordWeight      = 3
+)
+
+// SearchTerm is the text to find ignoring the case: anywhere, or only at the start
+// of a word when written as "term*". The quoted phrase is the term with spaces.
+type SearchTerm struct {
+	Text   string
+	Prefix bool
+}
+
+// SearchQuery matches tasks containing all Include terms and none of Exclude terms
+// in the description or the reason of the transition.
+type SearchQuery struct {
+	Include []SearchTerm
+	Exclude []SearchTerm
+}
+
+// Span is the matched part of the text, in bytes.
+type Span struct {
+	Start int
+	End   int
+}
+
+// NewSearchQuery parses words, "word*" prefixes, "quoted phrases" and "-negated" terms of them.
+func NewSearchQuery(raw string) (SearchQuery, error) {
+	query := SearchQuery{Include: make([]SearchTerm, 0), Exclude: make([]SearchTerm, 0)}
+
+	for rest := strings.TrimSpace(raw); rest != ""; rest = strings.TrimLeftFunc(rest, unicode.IsSpace) {
+		negated := len(rest) > 1 && rest[0] == searchNegation && !unicode.IsSpace(rune(rest[1]))
+		if negated {
+			rest = rest[1:]
+		}
+
+		var (
+			term SearchTerm
+			err  error
+		)
+
+		term, rest, err = nextTerm(rest)
+		if err != nil {
+			return query, err
+		}
+
+		if negated {
+			query.Exclude = append(query.Exclude, term)
+		} else {
+			query.Include = append(query.Include, term)
+		}
+	}
+
+	if len(query.Include) == 0 && len(query.Exclude) == 0 {
+		return query, ErrInvalidQuery
+	}
+
+	return query, nil
+}
+
+// nextTerm cuts the quoted phrase or the word off the query.
+func nextTerm(query string) (SearchTerm, string, error) {
+	if query[0] == searchQuote {
+		phrase, rest, closed := strings.Cut(query[1:], string(searchQuote))
+		phrase = strings.Join(strings.Fields(phrase), " ")
+
+		if !closed || phrase == "" {
+			return SearchTerm{Text: "", Prefix: false}, "", ErrInvalidQuery
+		}
+
+		return SearchTerm{Text: phrase, Prefix: false}, rest, nil
+	}
+
+	end := strings.IndexFunc(query, unicode.IsSpace)
+	if end < 0 {
+		end = len(query)
+	}
+
+	word, prefix := strings.CutSuffix(query[:end], searchPrefix)
+	if word == "" {
+		return SearchTerm{Text: "", Prefix: false}, "", ErrInvalidQuery
+	}
+
+	return SearchTerm{Text: word, Prefix: prefix}, query[end:], nil
+}
+
+func (q SearchQuery) Match(task Task) bool {
+	texts := task.searchable()
+
+	for _, term := range q.Include {
+		if !term.foundIn(texts) {
+			return false
+		}
+	}
+
+	for _, term := range q.Exclude {
+		if term.foundIn(texts) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Score ranks the task by the number of the matches and how good they are,
+// longer phrases count more than single words.
+func (q SearchQuery) Score(task Task) int {
+	score := 0
+
+	for _, text := range task.searchable() {
+		for _, term := range q.Include {
+			words := len(strings.Fields(term.Text))
+
+			for _, span := range term.spans(text) {
+				score += words * weight(text, span)
+			}
+		}
+	}
+
+	return score
+}
+
+// Highlight gives the sorted parts of the text matched by the Include terms, overlapping ones are merged.
+func (q SearchQuery) Highlight(text string) []Span {
+	spans := make([]Span, 0)
+	for _, term := range q.Include {
+		spans = append(spans, term.spans(text)...)
+	}
+
+	slices.SortFunc(spans, func(a, b Span) int { return cmp.Compare(a.Start, b.Start) })
+
+	merged := make([]Span, 0, len(spans))
+
+	for _, span := range spans {
+		last := len(merged) - 1
+		if last >= 0 && span.Start <= merged[last].End {
+			merged[last].End = max(merged[last].End, span.End)
+
+			continue
+		}
+
+		merged = append(merged, span)
+	}
+
+	return merged
+}
+
+func (t Task) searchable() []string {
+	if t.Transition == nil || t.Transition.Reason == "" {
+		return []string{t.Description}
+	}
+
+	return []string{t.Description, t.Transition.Reason}
+}
+
+func (s SearchTerm) foundIn(texts []string) bool {
+	for _, text := range texts {
+		if len(s.spans(text)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// spans finds all the term occurrences in the text that do not overlap.
+func (s SearchTerm) spans(text string) []Span {
+	spans := make([]Span, 0)
+
+	for start := 0; start < len(text); {
+		end, ok := foldPrefix(text[start:], s.Text)
+		if ok && (!s.Prefix || isWordStart(text, start)) {
+			spans = append(spans, Span{Start: start, End: start + end})
+			start += end
+
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(text[start:])
+		start += size
+	}
+
+	return spans
+}
+
+func weight(text string, span Span) int {
+	switch {
+	case isWordStart(text, span.Start) && isWordEnd(text, span.End):
+		return wordWeight
+	case isWordStart(text, span.Start):
+		return prefixWeight
+	default:
+		return substringWeight
+	}
+}
+
+// foldPrefix tells if the text starts with the prefix ignoring the case and how long the match is in the text.
+func foldPrefix(text, prefix string) (int, bool) {
+	end := 0
+
+	for _, want := range prefix {
+		got, size := utf8.DecodeRuneInString(text[end:])
+		if size == 0 || unicode.ToLower(got) != unicode.ToLower(want) {
+			return 0, false
+		}
+
+		end += size
+	}
+
+	return end, true
+}
+
+func isWordStart(text string, idx int) bool {
+	char, _ := utf8.DecodeLastRuneInString(text[:idx])
+
+	return idx == 0 || !isWordRune(char)
+}
+
+func isWordEnd(text string, idx int) bool {
+	char, _ := utf8.DecodeRuneInString(text[idx:])
+
+	return idx == len(text) || !isWordRune(char)
+}
+
+func isWordRune(char rune) bool {
+	return unicode.IsLetter(char) || unicode.IsDigit(char)
+}
@@ -0,0 +1,167 @@
+package domain_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+func TestUnitNewSearchQuery(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		raw string
+	}
+
+	type want struct {
+		query domain.SearchQuery
+		err   error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "words",
+			args: args{raw: " Deploy  api "},
+			want: want{query: domain.SearchQuery{
+				Include: []domain.SearchTerm{{Text: "Deploy"}, {Text: "api"}},
+				Exclude: []domain.SearchTerm{},
+			}},
+		},
+		{
+			name: "prefix and phrase",
+			args: args{raw: `dep* "release   notes"`},
+			want: want{query: domain.SearchQuery{
+				Include: []domain.SearchTerm{{Text: "dep", Prefix: true}, {Text: "release notes"}},
+				Exclude: []domain.SearchTerm{},
+			}},
+		},
+		{
+			name: "negated",
+			args: args{raw: `- -draft -"on hold"`},
+			want: want{query: domain.SearchQuery{
+				Include: []domain.SearchTerm{{Text: "-"}},
+				Exclude: []domain.SearchTerm{{Text: "draft"}, {Text: "on hold"}},
+			}},
+		},
+		{name: "empty", args: args{raw: "  "}, want: want{err: domain.ErrInvalidQuery}},
+		{name: "open quote", args: args{raw: `"release notes`}, want: want{err: domain.ErrInvalidQuery}},
+		{name: "empty phrase", args: args{raw: `" "`}, want: want{err: domain.ErrInvalidQuery}},
+		{name: "bare prefix", args: args{raw: "api *"}, want: want{err: domain.ErrInvalidQuery}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := domain.NewSearchQuery(test.args.raw)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("NewSearchQuery() error = %v, want = %v", err, test.want.err)
+			}
+
+			if err == nil && !reflect.DeepEqual(got, test.want.query) {
+				t.Errorf("NewSearchQuery() got = %v, want = %v", got, test.want.query)
+			}
+		})
+	}
+}
+
+func TestUnitSearchQueryMatch(t *testing.T) {
+	t.Parallel()
+
+	task := domain.Task{
+		Description: "Deploy the API to staging",
+		Transition:  &domain.Transition{Reason: "Rollback after the outage"},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{name: "substring", query: "PLOY", want: true},
+		{name: "all words", query: "deploy api", want: true},
+		{name: "missing word", query: "deploy prod", want: false},
+		{name: "word prefix", query: "stag*", want: true},
+		{name: "not a word prefix", query: "tag*", want: false},
+		{name: "phrase", query: `"the api"`, want: true},
+		{name: "phrase out of order", query: `"api the"`, want: false},
+		{name: "reason", query: "outage", want: true},
+		{name: "negated", query: "deploy -staging", want: false},
+		{name: "negated missing", query: "deploy -prod", want: true},
+		{name: "only negated", query: "-prod", want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := domain.NewSearchQuery(test.query)
+			if err != nil {
+				t.Fatalf("NewSearchQuery() error = %v, want = %v", err, nil)
+			}
+
+			if got := query.Match(task); got != test.want {
+				t.Errorf("Match() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnitSearchQueryScore(t *testing.T) {
+	t.Parallel()
+
+	query, _ := domain.NewSearchQuery("api")
+
+	tests := []struct {
+		name string
+		task domain.Task
+		want int
+	}{
+		{name: "no match", task: domain.Task{Description: "deploy"}, want: 0},
+		{name: "substring", task: domain.Task{Description: "rapid"}, want: 1},
+		{name: "prefix", task: domain.Task{Description: "apis"}, want: 2},
+		{name: "word", task: domain.Task{Description: "the API"}, want: 3},
+		{name: "twice", task: domain.Task{Description: "api and rapid"}, want: 4},
+		{
+			name: "reason",
+			task: domain.Task{Description: "api", Transition: &domain.Transition{Reason: "api"}},
+			want: 6,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := query.Score(test.task); got != test.want {
+				t.Errorf("Score() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+
+	phrase, _ := domain.NewSearchQuery(`"the api"`)
+
+	if got, want := phrase.Score(domain.Task{Description: "the api"}), 6; got != want {
+		t.Errorf("Score() got = %v, want = %v", got, want)
+	}
+}
+
+func TestUnitSearchQueryHighlight(t *testing.T) {
+	t.Parallel()
+
+	query, _ := domain.NewSearchQuery(`de* "ploy the" Ünï`)
+
+	got := query.Highlight("Deploy the ÜNÏcode")
+	want := []domain.Span{{Start: 0, End: 10}, {Start: 11, End: 16}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Highlight() got = %v, want = %v", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+type SortField string
+
+const (
+	SortByID       SortField = "id"
+	SortByCreated  SortField = "created"
+	SortByUpdated  SortField = "updated"
+	SortByStatus   SortField = "status"
+	SortByPriority SortField = "priority"
+	SortByDue      SortField = "due"
+)
+
+func NewSortField(raw string) (SortField, error) {
+	field := SortField(strings.ToLower(strings.TrimSpace(raw)))
+	if !slices.Contains(AllSortFields(), field) {
+		return "", ErrInvalidSort
+	}
+
+	return field, nil
+}
+
+func AllSortFields() []SortField {
+	return []SortField{SortByID, SortByCreated, SortByUpdated, SortByStatus, SortByPriority, SortByDue}
+}
+
+// ListOptions sorts the tasks by the field, the last update when it is empty, and gives the page of them.
+// Ties are the recently updated first, then by the ID. Statuses are sorted in the given order, unknown
+// ones go last, and the tasks without the due date go last in any direction. The page of zero Limit
+// has all the tasks from the Offset, Reverse turns the page over.
+type ListOptions struct {
+	Sort     SortField
+	Desc     bool
+	Statuses []Status
+	Offset   int
+	Limit    int
+	Reverse  bool
+}
+
+// Apply gives the sorted page of the tasks, the given slice is not changed.
+func (o ListOptions) Apply(tasks []*Task) []*Task {
+	sorted := slices.Clone(tasks)
+	slices.SortStableFunc(sorted, o.compare)
+
+	start := min(o.Offset, len(sorted))
+	end := len(sorted)
+
+	if o.Limit > 0 {
+		end = min(start+o.Limit, end)
+	}
+
+	page := sorted[start:end]
+	if o.Reverse {
+		slices.Reverse(page)
+	}
+
+	return page
+}
+
+func (o ListOptions) compare(a, b *Task) int {
+	if o.Sort == SortByDue && a.HasDue() != b.HasDue() {
+		if a.HasDue() {
+			return -1
+		}
+
+		return 1
+	}
+
+	var order int
+
+	switch o.Sort {
+	case SortByID:
+		order = cmp.Compare(a.ID, b.ID)
+	case SortByCreated:
+		order = a.CreatedAt.Compare(b.CreatedAt)
+	case SortByStatus:
+		order = cmp.Compare(o.statusRank(a.Status), o.statusRank(b.Status))
+	case SortByPriority:
+		order = cmp.Compare(a.Priority.Weight(), b.Priority.Weight())
+	case SortByDue:
+		order = a.DueAt.Compare(b.DueAt)
+	default:
+		order = a.UpdatedAt.Compare(b.UpdatedAt)
+	}
+
+	if o.Desc {
+		order = -order
+	}
+
+	return cmp.Or(order, b.UpdatedAt.Compare(a.UpdatedAt), cmp.Compare(a.ID, b.ID))
+}
+
+func (o ListOptions) statusRank(status Status) int {
+	rank := slices.Index(o.Statuses, status)
+	if rank < 0 {
+		return len(o.Statuses)
+	}
+
+	return rank
+}
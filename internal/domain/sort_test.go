@@ -0,0 +1,117 @@
+package domain_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+func TestUnitNewSortField(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		raw  string
+		want domain.SortField
+		err  error
+	}{
+		{name: "known", raw: " Due ", want: domain.SortByDue},
+		{name: "unknown", raw: "title", err: domain.ErrInvalidSort},
+		{name: "empty", raw: "", err: domain.ErrInvalidSort},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := domain.NewSortField(test.raw)
+
+			if !errors.Is(err, test.err) {
+				t.Fatalf("NewSortField() error = %v, want = %v", err, test.err)
+			}
+
+			if got != test.want {
+				t.Errorf("NewSortField() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnitListOptionsApply(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []*domain.Task{
+		{
+			ID: 1, Status: domain.StatusDone, Priority: domain.PriorityLow,
+			CreatedAt: day, UpdatedAt: day.Add(time.Hour), DueAt: day.AddDate(0, 0, 2),
+		},
+		{
+			ID: 2, Status: domain.StatusTodo, Priority: domain.PriorityHigh,
+			CreatedAt: day.Add(time.Hour), UpdatedAt: day.Add(time.Hour),
+		},
+		{
+			ID: 3, Status: domain.StatusProgress, Priority: domain.PriorityMedium,
+			CreatedAt: day.Add(-time.Hour), UpdatedAt: day.Add(2 * time.Hour), DueAt: day.AddDate(0, 0, 1),
+		},
+		{
+			ID: 4, Status: "review", Priority: domain.PriorityHigh,
+			CreatedAt: day.Add(2 * time.Hour), UpdatedAt: day,
+		},
+	}
+	statuses := []domain.Status{domain.StatusTodo, domain.StatusProgress, domain.StatusDone}
+
+	tests := []struct {
+		name string
+		opts domain.ListOptions
+		want []uint64
+	}{
+		{name: "default", opts: domain.ListOptions{}, want: []uint64{4, 1, 2, 3}},
+		{name: "recently updated", opts: domain.ListOptions{Desc: true}, want: []uint64{3, 1, 2, 4}},
+		{name: "id", opts: domain.ListOptions{Sort: domain.SortByID, Desc: true}, want: []uint64{4, 3, 2, 1}},
+		{name: "created", opts: domain.ListOptions{Sort: domain.SortByCreated}, want: []uint64{3, 1, 2, 4}},
+		{
+			name: "status",
+			opts: domain.ListOptions{Sort: domain.SortByStatus, Statuses: statuses},
+			want: []uint64{2, 3, 1, 4},
+		},
+		{
+			name: "priority ties",
+			opts: domain.ListOptions{Sort: domain.SortByPriority, Desc: true},
+			want: []uint64{2, 4, 3, 1},
+		},
+		{name: "due", opts: domain.ListOptions{Sort: domain.SortByDue}, want: []uint64{3, 1, 2, 4}},
+		{name: "due desc", opts: domain.ListOptions{Sort: domain.SortByDue, Desc: true}, want: []uint64{1, 3, 2, 4}},
+		{name: "page", opts: domain.ListOptions{Sort: domain.SortByID, Offset: 1, Limit: 2}, want: []uint64{2, 3}},
+		{
+			name: "reversed page",
+			opts: domain.ListOptions{Sort: domain.SortByID, Offset: 2, Limit: 5, Reverse: true},
+			want: []uint64{4, 3},
+		},
+		{name: "out of range", opts: domain.ListOptions{Offset: 9}, want: []uint64{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := test.opts.Apply(tasks)
+
+			ids := make([]uint64, len(got))
+			for idx, task := range got {
+				ids[idx] = task.ID
+			}
+
+			if !reflect.DeepEqual(ids, test.want) {
+				t.Errorf("Apply() got = %v, want = %v", ids, test.want)
+			}
+		})
+	}
+
+	if tasks[0].ID != 1 || tasks[3].ID != 4 {
+		t.Errorf("Apply() got = %v, want = %v", tasks, "unchanged tasks")
+	}
+}
@@ -3,15 +3,16 @@ package domain
 type Status string
 
 const (
-	StatusTodo     Status = "todo"
-	StatusDone     Status = "done"
-	StatusProgress Status = "progress"
+	StatusTodo      Status = "todo"
+	StatusDone      Status = "done"
+	StatusProgress  Status = "progress"
+	StatusCancelled Status = "cancelled"
 )
 
 func NewStatus(raw string) (Status, error) {
 	s := Status(raw)
 	switch s {
-	case StatusTodo, StatusDone, StatusProgress:
+	case StatusTodo, StatusDone, StatusProgress, StatusCancelled:
 		return s, nil
 	default:
 		return "", ErrInvalidStatus
@@ -19,5 +20,5 @@ func NewStatus(raw string) (Status, error) {
 }
 
 func AllStatus() []Status {
-	return []Status{StatusTodo, StatusProgress, StatusDone}
+	return []Status{StatusTodo, StatusProgress, StatusDone, StatusCancelled}
 }
@@ -27,6 +27,7 @@ func TestUnitNewStatus(t *testing.T) {
 		{name: "todo", args: args{raw: "todo"}, want: want{status: domain.StatusTodo}},
 		{name: "done", args: args{raw: "done"}, want: want{status: domain.StatusDone}},
 		{name: "progress", args: args{raw: "progress"}, want: want{status: domain.StatusProgress}},
+		{name: "cancelled", args: args{raw: "cancelled"}, want: want{status: domain.StatusCancelled}},
 		{name: "invalid", args: args{raw: "invalid"}, want: want{err: domain.ErrInvalidStatus}},
 	}
 
@@ -51,7 +52,7 @@ func TestUnitAllStatus(t *testing.T) {
 	t.Parallel()
 
 	got := domain.AllStatus()
-	want := []string{"todo", "progress", "done"}
+	want := []string{"todo", "progress", "done", "cancelled"}
 
 	for idx, status := range got {
 		if string(status) != want[idx] {
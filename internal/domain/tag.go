@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	tagPrefix    = "+"
+	maxTagLength = 32
+)
+
+// NewTag normalizes the tag: lowercase without the leading "+", starts with a letter
+// and contains only letters, digits, "-" or "_".
+func NewTag(raw string) (string, error) {
+	tag := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(raw), tagPrefix))
+
+	if len(tag) > maxTagLength || !isName(tag) {
+		return "", ErrInvalidTag
+	}
+
+	return tag, nil
+}
+
+// isName tells if the string starts with a letter and contains only letters, digits, "-" or "_".
+func isName(str string) bool {
+	if str == "" {
+		return false
+	}
+
+	for idx, char := range str {
+		switch {
+		case unicode.IsLetter(char):
+		case idx > 0 && (unicode.IsDigit(char) || char == '-' || char == '_'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewTags normalizes all tags, the result is sorted and has no duplicates.
+func NewTags(raw []string) ([]string, error) {
+	tags := make([]string, 0, len(raw))
+
+	for _, one := range raw {
+		tag, err := NewTag(one)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	slices.Sort(tags)
+
+	return slices.Compact(tags), nil
+}
+
+// TagQuery matches tasks having all Include tags and none of Exclude tags.
+type TagQuery struct {
+	Include []string
+	Exclude []string
+}
+
+func (q TagQuery) IsEmpty() bool {
+	return len(q.Include) == 0 && len(q.Exclude) == 0
+}
+
+func (q TagQuery) Match(task Task) bool {
+	for _, tag := range q.Include {
+		if !task.HasTag(tag) {
+			return false
+		}
+	}
+
+	for _, tag := range q.Exclude {
+		if task.HasTag(tag) {
+			return false
+		}
+	}
+
+	return true
+}
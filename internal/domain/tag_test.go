@@ -0,0 +1,116 @@
+package domain_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+func TestUnitNewTag(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		raw string
+	}
+
+	type want struct {
+		tag string
+		err error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{name: "plain", args: args{raw: "backend"}, want: want{tag: "backend"}},
+		{name: "with prefix", args: args{raw: "+Infra"}, want: want{tag: "infra"}},
+		{name: "with symbols", args: args{raw: " on-call_2 "}, want: want{tag: "on-call_2"}},
+		{name: "empty", args: args{raw: "+"}, want: want{err: domain.ErrInvalidTag}},
+		{name: "starts with digit", args: args{raw: "2fa"}, want: want{err: domain.ErrInvalidTag}},
+		{name: "bad symbol", args: args{raw: "a.b"}, want: want{err: domain.ErrInvalidTag}},
+		{name: "too long", args: args{raw: strings.Repeat("a", 33)}, want: want{err: domain.ErrInvalidTag}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := domain.NewTag(test.args.raw)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("NewTag() error = %v, want = %v", err, test.want.err)
+			}
+
+			if got != test.want.tag {
+				t.Errorf("NewTag() got = %v, want = %v", got, test.want.tag)
+			}
+		})
+	}
+}
+
+func TestUnitNewTags(t *testing.T) {
+	t.Parallel()
+
+	got, err := domain.NewTags([]string{"+infra", "Backend", "infra"})
+	if err != nil {
+		t.Fatalf("NewTags() error = %v, want = %v", err, nil)
+	}
+
+	if want := []string{"backend", "infra"}; !slices.Equal(got, want) {
+		t.Errorf("NewTags() got = %v, want = %v", got, want)
+	}
+
+	_, err = domain.NewTags([]string{"backend", "1"})
+	if !errors.Is(err, domain.ErrInvalidTag) {
+		t.Errorf("NewTags() error = %v, want = %v", err, domain.ErrInvalidTag)
+	}
+}
+
+func TestUnitTagQueryIsEmpty(t *testing.T) {
+	t.Parallel()
+
+	if got := (domain.TagQuery{}).IsEmpty(); !got {
+		t.Errorf("IsEmpty() got = %v, want = %v", got, true)
+	}
+
+	if got := (domain.TagQuery{Exclude: []string{"infra"}}).IsEmpty(); got {
+		t.Errorf("IsEmpty() got = %v, want = %v", got, false)
+	}
+}
+
+func TestUnitTagQueryMatch(t *testing.T) {
+	t.Parallel()
+
+	task := domain.Task{Tags: []string{"backend", "oncall"}}
+
+	tests := []struct {
+		name  string
+		query domain.TagQuery
+		want  bool
+	}{
+		{name: "empty", query: domain.TagQuery{}, want: true},
+		{name: "include", query: domain.TagQuery{Include: []string{"backend", "oncall"}}, want: true},
+		{name: "include missing", query: domain.TagQuery{Include: []string{"backend", "infra"}}, want: false},
+		{name: "exclude", query: domain.TagQuery{Exclude: []string{"infra"}}, want: true},
+		{name: "exclude present", query: domain.TagQuery{Exclude: []string{"oncall"}}, want: false},
+		{
+			name:  "both",
+			query: domain.TagQuery{Include: []string{"backend"}, Exclude: []string{"infra"}},
+			want:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := test.query.Match(task); got != test.want {
+				t.Errorf("Match() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
@@ -1,15 +1,79 @@
 package domain
 
-import "time"
+import (
+	"slices"
+	"strings"
+	"time"
+)
 
 type Task struct {
 	ID          uint64
 	Description string
 	Status      Status
+	Priority    Priority
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	DueAt       time.Time
+	DeletedAt   time.Time
+	ArchivedAt  time.Time
+	Tags        []string
+	Project     string
+	ParentID    uint64
+	BlockedBy   []uint64
+	Recurrence  Recurrence
+	RecurOf     uint64
+	Transition  *Transition
+	History     []Change
+}
+
+// Transition records the status change made on purpose out of the workflow,
+// like reopening or cancelling the task, together with the reason why.
+type Transition struct {
+	From   Status
+	To     Status
+	Reason string
+	At     time.Time
 }
 
 func (t Task) IsDone() bool {
 	return t.Status == StatusDone
 }
+
+func (t Task) HasDue() bool {
+	return !t.DueAt.IsZero()
+}
+
+func (t Task) IsOverdue(now time.Time) bool {
+	return t.HasDue() && !t.IsDone() && now.After(t.DueAt)
+}
+
+// IsDeleted tells if the task is in the trash.
+func (t Task) IsDeleted() bool {
+	return !t.DeletedAt.IsZero()
+}
+
+// IsArchived tells if the task is moved to the archive.
+func (t Task) IsArchived() bool {
+	return !t.ArchivedAt.IsZero()
+}
+
+func (t Task) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, tag)
+}
+
+// InProject tells if the task belongs to the project or any of its sub-projects.
+func (t Task) InProject(project string) bool {
+	return t.Project == project || strings.HasPrefix(t.Project, project+projectSeparator)
+}
+
+func (t Task) HasParent() bool {
+	return t.ParentID != 0
+}
+
+func (t Task) DependsOn(tid uint64) bool {
+	return slices.Contains(t.BlockedBy, tid)
+}
+
+func (t Task) IsRecurring() bool {
+	return t.Recurrence != ""
+}
@@ -2,6 +2,7 @@ package domain_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/therenotomorrow/tasker/internal/domain"
 )
@@ -34,3 +35,170 @@ func TestUnitTaskIsDone(t *testing.T) {
 		})
 	}
 }
+
+func TestUnitTaskHasDue(t *testing.T) {
+	t.Parallel()
+
+	type fields struct {
+		DueAt time.Time
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{name: "with due", fields: fields{DueAt: time.Now()}, want: true},
+		{name: "without due", fields: fields{DueAt: time.Time{}}, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			task := &domain.Task{DueAt: test.fields.DueAt}
+
+			if got := task.HasDue(); got != test.want {
+				t.Errorf("HasDue() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnitTaskIsDeleted(t *testing.T) {
+	t.Parallel()
+
+	if got := (domain.Task{DeletedAt: time.Now()}).IsDeleted(); !got {
+		t.Errorf("IsDeleted() got = %v, want = %v", got, true)
+	}
+
+	if got := (domain.Task{}).IsDeleted(); got {
+		t.Errorf("IsDeleted() got = %v, want = %v", got, false)
+	}
+}
+
+func TestUnitTaskIsArchived(t *testing.T) {
+	t.Parallel()
+
+	if got := (domain.Task{ArchivedAt: time.Now()}).IsArchived(); !got {
+		t.Errorf("IsArchived() got = %v, want = %v", got, true)
+	}
+
+	if got := (domain.Task{}).IsArchived(); got {
+		t.Errorf("IsArchived() got = %v, want = %v", got, false)
+	}
+}
+
+func TestUnitTaskIsOverdue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 5, 8, 12, 0, 0, 0, time.UTC)
+
+	type fields struct {
+		Status domain.Status
+		DueAt  time.Time
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{name: "overdue", fields: fields{Status: domain.StatusTodo, DueAt: now.Add(-time.Hour)}, want: true},
+		{name: "in time", fields: fields{Status: domain.StatusTodo, DueAt: now.Add(time.Hour)}, want: false},
+		{name: "done", fields: fields{Status: domain.StatusDone, DueAt: now.Add(-time.Hour)}, want: false},
+		{name: "without due", fields: fields{Status: domain.StatusTodo}, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			task := &domain.Task{Status: test.fields.Status, DueAt: test.fields.DueAt}
+
+			if got := task.IsOverdue(now); got != test.want {
+				t.Errorf("IsOverdue() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnitTaskHasTag(t *testing.T) {
+	t.Parallel()
+
+	task := &domain.Task{Tags: []string{"backend", "infra"}}
+
+	if got := task.HasTag("infra"); !got {
+		t.Errorf("HasTag() got = %v, want = %v", got, true)
+	}
+
+	if got := task.HasTag("oncall"); got {
+		t.Errorf("HasTag() got = %v, want = %v", got, false)
+	}
+}
+
+func TestUnitTaskInProject(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		project string
+		want    bool
+	}{
+		{name: "same", project: "platform.auth", want: true},
+		{name: "parent", project: "platform", want: true},
+		{name: "child", project: "platform.auth.sso", want: false},
+		{name: "same prefix", project: "plat", want: false},
+		{name: "other", project: "billing", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			task := &domain.Task{Project: "platform.auth"}
+
+			if got := task.InProject(test.project); got != test.want {
+				t.Errorf("InProject() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnitTaskHasParent(t *testing.T) {
+	t.Parallel()
+
+	if got := (&domain.Task{ParentID: 1}).HasParent(); !got {
+		t.Errorf("HasParent() got = %v, want = %v", got, true)
+	}
+
+	if got := (&domain.Task{}).HasParent(); got {
+		t.Errorf("HasParent() got = %v, want = %v", got, false)
+	}
+}
+
+func TestUnitTaskDependsOn(t *testing.T) {
+	t.Parallel()
+
+	task := &domain.Task{BlockedBy: []uint64{2, 3}}
+
+	if got := task.DependsOn(3); !got {
+		t.Errorf("DependsOn() got = %v, want = %v", got, true)
+	}
+
+	if got := task.DependsOn(4); got {
+		t.Errorf("DependsOn() got = %v, want = %v", got, false)
+	}
+}
+
+func TestUnitTaskIsRecurring(t *testing.T) {
+	t.Parallel()
+
+	if got := (&domain.Task{Recurrence: "daily"}).IsRecurring(); !got {
+		t.Errorf("IsRecurring() got = %v, want = %v", got, true)
+	}
+
+	if got := new(domain.Task).IsRecurring(); got {
+		t.Errorf("IsRecurring() got = %v, want = %v", got, false)
+	}
+}
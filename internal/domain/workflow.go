@@ -0,0 +1,144 @@
+package domain
+
+import "slices"
+
+// cancelledIndex is the place of the cancellation among the terminal statuses, the completion goes first.
+const cancelledIndex = 1
+
+// Workflow declares the task statuses, the first one is given to new tasks. Terminal
+// statuses close the task for good, the first of them is the completion of the task and
+// the second one is its cancellation.
+// Transitions list where every status could move, staying is always fine.
+type Workflow struct {
+	statuses    []Status
+	terminal    []Status
+	transitions map[Status][]Status
+}
+
+func NewWorkflow(statuses []string, terminal []string, transitions map[string][]string) (*Workflow, error) {
+	flow := &Workflow{
+		statuses:    make([]Status, 0, len(statuses)),
+		terminal:    make([]Status, 0, len(terminal)),
+		transitions: make(map[Status][]Status, len(transitions)),
+	}
+
+	for _, raw := range statuses {
+		status := Status(raw)
+		if !isName(raw) || slices.Contains(flow.statuses, status) {
+			return nil, ErrInvalidWorkflow
+		}
+
+		flow.statuses = append(flow.statuses, status)
+	}
+
+	for _, raw := range terminal {
+		status := Status(raw)
+		if !slices.Contains(flow.statuses, status) || status == flow.Initial() {
+			return nil, ErrInvalidWorkflow
+		}
+
+		flow.terminal = append(flow.terminal, status)
+	}
+
+	if len(flow.terminal) == 0 {
+		return nil, ErrInvalidWorkflow
+	}
+
+	for from, tos := range transitions {
+		if !slices.Contains(flow.statuses, Status(from)) || flow.IsTerminal(Status(from)) {
+			return nil, ErrInvalidWorkflow
+		}
+
+		for _, to := range tos {
+			if !slices.Contains(flow.statuses, Status(to)) {
+				return nil, ErrInvalidWorkflow
+			}
+
+			flow.transitions[Status(from)] = append(flow.transitions[Status(from)], Status(to))
+		}
+	}
+
+	return flow, nil
+}
+
+// DefaultWorkflow is the "todo", "progress" and "done" statuses moving freely till done,
+// tasks are "cancelled" only on purpose, so no status moves there.
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		statuses: AllStatus(),
+		terminal: []Status{StatusDone, StatusCancelled},
+		transitions: map[Status][]Status{
+			StatusTodo:     {StatusProgress, StatusDone},
+			StatusProgress: {StatusTodo, StatusDone},
+		},
+	}
+}
+
+func (w *Workflow) Statuses() []Status {
+	return slices.Clone(w.statuses)
+}
+
+func (w *Workflow) Initial() Status {
+	if len(w.statuses) == 0 {
+		return ""
+	}
+
+	return w.statuses[0]
+}
+
+func (w *Workflow) Status(raw string) (Status, error) {
+	status := Status(raw)
+	if !slices.Contains(w.statuses, status) {
+		return "", ErrInvalidStatus
+	}
+
+	return status, nil
+}
+
+// Completed is the terminal status of the finished task, recurring tasks come again and parents close by it.
+func (w *Workflow) Completed() Status {
+	if len(w.terminal) == 0 {
+		return ""
+	}
+
+	return w.terminal[0]
+}
+
+// Cancelled is the terminal status of the task closed without finishing it, empty when the workflow has none.
+func (w *Workflow) Cancelled() Status {
+	if len(w.terminal) < cancelledIndex+1 {
+		return ""
+	}
+
+	return w.terminal[cancelledIndex]
+}
+
+func (w *Workflow) IsTerminal(status Status) bool {
+	return slices.Contains(w.terminal, status)
+}
+
+// Reachable lists the initial status with all the statuses some transition moves to.
+func (w *Workflow) Reachable() []Status {
+	return slices.DeleteFunc(w.Statuses(), func(status Status) bool {
+		if status == w.Initial() {
+			return false
+		}
+
+		for _, tos := range w.transitions {
+			if slices.Contains(tos, status) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// Allowed lists the statuses the given one could move to.
+func (w *Workflow) Allowed(from Status) []Status {
+	return slices.Clone(w.transitions[from])
+}
+
+func (w *Workflow) CanMove(from Status, to Status) bool {
+	return (from == to && !w.IsTerminal(from)) || slices.Contains(w.transitions[from], to)
+}
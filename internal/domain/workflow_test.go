@@ -0,0 +1,225 @@
+package domain_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+func TestUnitNewWorkflow(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		statuses    []string
+		terminal    []string
+		transitions map[string][]string
+	}
+
+	statuses := []string{"todo", "review", "done", "cancelled"}
+
+	tests := []struct {
+		name string
+		args args
+		want error
+	}{
+		{
+			name: "valid",
+			args: args{
+				statuses:    statuses,
+				terminal:    []string{"done", "cancelled"},
+				transitions: map[string][]string{"todo": {"review", "cancelled"}, "review": {"todo", "done"}},
+			},
+			want: nil,
+		},
+		{
+			name: "empty",
+			args: args{statuses: nil, terminal: nil, transitions: nil},
+			want: domain.ErrInvalidWorkflow,
+		},
+		{
+			name: "invalid status",
+			args: args{statuses: []string{"todo", "in review", "done"}, terminal: []string{"done"}},
+			want: domain.ErrInvalidWorkflow,
+		},
+		{
+			name: "duplicate status",
+			args: args{statuses: []string{"todo", "done", "todo"}, terminal: []string{"done"}},
+			want: domain.ErrInvalidWorkflow,
+		},
+		{
+			name: "unknown terminal",
+			args: args{statuses: statuses, terminal: []string{"done", "closed"}},
+			want: domain.ErrInvalidWorkflow,
+		},
+		{
+			name: "initial terminal",
+			args: args{statuses: statuses, terminal: []string{"todo", "done"}},
+			want: domain.ErrInvalidWorkflow,
+		},
+		{
+			name: "no terminal",
+			args: args{statuses: statuses, terminal: nil},
+			want: domain.ErrInvalidWorkflow,
+		},
+		{
+			name: "without done",
+			args: args{statuses: []string{"open", "shipped"}, terminal: []string{"shipped"}},
+			want: nil,
+		},
+		{
+			name: "unknown transition source",
+			args: args{
+				statuses:    statuses,
+				terminal:    []string{"done"},
+				transitions: map[string][]string{"closed": {"todo"}},
+			},
+			want: domain.ErrInvalidWorkflow,
+		},
+		{
+			name: "unknown transition target",
+			args: args{
+				statuses:    statuses,
+				terminal:    []string{"done"},
+				transitions: map[string][]string{"todo": {"closed"}},
+			},
+			want: domain.ErrInvalidWorkflow,
+		},
+		{
+			name: "transition from terminal",
+			args: args{
+				statuses:    statuses,
+				terminal:    []string{"done"},
+				transitions: map[string][]string{"done": {"todo"}},
+			},
+			want: domain.ErrInvalidWorkflow,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := domain.NewWorkflow(test.args.statuses, test.args.terminal, test.args.transitions)
+
+			if !errors.Is(err, test.want) {
+				t.Fatalf("NewWorkflow() error = %v, want = %v", err, test.want)
+			}
+
+			if (got == nil) == (test.want == nil) {
+				t.Errorf("NewWorkflow() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnitWorkflow(t *testing.T) {
+	t.Parallel()
+
+	flow, _ := domain.NewWorkflow(
+		[]string{"todo", "review", "done", "cancelled"},
+		[]string{"done", "cancelled"},
+		map[string][]string{"todo": {"review", "cancelled"}, "review": {"todo", "done"}},
+	)
+
+	want := []domain.Status{"todo", "review", "done", "cancelled"}
+	if got := flow.Statuses(); !slices.Equal(got, want) {
+		t.Errorf("Statuses() got = %v, want = %v", got, want)
+	}
+
+	if got := flow.Initial(); got != "todo" {
+		t.Errorf("Initial() got = %v, want = %v", got, "todo")
+	}
+
+	if got, err := flow.Status("review"); got != "review" || err != nil {
+		t.Errorf("Status() got = %v, error = %v, want = %v", got, err, "review")
+	}
+
+	if _, err := flow.Status("progress"); !errors.Is(err, domain.ErrInvalidStatus) {
+		t.Errorf("Status() error = %v, want = %v", err, domain.ErrInvalidStatus)
+	}
+
+	if got := flow.IsTerminal("cancelled"); !got {
+		t.Errorf("IsTerminal() got = %v, want = %v", got, true)
+	}
+
+	if got := flow.Completed(); got != "done" {
+		t.Errorf("Completed() got = %v, want = %v", got, "done")
+	}
+
+	if got := flow.Cancelled(); got != "cancelled" {
+		t.Errorf("Cancelled() got = %v, want = %v", got, "cancelled")
+	}
+
+	single, _ := domain.NewWorkflow([]string{"todo", "done"}, []string{"done"}, nil)
+	if got := single.Cancelled(); got != "" {
+		t.Errorf("Cancelled() got = %v, want = %v", got, "")
+	}
+
+	if got := flow.Reachable(); !slices.Equal(got, want) {
+		t.Errorf("Reachable() got = %v, want = %v", got, want)
+	}
+
+	if got := flow.Allowed("todo"); !slices.Equal(got, []domain.Status{"review", "cancelled"}) {
+		t.Errorf("Allowed() got = %v, want = %v", got, []domain.Status{"review", "cancelled"})
+	}
+
+	moves := []struct {
+		from domain.Status
+		to   domain.Status
+		want bool
+	}{
+		{from: "todo", to: "review", want: true},
+		{from: "todo", to: "todo", want: true},
+		{from: "todo", to: "done", want: false},
+		{from: "review", to: "done", want: true},
+		{from: "done", to: "done", want: false},
+		{from: "done", to: "todo", want: false},
+	}
+
+	for _, move := range moves {
+		if got := flow.CanMove(move.from, move.to); got != move.want {
+			t.Errorf("CanMove(%v, %v) got = %v, want = %v", move.from, move.to, got, move.want)
+		}
+	}
+}
+
+func TestUnitDefaultWorkflow(t *testing.T) {
+	t.Parallel()
+
+	flow := domain.DefaultWorkflow()
+
+	if got := flow.Statuses(); !slices.Equal(got, domain.AllStatus()) {
+		t.Errorf("Statuses() got = %v, want = %v", got, domain.AllStatus())
+	}
+
+	if got := flow.Initial(); got != domain.StatusTodo {
+		t.Errorf("Initial() got = %v, want = %v", got, domain.StatusTodo)
+	}
+
+	if got := flow.Completed(); got != domain.StatusDone {
+		t.Errorf("Completed() got = %v, want = %v", got, domain.StatusDone)
+	}
+
+	if got := flow.Cancelled(); got != domain.StatusCancelled {
+		t.Errorf("Cancelled() got = %v, want = %v", got, domain.StatusCancelled)
+	}
+
+	reachable := []domain.Status{domain.StatusTodo, domain.StatusProgress, domain.StatusDone}
+	if got := flow.Reachable(); !slices.Equal(got, reachable) {
+		t.Errorf("Reachable() got = %v, want = %v", got, reachable)
+	}
+
+	if got := flow.CanMove(domain.StatusTodo, domain.StatusCancelled); got {
+		t.Errorf("CanMove() got = %v, want = %v", got, false)
+	}
+
+	if got := flow.CanMove(domain.StatusDone, domain.StatusTodo); got {
+		t.Errorf("CanMove() got = %v, want = %v", got, false)
+	}
+
+	if got := flow.CanMove(domain.StatusTodo, domain.StatusDone); !got {
+		t.Errorf("CanMove() got = %v, want = %v", got, true)
+	}
+}
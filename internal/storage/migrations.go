@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+// LatestVersion is the schema version of the task file written by this build.
+const LatestVersion = 14
+
+var ErrInvalidSchema = errors.New("invalid schema")
+
+type (
+	document  = map[string]any
+	migration func(doc document) (document, error)
+)
+
+// migrations[N] upgrades the document from version N to N+1, every new
+// migration goes together with the golden file in "test/data/schema".
+var migrations = [LatestVersion]migration{
+	migrateV0ToV1,
+	migrateV1ToV2,
+	migrateV2ToV3,
+	markVersion(4),  // tasks get the optional "dueAt"
+	markVersion(5),  // tasks get the optional "tags"
+	markVersion(6),  // tasks get the optional "project"
+	markVersion(7),  // tasks get the optional "parentID"
+	markVersion(8),  // tasks get the optional "blockedBy"
+	markVersion(9),  // tasks get the optional "recurrence" and "recurOf"
+	markVersion(10), // tasks get the optional "transition" and the "cancelled" status
+	markVersion(11), // tasks and the envelope get the optional "history"
+	markVersion(12), // the envelope gets the optional "journal"
+	markVersion(13), // the envelope gets the optional "trash" and tasks the "deletedAt"
+	markVersion(14), // tasks get the optional "archivedAt", the archive file is the same envelope
+}
+
+// markVersion is the migration for backward compatible changes like a new optional
+// field, the marker stops older builds from dropping the data they do not know.
+func markVersion(version int) migration {
+	return func(doc document) (document, error) {
+		doc["version"] = version
+
+		return doc, nil
+	}
+}
+
+func decodeDocument(data []byte) (document, int, error) {
+	var doc document
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&doc)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s schema error: %w", name, err)
+	}
+
+	origin, err := versionOf(doc)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if origin > LatestVersion {
+		return nil, 0, fmt.Errorf("%s schema error: %w", name, domain.ErrSchemaTooNew)
+	}
+
+	for version := origin; version < LatestVersion; version++ {
+		doc, err = migrations[version](doc)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s schema error: version %d: %w", name, version, err)
+		}
+	}
+
+	return doc, origin, nil
+}
+
+func versionOf(doc document) (int, error) {
+	raw, ok := doc["version"]
+	if !ok {
+		// versions before the marker are recognized by the shape
+		if _, ok = doc["tasks"]; ok {
+			return 1, nil
+		}
+
+		return 0, nil
+	}
+
+	number, ok := raw.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("%s schema error: %w: version %v", name, ErrInvalidSchema, raw)
+	}
+
+	version, err := strconv.Atoi(number.String())
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("%s schema error: %w: version %v", name, ErrInvalidSchema, raw)
+	}
+
+	return version, nil
+}
+
+// the bare tasks map becomes the envelope with the ID sequence.
+func migrateV0ToV1(doc document) (document, error) {
+	nextID := uint64(1)
+
+	for key := range doc {
+		tid, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: task key %q", ErrInvalidSchema, key)
+		}
+
+		nextID = max(nextID, tid+1)
+	}
+
+	if doc == nil {
+		doc = make(document)
+	}
+
+	return document{"nextID": nextID, "tasks": doc}, nil
+}
+
+// the envelope gets the explicit version marker.
+func migrateV1ToV2(doc document) (document, error) {
+	const version = 2
+
+	doc["version"] = version
+
+	return doc, nil
+}
+
+// tasks get the priority, the existing ones are of medium importance.
+func migrateV2ToV3(doc document) (document, error) {
+	const version = 3
+
+	tasks, ok := doc["tasks"].(document)
+	if !ok && doc["tasks"] != nil {
+		return nil, fmt.Errorf("%w: tasks %v", ErrInvalidSchema, doc["tasks"])
+	}
+
+	for key, raw := range tasks {
+		task, ok := raw.(document)
+		if !ok {
+			return nil, fmt.Errorf("%w: task %q", ErrInvalidSchema, key)
+		}
+
+		if _, ok = task["priority"]; !ok {
+			task["priority"] = string(domain.PriorityMedium)
+		}
+	}
+
+	doc["version"] = version
+
+	return doc, nil
+}
@@ -0,0 +1,92 @@
+package storage_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/config"
+	"github.com/therenotomorrow/tasker/internal/domain"
+	"github.com/therenotomorrow/tasker/internal/storage"
+	"github.com/therenotomorrow/tasker/pkg/jsonfile"
+)
+
+func golden(version int) string {
+	return config.Path("test", "data", "schema", fmt.Sprintf("v%d.json", version))
+}
+
+func TestIntegrationMigrations(t *testing.T) {
+	t.Parallel()
+
+	want, _ := os.ReadFile(filepath.Clean(golden(storage.LatestVersion)))
+
+	// every historical version must be upgraded to the latest golden file
+	for version := range storage.LatestVersion + 1 {
+		t.Run(fmt.Sprintf("v%d", version), func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				ctx      = t.Context()
+				filename = copyFile(t, golden(version))
+				stor     = storage.MustNew(jsonfile.Config{File: filename})
+			)
+
+			schema, err := stor.SchemaVersion(ctx)
+			if err != nil || schema.Version != version || schema.Latest != storage.LatestVersion {
+				t.Fatalf("SchemaVersion() got = %v, error = %v, want = %v", schema, err, version)
+			}
+
+			schema, err = stor.Migrate(ctx)
+			if err != nil || schema.Version != version {
+				t.Fatalf("Migrate() got = %v, error = %v, want = %v", schema, err, version)
+			}
+
+			got, _ := os.ReadFile(filepath.Clean(filename))
+
+			if string(got) != string(want) {
+				t.Errorf("Migrate() got = %v, want = %v", string(got), string(want))
+			}
+
+			schema, _ = stor.SchemaVersion(ctx)
+			if schema.IsOutdated() {
+				t.Errorf("SchemaVersion() got = %v, want = %v", schema.Version, storage.LatestVersion)
+			}
+		})
+	}
+}
+
+func TestIntegrationMigrationsInvalid(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		data string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want error
+	}{
+		{name: "too new", args: args{data: `{"version":1000,"tasks":{}}`}, want: domain.ErrSchemaTooNew},
+		{name: "negative version", args: args{data: `{"version":-1}`}, want: storage.ErrInvalidSchema},
+		{name: "text version", args: args{data: `{"version":"2"}`}, want: storage.ErrInvalidSchema},
+		{name: "invalid task key", args: args{data: `{"one":{"id":1}}`}, want: storage.ErrInvalidSchema},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			filename := filepath.Join(t.TempDir(), "tasks.json")
+
+			_ = os.WriteFile(filename, []byte(test.args.data), 0o600)
+
+			_, err := storage.New(jsonfile.Config{File: filename})
+			if !errors.Is(err, test.want) {
+				t.Errorf("New() error = %v, want = %v", err, test.want)
+			}
+		})
+	}
+}
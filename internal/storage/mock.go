@@ -8,12 +8,34 @@ import (
 )
 
 type Mock struct {
-	SaveTaskFunc     func(ctx context.Context, task *domain.Task) (*domain.Task, error)
-	UpdateTaskFunc   func(ctx context.Context, task *domain.Task) error
-	DeleteTaskFunc   func(ctx context.Context, task *domain.Task) error
-	GetByIDFunc      func(ctx context.Context, tid uint64) (*domain.Task, error)
-	ListAllFunc      func(ctx context.Context) ([]*domain.Task, error)
-	ListByStatusFunc func(ctx context.Context, status domain.Status) ([]*domain.Task, error)
+	SaveTaskFunc      func(ctx context.Context, task *domain.Task) (*domain.Task, error)
+	UpdateTaskFunc    func(ctx context.Context, task *domain.Task) error
+	DeleteTaskFunc    func(ctx context.Context, task *domain.Task) error
+	DeleteTasksFunc   func(ctx context.Context, tasks []*domain.Task) error
+	RestoreTasksFunc  func(ctx context.Context, tasks []*domain.Task) error
+	PurgeTasksFunc    func(ctx context.Context, tasks []*domain.Task) error
+	ArchiveTasksFunc  func(ctx context.Context, tasks []*domain.Task) error
+	GetByIDFunc       func(ctx context.Context, tid uint64) (*domain.Task, error)
+	ListAllFunc       func(ctx context.Context) ([]*domain.Task, error)
+	ListFunc          func(ctx context.Context, opts domain.ListOptions) ([]*domain.Task, error)
+	ListByStatusFunc  func(ctx context.Context, status domain.Status) ([]*domain.Task, error)
+	ListByTagsFunc    func(ctx context.Context, query domain.TagQuery) ([]*domain.Task, error)
+	ListByProjectFunc func(ctx context.Context, project string) ([]*domain.Task, error)
+	ListChildrenFunc  func(ctx context.Context, parentID uint64) ([]*domain.Task, error)
+	GetHistoryFunc    func(ctx context.Context, tid uint64) ([]domain.Change, error)
+	GetTrashedFunc    func(ctx context.Context, tid uint64) (*domain.Task, error)
+	ListTrashFunc     func(ctx context.Context) ([]*domain.Task, error)
+	ListArchivedFunc  func(ctx context.Context) ([]*domain.Task, error)
+	SearchFunc        func(ctx context.Context, query domain.SearchQuery) ([]*domain.Task, error)
+
+	AppendOperationFunc func(ctx context.Context, op *domain.Operation) (*domain.Operation, error)
+	UpdateOperationFunc func(ctx context.Context, op *domain.Operation) error
+	ListOperationsFunc  func(ctx context.Context) ([]*domain.Operation, error)
+
+	SchemaVersionFunc func(ctx context.Context) (domain.Schema, error)
+	MigrateFunc       func(ctx context.Context) (domain.Schema, error)
+
+	LockFunc func(ctx context.Context) (context.Context, func(), error)
 }
 
 func (s *Mock) SaveTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
@@ -40,6 +62,38 @@ func (s *Mock) DeleteTask(ctx context.Context, task *domain.Task) error {
 	return s.DeleteTaskFunc(ctx, task)
 }
 
+func (s *Mock) DeleteTasks(ctx context.Context, tasks []*domain.Task) error {
+	if s.DeleteTasksFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.DeleteTasksFunc(ctx, tasks)
+}
+
+func (s *Mock) RestoreTasks(ctx context.Context, tasks []*domain.Task) error {
+	if s.RestoreTasksFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.RestoreTasksFunc(ctx, tasks)
+}
+
+func (s *Mock) PurgeTasks(ctx context.Context, tasks []*domain.Task) error {
+	if s.PurgeTasksFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.PurgeTasksFunc(ctx, tasks)
+}
+
+func (s *Mock) ArchiveTasks(ctx context.Context, tasks []*domain.Task) error {
+	if s.ArchiveTasksFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.ArchiveTasksFunc(ctx, tasks)
+}
+
 func (s *Mock) GetByID(ctx context.Context, tid uint64) (*domain.Task, error) {
 	if s.GetByIDFunc == nil {
 		panic(testkit.ErrUnimplemented)
@@ -56,6 +110,14 @@ func (s *Mock) ListAll(ctx context.Context) ([]*domain.Task, error) {
 	return s.ListAllFunc(ctx)
 }
 
+func (s *Mock) List(ctx context.Context, opts domain.ListOptions) ([]*domain.Task, error) {
+	if s.ListFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.ListFunc(ctx, opts)
+}
+
 func (s *Mock) ListByStatus(ctx context.Context, status domain.Status) ([]*domain.Task, error) {
 	if s.ListByStatusFunc == nil {
 		panic(testkit.ErrUnimplemented)
@@ -63,3 +125,115 @@ func (s *Mock) ListByStatus(ctx context.Context, status domain.Status) ([]*domai
 
 	return s.ListByStatusFunc(ctx, status)
 }
+
+func (s *Mock) ListByTags(ctx context.Context, query domain.TagQuery) ([]*domain.Task, error) {
+	if s.ListByTagsFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.ListByTagsFunc(ctx, query)
+}
+
+func (s *Mock) Search(ctx context.Context, query domain.SearchQuery) ([]*domain.Task, error) {
+	if s.SearchFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.SearchFunc(ctx, query)
+}
+
+func (s *Mock) ListByProject(ctx context.Context, project string) ([]*domain.Task, error) {
+	if s.ListByProjectFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.ListByProjectFunc(ctx, project)
+}
+
+func (s *Mock) ListChildren(ctx context.Context, parentID uint64) ([]*domain.Task, error) {
+	if s.ListChildrenFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.ListChildrenFunc(ctx, parentID)
+}
+
+func (s *Mock) GetHistory(ctx context.Context, tid uint64) ([]domain.Change, error) {
+	if s.GetHistoryFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.GetHistoryFunc(ctx, tid)
+}
+
+func (s *Mock) GetTrashed(ctx context.Context, tid uint64) (*domain.Task, error) {
+	if s.GetTrashedFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.GetTrashedFunc(ctx, tid)
+}
+
+func (s *Mock) ListTrash(ctx context.Context) ([]*domain.Task, error) {
+	if s.ListTrashFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.ListTrashFunc(ctx)
+}
+
+func (s *Mock) ListArchived(ctx context.Context) ([]*domain.Task, error) {
+	if s.ListArchivedFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.ListArchivedFunc(ctx)
+}
+
+func (s *Mock) AppendOperation(ctx context.Context, op *domain.Operation) (*domain.Operation, error) {
+	if s.AppendOperationFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.AppendOperationFunc(ctx, op)
+}
+
+func (s *Mock) UpdateOperation(ctx context.Context, op *domain.Operation) error {
+	if s.UpdateOperationFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.UpdateOperationFunc(ctx, op)
+}
+
+func (s *Mock) ListOperations(ctx context.Context) ([]*domain.Operation, error) {
+	if s.ListOperationsFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.ListOperationsFunc(ctx)
+}
+
+func (s *Mock) SchemaVersion(ctx context.Context) (domain.Schema, error) {
+	if s.SchemaVersionFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.SchemaVersionFunc(ctx)
+}
+
+func (s *Mock) Migrate(ctx context.Context) (domain.Schema, error) {
+	if s.MigrateFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.MigrateFunc(ctx)
+}
+
+func (s *Mock) Lock(ctx context.Context) (context.Context, func(), error) {
+	if s.LockFunc == nil {
+		panic(testkit.ErrUnimplemented)
+	}
+
+	return s.LockFunc(ctx)
+}
@@ -74,6 +74,94 @@ func TestUnitMockDeleteTask(t *testing.T) {
 	_ = stor.DeleteTask(ctx, new(domain.Task))
 }
 
+func TestUnitMockDeleteTasks(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.DeleteTasksFunc = func(ctx context.Context, tasks []*domain.Task) error {
+		return nil
+	}
+
+	_ = stor.DeleteTasks(ctx, nil)
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("DeleteTasks() should panic")
+		}
+	}()
+
+	stor.DeleteTasksFunc = nil
+	_ = stor.DeleteTasks(ctx, nil)
+}
+
+func TestUnitMockRestoreTasks(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.RestoreTasksFunc = func(ctx context.Context, tasks []*domain.Task) error {
+		return nil
+	}
+
+	_ = stor.RestoreTasks(ctx, nil)
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("RestoreTasks() should panic")
+		}
+	}()
+
+	stor.RestoreTasksFunc = nil
+	_ = stor.RestoreTasks(ctx, nil)
+}
+
+func TestUnitMockPurgeTasks(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.PurgeTasksFunc = func(ctx context.Context, tasks []*domain.Task) error {
+		return nil
+	}
+
+	_ = stor.PurgeTasks(ctx, nil)
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("PurgeTasks() should panic")
+		}
+	}()
+
+	stor.PurgeTasksFunc = nil
+	_ = stor.PurgeTasks(ctx, nil)
+}
+
+func TestUnitMockArchiveTasks(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.ArchiveTasksFunc = func(ctx context.Context, tasks []*domain.Task) error {
+		return nil
+	}
+
+	_ = stor.ArchiveTasks(ctx, nil)
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("ArchiveTasks() should panic")
+		}
+	}()
+
+	stor.ArchiveTasksFunc = nil
+	_ = stor.ArchiveTasks(ctx, nil)
+}
+
 func TestUnitMockGetByID(t *testing.T) {
 	t.Parallel()
 
@@ -139,3 +227,333 @@ func TestUnitMockListByStatus(t *testing.T) {
 	stor.ListByStatusFunc = nil
 	_, _ = stor.ListByStatus(ctx, domain.StatusTodo)
 }
+
+func TestUnitMockList(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.ListFunc = func(ctx context.Context, opts domain.ListOptions) ([]*domain.Task, error) {
+		return nil, nil
+	}
+
+	_, _ = stor.List(ctx, domain.ListOptions{})
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("List() should panic")
+		}
+	}()
+
+	stor.ListFunc = nil
+	_, _ = stor.List(ctx, domain.ListOptions{})
+}
+
+func TestUnitMockListByTags(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.ListByTagsFunc = func(ctx context.Context, query domain.TagQuery) ([]*domain.Task, error) {
+		return nil, nil
+	}
+
+	_, _ = stor.ListByTags(ctx, domain.TagQuery{})
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("ListByTags() should panic")
+		}
+	}()
+
+	stor.ListByTagsFunc = nil
+	_, _ = stor.ListByTags(ctx, domain.TagQuery{})
+}
+
+func TestUnitMockSearch(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.SearchFunc = func(ctx context.Context, query domain.SearchQuery) ([]*domain.Task, error) {
+		return nil, nil
+	}
+
+	_, _ = stor.Search(ctx, domain.SearchQuery{})
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("Search() should panic")
+		}
+	}()
+
+	stor.SearchFunc = nil
+	_, _ = stor.Search(ctx, domain.SearchQuery{})
+}
+
+func TestUnitMockListByProject(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.ListByProjectFunc = func(ctx context.Context, project string) ([]*domain.Task, error) {
+		return nil, nil
+	}
+
+	_, _ = stor.ListByProject(ctx, "platform")
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("ListByProject() should panic")
+		}
+	}()
+
+	stor.ListByProjectFunc = nil
+	_, _ = stor.ListByProject(ctx, "platform")
+}
+
+func TestUnitMockListChildren(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.ListChildrenFunc = func(ctx context.Context, parentID uint64) ([]*domain.Task, error) {
+		return nil, nil
+	}
+
+	_, _ = stor.ListChildren(ctx, 1)
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("ListChildren() should panic")
+		}
+	}()
+
+	stor.ListChildrenFunc = nil
+	_, _ = stor.ListChildren(ctx, 1)
+}
+
+func TestUnitMockGetHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.GetHistoryFunc = func(ctx context.Context, tid uint64) ([]domain.Change, error) {
+		return nil, nil
+	}
+
+	_, _ = stor.GetHistory(ctx, 1)
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("GetHistory() should panic")
+		}
+	}()
+
+	stor.GetHistoryFunc = nil
+	_, _ = stor.GetHistory(ctx, 1)
+}
+
+func TestUnitMockGetTrashed(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.GetTrashedFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+		return nil, nil
+	}
+
+	_, _ = stor.GetTrashed(ctx, 1)
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("GetTrashed() should panic")
+		}
+	}()
+
+	stor.GetTrashedFunc = nil
+	_, _ = stor.GetTrashed(ctx, 1)
+}
+
+func TestUnitMockListTrash(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.ListTrashFunc = func(ctx context.Context) ([]*domain.Task, error) {
+		return nil, nil
+	}
+
+	_, _ = stor.ListTrash(ctx)
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("ListTrash() should panic")
+		}
+	}()
+
+	stor.ListTrashFunc = nil
+	_, _ = stor.ListTrash(ctx)
+}
+
+func TestUnitMockListArchived(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.ListArchivedFunc = func(ctx context.Context) ([]*domain.Task, error) {
+		return nil, nil
+	}
+
+	_, _ = stor.ListArchived(ctx)
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("ListArchived() should panic")
+		}
+	}()
+
+	stor.ListArchivedFunc = nil
+	_, _ = stor.ListArchived(ctx)
+}
+
+func TestUnitMockAppendOperation(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.AppendOperationFunc = func(ctx context.Context, op *domain.Operation) (*domain.Operation, error) {
+		return op, nil
+	}
+
+	_, _ = stor.AppendOperation(ctx, new(domain.Operation))
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("AppendOperation() should panic")
+		}
+	}()
+
+	stor.AppendOperationFunc = nil
+	_, _ = stor.AppendOperation(ctx, new(domain.Operation))
+}
+
+func TestUnitMockUpdateOperation(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.UpdateOperationFunc = func(ctx context.Context, op *domain.Operation) error {
+		return nil
+	}
+
+	_ = stor.UpdateOperation(ctx, new(domain.Operation))
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("UpdateOperation() should panic")
+		}
+	}()
+
+	stor.UpdateOperationFunc = nil
+	_ = stor.UpdateOperation(ctx, new(domain.Operation))
+}
+
+func TestUnitMockListOperations(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.ListOperationsFunc = func(ctx context.Context) ([]*domain.Operation, error) {
+		return nil, nil
+	}
+
+	_, _ = stor.ListOperations(ctx)
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("ListOperations() should panic")
+		}
+	}()
+
+	stor.ListOperationsFunc = nil
+	_, _ = stor.ListOperations(ctx)
+}
+
+func TestUnitMockSchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.SchemaVersionFunc = func(ctx context.Context) (domain.Schema, error) {
+		return domain.Schema{}, nil
+	}
+
+	_, _ = stor.SchemaVersion(ctx)
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("SchemaVersion() should panic")
+		}
+	}()
+
+	stor.SchemaVersionFunc = nil
+	_, _ = stor.SchemaVersion(ctx)
+}
+
+func TestUnitMockMigrate(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.MigrateFunc = func(ctx context.Context) (domain.Schema, error) {
+		return domain.Schema{}, nil
+	}
+
+	_, _ = stor.Migrate(ctx)
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("Migrate() should panic")
+		}
+	}()
+
+	stor.MigrateFunc = nil
+	_, _ = stor.Migrate(ctx)
+}
+
+func TestUnitMockLock(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	stor := new(storage.Mock)
+	stor.LockFunc = func(ctx context.Context) (context.Context, func(), error) {
+		return ctx, func() {}, nil
+	}
+
+	_, _, _ = stor.Lock(ctx)
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("Lock() should panic")
+		}
+	}()
+
+	stor.LockFunc = nil
+	_, _, _ = stor.Lock(ctx)
+}
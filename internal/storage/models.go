@@ -1,37 +1,305 @@
 package storage
 
 import (
+	"encoding/json"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/therenotomorrow/tasker/internal/domain"
 )
 
 type Task struct {
-	ID          uint64    `json:"id"` // pk
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          uint64      `json:"id"` // pk
+	Description string      `json:"description"`
+	Status      string      `json:"status"`
+	Priority    string      `json:"priority"`
+	CreatedAt   time.Time   `json:"createdAt"`
+	UpdatedAt   time.Time   `json:"updatedAt"`
+	DueAt       *time.Time  `json:"dueAt,omitempty"`
+	DeletedAt   *time.Time  `json:"deletedAt,omitempty"`
+	ArchivedAt  *time.Time  `json:"archivedAt,omitempty"`
+	Tags        []string    `json:"tags,omitempty"`
+	Project     string      `json:"project,omitempty"`
+	ParentID    uint64      `json:"parentID,omitempty"`
+	BlockedBy   []uint64    `json:"blockedBy,omitempty"`
+	Recurrence  string      `json:"recurrence,omitempty"`
+	RecurOf     uint64      `json:"recurOf,omitempty"`
+	Transition  *Transition `json:"transition,omitempty"`
+	History     []*Change   `json:"history,omitempty"`
+}
+
+type Transition struct {
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+type Change struct {
+	At    time.Time `json:"at"`
+	Actor string    `json:"actor,omitempty"`
+	Field string    `json:"field"`
+	Old   string    `json:"old,omitempty"`
+	New   string    `json:"new,omitempty"`
+}
+
+type Revision struct {
+	Before *Task `json:"before,omitempty"`
+	After  *Task `json:"after,omitempty"`
+}
+
+type Operation struct {
+	ID        uint64      `json:"id"` // pk
+	Command   string      `json:"command"`
+	At        time.Time   `json:"at"`
+	Actor     string      `json:"actor,omitempty"`
+	Revisions []*Revision `json:"revisions"`
+	Undone    bool        `json:"undone,omitempty"`
 }
 
 type Tasks map[uint64]*Task
 
+// Envelope is the file content: tasks together with the persisted ID sequence,
+// so the deleted IDs are never handed out again, the deleted tasks in the trash,
+// the history of purged tasks and the journal of the latest operations.
+type Envelope struct {
+	Version int                  `json:"version"`
+	NextID  uint64               `json:"nextID"`
+	Tasks   Tasks                `json:"tasks"`
+	Trash   Tasks                `json:"trash,omitempty"`
+	History map[uint64][]*Change `json:"history,omitempty"`
+	Journal []*Operation         `json:"journal,omitempty"`
+
+	origin int
+}
+
+// Origin is the schema version of the file before the migrations on load.
+func (e *Envelope) Origin() int {
+	return e.origin
+}
+
+func (e *Envelope) UnmarshalJSON(data []byte) error {
+	doc, origin, err := decodeDocument(data)
+	if err != nil {
+		return err
+	}
+
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("%s envelope error: %w", name, err)
+	}
+
+	type plain Envelope
+
+	err = json.Unmarshal(data, (*plain)(e))
+	if err != nil {
+		return fmt.Errorf("%s envelope error: %w", name, err)
+	}
+
+	e.origin = origin
+	e.repair()
+
+	return nil
+}
+
+func (e *Envelope) repair() {
+	if e.Tasks == nil {
+		e.Tasks = make(Tasks)
+	}
+
+	for _, tasks := range []Tasks{e.Tasks, e.Trash} {
+		for _, task := range tasks {
+			e.NextID = max(e.NextID, task.ID+1)
+		}
+	}
+
+	e.NextID = max(e.NextID, 1)
+}
+
 func toTask(model *Task) *domain.Task {
+	var dueAt, deletedAt, archivedAt time.Time
+	if model.DueAt != nil {
+		dueAt = *model.DueAt
+	}
+
+	if model.DeletedAt != nil {
+		deletedAt = *model.DeletedAt
+	}
+
+	if model.ArchivedAt != nil {
+		archivedAt = *model.ArchivedAt
+	}
+
 	return &domain.Task{
 		ID:          model.ID,
 		Description: model.Description,
 		Status:      domain.Status(model.Status),
+		Priority:    domain.Priority(model.Priority),
 		CreatedAt:   model.CreatedAt,
 		UpdatedAt:   model.UpdatedAt,
+		DueAt:       dueAt,
+		DeletedAt:   deletedAt,
+		ArchivedAt:  archivedAt,
+		Tags:        slices.Clone(model.Tags),
+		Project:     model.Project,
+		ParentID:    model.ParentID,
+		BlockedBy:   slices.Clone(model.BlockedBy),
+		Recurrence:  domain.Recurrence(model.Recurrence),
+		RecurOf:     model.RecurOf,
+		Transition:  toTransition(model.Transition),
+		History:     toHistory(model.History),
+	}
+}
+
+func toTransition(model *Transition) *domain.Transition {
+	if model == nil {
+		return nil
+	}
+
+	return &domain.Transition{
+		From:   domain.Status(model.From),
+		To:     domain.Status(model.To),
+		Reason: model.Reason,
+		At:     model.At,
 	}
 }
 
 func fromTask(entity *domain.Task) *Task {
+	var dueAt *time.Time
+	if entity.HasDue() {
+		due := entity.DueAt
+		dueAt = &due
+	}
+
+	var deletedAt *time.Time
+	if entity.IsDeleted() {
+		deleted := entity.DeletedAt
+		deletedAt = &deleted
+	}
+
+	var archivedAt *time.Time
+	if entity.IsArchived() {
+		archived := entity.ArchivedAt
+		archivedAt = &archived
+	}
+
 	return &Task{
 		ID:          entity.ID,
 		Description: entity.Description,
 		Status:      string(entity.Status),
+		Priority:    string(entity.Priority),
 		CreatedAt:   entity.CreatedAt,
 		UpdatedAt:   entity.UpdatedAt,
+		DueAt:       dueAt,
+		DeletedAt:   deletedAt,
+		ArchivedAt:  archivedAt,
+		Tags:        slices.Clone(entity.Tags),
+		Project:     entity.Project,
+		ParentID:    entity.ParentID,
+		BlockedBy:   slices.Clone(entity.BlockedBy),
+		Recurrence:  string(entity.Recurrence),
+		RecurOf:     entity.RecurOf,
+		Transition:  fromTransition(entity.Transition),
+		History:     fromHistory(entity.History),
+	}
+}
+
+func fromTransition(entity *domain.Transition) *Transition {
+	if entity == nil {
+		return nil
+	}
+
+	return &Transition{
+		From:   string(entity.From),
+		To:     string(entity.To),
+		Reason: entity.Reason,
+		At:     entity.At,
+	}
+}
+
+func toHistory(models []*Change) []domain.Change {
+	if models == nil {
+		return nil
+	}
+
+	history := make([]domain.Change, len(models))
+	for idx, model := range models {
+		history[idx] = domain.Change{
+			At:    model.At,
+			Actor: model.Actor,
+			Field: model.Field,
+			Old:   model.Old,
+			New:   model.New,
+		}
+	}
+
+	return history
+}
+
+func fromHistory(entities []domain.Change) []*Change {
+	if entities == nil {
+		return nil
+	}
+
+	history := make([]*Change, len(entities))
+	for idx, entity := range entities {
+		history[idx] = &Change{
+			At:    entity.At,
+			Actor: entity.Actor,
+			Field: entity.Field,
+			Old:   entity.Old,
+			New:   entity.New,
+		}
+	}
+
+	return history
+}
+
+func toOperation(model *Operation) *domain.Operation {
+	revisions := make([]domain.Revision, len(model.Revisions))
+	for idx, revision := range model.Revisions {
+		revisions[idx] = domain.Revision{Before: toOptionalTask(revision.Before), After: toOptionalTask(revision.After)}
+	}
+
+	return &domain.Operation{
+		ID:        model.ID,
+		Command:   model.Command,
+		At:        model.At,
+		Actor:     model.Actor,
+		Revisions: revisions,
+		Undone:    model.Undone,
+	}
+}
+
+func toOptionalTask(model *Task) *domain.Task {
+	if model == nil {
+		return nil
 	}
+
+	return toTask(model)
+}
+
+func fromOperation(entity *domain.Operation) *Operation {
+	revisions := make([]*Revision, len(entity.Revisions))
+	for idx, revision := range entity.Revisions {
+		revisions[idx] = &Revision{Before: fromOptionalTask(revision.Before), After: fromOptionalTask(revision.After)}
+	}
+
+	return &Operation{
+		ID:        entity.ID,
+		Command:   entity.Command,
+		At:        entity.At,
+		Actor:     entity.Actor,
+		Revisions: revisions,
+		Undone:    entity.Undone,
+	}
+}
+
+func fromOptionalTask(entity *domain.Task) *Task {
+	if entity == nil {
+		return nil
+	}
+
+	return fromTask(entity)
 }
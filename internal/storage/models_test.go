@@ -1 +1,143 @@
 package storage_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/storage"
+)
+
+func TestUnitEnvelopeUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		data string
+	}
+
+	type want struct {
+		envelope storage.Envelope
+		origin   int
+		err      bool
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "empty legacy",
+			args: args{data: `{}`},
+			want: want{envelope: storage.Envelope{Version: storage.LatestVersion, NextID: 1, Tasks: storage.Tasks{}}},
+		},
+		{
+			name: "null",
+			args: args{data: `null`},
+			want: want{envelope: storage.Envelope{Version: storage.LatestVersion, NextID: 1, Tasks: storage.Tasks{}}},
+		},
+		{
+			name: "legacy",
+			args: args{data: `{"2":{"id":2},"7":{"id":7}}`},
+			want: want{
+				envelope: storage.Envelope{
+					Version: storage.LatestVersion,
+					NextID:  8,
+					Tasks:   storage.Tasks{2: {ID: 2, Priority: "medium"}, 7: {ID: 7, Priority: "medium"}},
+				},
+			},
+		},
+		{
+			name: "envelope",
+			args: args{data: `{"nextID":10,"tasks":{"2":{"id":2}}}`},
+			want: want{
+				envelope: storage.Envelope{
+					Version: storage.LatestVersion,
+					NextID:  10,
+					Tasks:   storage.Tasks{2: {ID: 2, Priority: "medium"}},
+				},
+				origin: 1,
+			},
+		},
+		{
+			name: "envelope without tasks",
+			args: args{data: `{"nextID":10,"tasks":null}`},
+			want: want{
+				envelope: storage.Envelope{
+					Version: storage.LatestVersion,
+					NextID:  10,
+					Tasks:   storage.Tasks{},
+				},
+				origin: 1,
+			},
+		},
+		{
+			name: "outdated sequence",
+			args: args{data: `{"nextID":3,"tasks":{"5":{"id":5}}}`},
+			want: want{
+				envelope: storage.Envelope{
+					Version: storage.LatestVersion,
+					NextID:  6,
+					Tasks:   storage.Tasks{5: {ID: 5, Priority: "medium"}},
+				},
+				origin: 1,
+			},
+		},
+		{
+			name: "keep priority",
+			args: args{data: `{"version":2,"nextID":3,"tasks":{"2":{"id":2,"priority":"high"}}}`},
+			want: want{
+				envelope: storage.Envelope{
+					Version: storage.LatestVersion,
+					NextID:  3,
+					Tasks:   storage.Tasks{2: {ID: 2, Priority: "high"}},
+				},
+				origin: 2,
+			},
+		},
+		{
+			name: "invalid json",
+			args: args{data: `[]`},
+			want: want{err: true},
+		},
+		{
+			name: "invalid tasks",
+			args: args{data: `{"nextID":1,"tasks":[]}`},
+			want: want{err: true},
+		},
+		{
+			name: "invalid task",
+			args: args{data: `{"version":2,"nextID":1,"tasks":{"1":[]}}`},
+			want: want{err: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got storage.Envelope
+
+			err := json.Unmarshal([]byte(test.args.data), &got)
+			if (err != nil) != test.want.err {
+				t.Fatalf("UnmarshalJSON() error = %v, want = %v", err, test.want.err)
+			}
+
+			if test.want.err {
+				return
+			}
+
+			if got.Version != test.want.envelope.Version || got.NextID != test.want.envelope.NextID {
+				t.Errorf("UnmarshalJSON() got = %v, want = %v", got, test.want.envelope)
+			}
+
+			if !reflect.DeepEqual(got.Tasks, test.want.envelope.Tasks) {
+				t.Errorf("UnmarshalJSON() got = %v, want = %v", got.Tasks, test.want.envelope.Tasks)
+			}
+
+			if got.Origin() != test.want.origin {
+				t.Errorf("Origin() got = %v, want = %v", got.Origin(), test.want.origin)
+			}
+		})
+	}
+}
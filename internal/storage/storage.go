@@ -2,37 +2,47 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/therenotomorrow/tasker/internal/domain"
 	"github.com/therenotomorrow/tasker/pkg/jsonfile"
 )
 
-const name = "Storage"
+const (
+	name = "Storage"
 
+	archiveSuffix = ".archive.json"
+)
+
+// Storage keeps the tasks in the file and the archived ones in the sibling
+// cold file, like "tasker.archive.json", guarded by the lock of the first one.
 type Storage struct {
-	engine *jsonfile.JSONFile[Tasks]
-	lastID uint64
+	engine  *jsonfile.JSONFile[Envelope]
+	archive jsonfile.Config
+	lastID  uint64
 }
 
 func New(config jsonfile.Config) (*Storage, error) {
-	jsonfs, err := jsonfile.New[Tasks](config)
+	jsonfs, err := jsonfile.New[Envelope](config)
 	if err != nil {
 		return nil, fmt.Errorf("%s error: %w", name, err)
 	}
 
-	tasks, err := jsonfs.Load()
+	envelope, err := jsonfs.Load()
 	if err != nil {
 		return nil, fmt.Errorf("%s error: %w", name, err)
 	}
 
-	var lastID uint64
-
-	for _, task := range tasks {
-		lastID = max(lastID, task.ID)
-	}
+	archive := config
+	archive.File = strings.TrimSuffix(filepath.Clean(config.File), filepath.Ext(config.File)) + archiveSuffix
 
-	return &Storage{engine: jsonfs, lastID: lastID}, nil
+	return &Storage{engine: jsonfs, archive: archive, lastID: envelope.NextID - 1}, nil
 }
 
 func MustNew(config jsonfile.Config) *Storage {
@@ -48,17 +58,27 @@ func (s *Storage) LastID() uint64 {
 	return s.lastID
 }
 
-func (s *Storage) SaveTask(_ context.Context, task *domain.Task) (*domain.Task, error) {
-	tasks, err := s.engine.Load()
+func (s *Storage) SaveTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer unlock()
+
+	envelope, err := s.engine.Load()
 	if err != nil {
 		return nil, fmt.Errorf("%s error: %w", name, err)
 	}
 
-	s.lastID++
-	task.ID = s.lastID
-	tasks[task.ID] = fromTask(task)
+	// another process could add tasks since we have started,
+	// so take the ID from the sequence stored in the file
+	task.ID = envelope.NextID
+	envelope.NextID++
+	envelope.Tasks[task.ID] = fromTask(task)
+	s.lastID = task.ID
 
-	err = s.engine.Save(tasks)
+	err = s.engine.Save(envelope)
 	if err != nil {
 		return nil, fmt.Errorf("%s error: %w", name, err)
 	}
@@ -66,31 +86,123 @@ func (s *Storage) SaveTask(_ context.Context, task *domain.Task) (*domain.Task,
 	return task, nil
 }
 
-func (s *Storage) UpdateTask(_ context.Context, task *domain.Task) error {
-	tasks, err := s.engine.Load()
+func (s *Storage) UpdateTask(ctx context.Context, task *domain.Task) error {
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	envelope, err := s.engine.Load()
 	if err != nil {
 		return fmt.Errorf("%s error: %w", name, err)
 	}
 
-	tasks[task.ID] = fromTask(task)
+	// the trashed task comes back by RestoreTasks only
+	if _, ok := envelope.Trash[task.ID]; ok {
+		return fmt.Errorf("%s error: %w", name, domain.ErrTaskNotFound)
+	}
+
+	_, hot := envelope.Tasks[task.ID]
+
+	// the changed archived task goes back to the hot file
+	task.ArchivedAt = time.Time{}
+	envelope.Tasks[task.ID] = fromTask(task)
 
-	err = s.engine.Save(tasks)
+	err = s.engine.Save(envelope)
 	if err != nil {
 		return fmt.Errorf("%s error: %w", name, err)
 	}
 
-	return nil
+	if hot {
+		return nil
+	}
+
+	return s.unarchive([]uint64{task.ID})
+}
+
+func (s *Storage) DeleteTask(ctx context.Context, task *domain.Task) error {
+	return s.DeleteTasks(ctx, []*domain.Task{task})
+}
+
+// DeleteTasks moves all the tasks to the trash at once, so nothing is left half-deleted on failure.
+func (s *Storage) DeleteTasks(ctx context.Context, tasks []*domain.Task) error {
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return fmt.Errorf("%s error: %w", name, err)
+	}
+
+	if envelope.Trash == nil {
+		envelope.Trash = make(Tasks)
+	}
+
+	cold := make([]uint64, 0)
+
+	for _, task := range tasks {
+		if _, hot := envelope.Tasks[task.ID]; !hot {
+			cold = append(cold, task.ID)
+		}
+
+		delete(envelope.Tasks, task.ID)
+		envelope.Trash[task.ID] = fromTask(task)
+	}
+
+	err = s.engine.Save(envelope)
+	if err != nil {
+		return fmt.Errorf("%s error: %w", name, err)
+	}
+
+	return s.unarchive(cold)
 }
 
-func (s *Storage) DeleteTask(_ context.Context, task *domain.Task) error {
-	tasks, err := s.engine.Load()
+// ArchiveTasks moves the tasks to the archive file at once. The archive is saved
+// first, so the failure leaves the task in both files, where the hot one wins.
+func (s *Storage) ArchiveTasks(ctx context.Context, tasks []*domain.Task) error {
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	envelope, err := s.engine.Load()
 	if err != nil {
 		return fmt.Errorf("%s error: %w", name, err)
 	}
 
-	delete(tasks, task.ID)
+	cold, err := jsonfile.New[Envelope](s.archive)
+	if err != nil {
+		return fmt.Errorf("%s archive error: %w", name, err)
+	}
 
-	err = s.engine.Save(tasks)
+	archive, err := cold.Load()
+	if err != nil {
+		return fmt.Errorf("%s archive error: %w", name, err)
+	}
+
+	for _, task := range tasks {
+		if _, ok := envelope.Tasks[task.ID]; !ok {
+			return fmt.Errorf("%s error: %w", name, domain.ErrTaskNotFound)
+		}
+
+		archive.Tasks[task.ID] = fromTask(task)
+		delete(envelope.Tasks, task.ID)
+	}
+
+	err = cold.Save(archive)
+	if err != nil {
+		return fmt.Errorf("%s archive error: %w", name, err)
+	}
+
+	err = s.engine.Save(envelope)
 	if err != nil {
 		return fmt.Errorf("%s error: %w", name, err)
 	}
@@ -98,27 +210,111 @@ func (s *Storage) DeleteTask(_ context.Context, task *domain.Task) error {
 	return nil
 }
 
+// unarchive drops the tasks from the archive, the caller holds the lock.
+func (s *Storage) unarchive(ids []uint64) error {
+	archive, err := s.loadArchive()
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+
+	found := false
+
+	for _, tid := range ids {
+		if _, ok := archive.Tasks[tid]; ok {
+			found = true
+
+			delete(archive.Tasks, tid)
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	cold, err := jsonfile.New[Envelope](s.archive)
+	if err != nil {
+		return fmt.Errorf("%s archive error: %w", name, err)
+	}
+
+	err = cold.Save(archive)
+	if err != nil {
+		return fmt.Errorf("%s archive error: %w", name, err)
+	}
+
+	return nil
+}
+
+// loadArchive reads the archive file, nothing is archived until the file is created.
+func (s *Storage) loadArchive() (Envelope, error) {
+	var archive Envelope
+
+	_, err := os.Stat(s.archive.File)
+	if errors.Is(err, os.ErrNotExist) {
+		archive.repair()
+
+		return archive, nil
+	}
+
+	cold, err := jsonfile.New[Envelope](s.archive)
+	if err != nil {
+		return archive, fmt.Errorf("%s archive error: %w", name, err)
+	}
+
+	archive, err = cold.Load()
+	if err != nil {
+		return archive, fmt.Errorf("%s archive error: %w", name, err)
+	}
+
+	return archive, nil
+}
+
 func (s *Storage) GetByID(_ context.Context, tid uint64) (*domain.Task, error) {
-	tasks, err := s.engine.Load()
+	envelope, err := s.engine.Load()
 	if err != nil {
 		return nil, fmt.Errorf("%s error: %w", name, err)
 	}
 
-	if _, ok := tasks[tid]; !ok {
+	model, ok := envelope.Tasks[tid]
+	if ok {
+		return toTask(model), nil
+	}
+
+	// old IDs still resolve from the archive
+	archive, err := s.loadArchive()
+	if err != nil {
+		return nil, err
+	}
+
+	model, ok = archive.Tasks[tid]
+	if !ok {
 		return nil, fmt.Errorf("%s error: %w", name, domain.ErrTaskNotFound)
 	}
 
-	return toTask(tasks[tid]), nil
+	return toTask(model), nil
+}
+
+func (s *Storage) ListArchived(_ context.Context) ([]*domain.Task, error) {
+	archive, err := s.loadArchive()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*domain.Task, 0, len(archive.Tasks))
+	for _, task := range archive.Tasks {
+		list = append(list, toTask(task))
+	}
+
+	return list, nil
 }
 
 func (s *Storage) ListAll(_ context.Context) ([]*domain.Task, error) {
-	tasks, err := s.engine.Load()
+	envelope, err := s.engine.Load()
 	if err != nil {
 		return nil, fmt.Errorf("%s error: %w", name, err)
 	}
 
-	list := make([]*domain.Task, 0, len(tasks))
-	for _, task := range tasks {
+	list := make([]*domain.Task, 0, len(envelope.Tasks))
+	for _, task := range envelope.Tasks {
 		list = append(list, toTask(task))
 	}
 
@@ -126,14 +322,14 @@ func (s *Storage) ListAll(_ context.Context) ([]*domain.Task, error) {
 }
 
 func (s *Storage) ListByStatus(_ context.Context, status domain.Status) ([]*domain.Task, error) {
-	tasks, err := s.engine.Load()
+	envelope, err := s.engine.Load()
 	if err != nil {
 		return nil, fmt.Errorf("%s error: %w", name, err)
 	}
 
 	list := make([]*domain.Task, 0)
 
-	for _, task := range tasks {
+	for _, task := range envelope.Tasks {
 		domTask := toTask(task)
 
 		if domTask.Status == status {
@@ -143,3 +339,374 @@ func (s *Storage) ListByStatus(_ context.Context, status domain.Status) ([]*doma
 
 	return list, nil
 }
+
+// List gives the sorted page of the tasks.
+func (s *Storage) List(_ context.Context, opts domain.ListOptions) ([]*domain.Task, error) {
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	list := make([]*domain.Task, 0, len(envelope.Tasks))
+	for _, task := range envelope.Tasks {
+		list = append(list, toTask(task))
+	}
+
+	return opts.Apply(list), nil
+}
+
+func (s *Storage) ListByTags(_ context.Context, query domain.TagQuery) ([]*domain.Task, error) {
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	list := make([]*domain.Task, 0)
+
+	for _, task := range envelope.Tasks {
+		domTask := toTask(task)
+
+		if query.Match(*domTask) {
+			list = append(list, domTask)
+		}
+	}
+
+	return list, nil
+}
+
+// Search gives the tasks matching the query, the ranking is up to the caller.
+func (s *Storage) Search(_ context.Context, query domain.SearchQuery) ([]*domain.Task, error) {
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	list := make([]*domain.Task, 0)
+
+	for _, task := range envelope.Tasks {
+		domTask := toTask(task)
+
+		if query.Match(*domTask) {
+			list = append(list, domTask)
+		}
+	}
+
+	return list, nil
+}
+
+func (s *Storage) ListByProject(_ context.Context, project string) ([]*domain.Task, error) {
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	list := make([]*domain.Task, 0)
+
+	for _, task := range envelope.Tasks {
+		domTask := toTask(task)
+
+		if domTask.InProject(project) {
+			list = append(list, domTask)
+		}
+	}
+
+	return list, nil
+}
+
+func (s *Storage) ListChildren(_ context.Context, parentID uint64) ([]*domain.Task, error) {
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	list := make([]*domain.Task, 0)
+
+	for _, task := range envelope.Tasks {
+		if task.ParentID == parentID {
+			list = append(list, toTask(task))
+		}
+	}
+
+	return list, nil
+}
+
+// RestoreTasks moves all the tasks from the trash back at once.
+func (s *Storage) RestoreTasks(ctx context.Context, tasks []*domain.Task) error {
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return fmt.Errorf("%s error: %w", name, err)
+	}
+
+	for _, task := range tasks {
+		if _, ok := envelope.Trash[task.ID]; !ok {
+			return fmt.Errorf("%s error: %w", name, domain.ErrTaskNotFound)
+		}
+
+		delete(envelope.Trash, task.ID)
+		envelope.Tasks[task.ID] = fromTask(task)
+	}
+
+	err = s.engine.Save(envelope)
+	if err != nil {
+		return fmt.Errorf("%s error: %w", name, err)
+	}
+
+	return nil
+}
+
+// PurgeTasks removes all the tasks from the trash for good, only their history is kept.
+func (s *Storage) PurgeTasks(ctx context.Context, tasks []*domain.Task) error {
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return fmt.Errorf("%s error: %w", name, err)
+	}
+
+	for _, task := range tasks {
+		model, ok := envelope.Trash[task.ID]
+		if !ok {
+			return fmt.Errorf("%s error: %w", name, domain.ErrTaskNotFound)
+		}
+
+		delete(envelope.Trash, task.ID)
+
+		if model.History == nil {
+			continue
+		}
+
+		if envelope.History == nil {
+			envelope.History = make(map[uint64][]*Change)
+		}
+
+		envelope.History[task.ID] = model.History
+	}
+
+	err = s.engine.Save(envelope)
+	if err != nil {
+		return fmt.Errorf("%s error: %w", name, err)
+	}
+
+	return nil
+}
+
+// GetTrashed gives the task from the trash, the ones not deleted are not found here.
+func (s *Storage) GetTrashed(_ context.Context, tid uint64) (*domain.Task, error) {
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	model, ok := envelope.Trash[tid]
+	if !ok {
+		return nil, fmt.Errorf("%s error: %w", name, domain.ErrTaskNotFound)
+	}
+
+	return toTask(model), nil
+}
+
+func (s *Storage) ListTrash(_ context.Context) ([]*domain.Task, error) {
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	list := make([]*domain.Task, 0, len(envelope.Trash))
+	for _, task := range envelope.Trash {
+		list = append(list, toTask(task))
+	}
+
+	return list, nil
+}
+
+// GetHistory gives the history of the task, the deleted, purged and archived one too.
+func (s *Storage) GetHistory(_ context.Context, tid uint64) ([]domain.Change, error) {
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	if model, ok := envelope.Tasks[tid]; ok {
+		return toHistory(model.History), nil
+	}
+
+	if model, ok := envelope.Trash[tid]; ok {
+		return toHistory(model.History), nil
+	}
+
+	archive, err := s.loadArchive()
+	if err != nil {
+		return nil, err
+	}
+
+	if model, ok := archive.Tasks[tid]; ok {
+		return toHistory(model.History), nil
+	}
+
+	history, ok := envelope.History[tid]
+	if !ok {
+		return nil, fmt.Errorf("%s error: %w", name, domain.ErrTaskNotFound)
+	}
+
+	return toHistory(history), nil
+}
+
+// journalLimit is the number of the latest operations kept in the journal.
+const journalLimit = 50
+
+// AppendOperation puts the operation at the end of the journal, the undone operations
+// are dropped as they could not be redone after the new one.
+func (s *Storage) AppendOperation(ctx context.Context, op *domain.Operation) (*domain.Operation, error) {
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer unlock()
+
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	op.ID = 1
+	if len(envelope.Journal) > 0 {
+		op.ID = envelope.Journal[len(envelope.Journal)-1].ID + 1
+	}
+
+	journal := slices.DeleteFunc(envelope.Journal, func(model *Operation) bool { return model.Undone })
+
+	journal = append(journal, fromOperation(op))
+	envelope.Journal = journal[max(0, len(journal)-journalLimit):]
+
+	err = s.engine.Save(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	return op, nil
+}
+
+// UpdateOperation replaces the operation in the journal, the one dropped from
+// the journal meanwhile gives the conflict.
+func (s *Storage) UpdateOperation(ctx context.Context, op *domain.Operation) error {
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return fmt.Errorf("%s error: %w", name, err)
+	}
+
+	idx := slices.IndexFunc(envelope.Journal, func(model *Operation) bool { return model.ID == op.ID })
+	if idx < 0 {
+		return fmt.Errorf("%s error: %w", name, domain.ErrJournalConflict)
+	}
+
+	envelope.Journal[idx] = fromOperation(op)
+
+	err = s.engine.Save(envelope)
+	if err != nil {
+		return fmt.Errorf("%s error: %w", name, err)
+	}
+
+	return nil
+}
+
+// ListOperations gives the journal from the oldest operation.
+func (s *Storage) ListOperations(_ context.Context) ([]*domain.Operation, error) {
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	list := make([]*domain.Operation, len(envelope.Journal))
+	for idx, op := range envelope.Journal {
+		list[idx] = toOperation(op)
+	}
+
+	return list, nil
+}
+
+func (s *Storage) SchemaVersion(_ context.Context) (domain.Schema, error) {
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return domain.Schema{}, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	return domain.Schema{Version: envelope.Origin(), Latest: LatestVersion}, nil
+}
+
+func (s *Storage) Migrate(ctx context.Context) (domain.Schema, error) {
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return domain.Schema{}, err
+	}
+
+	defer unlock()
+
+	envelope, err := s.engine.Load()
+	if err != nil {
+		return domain.Schema{}, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	schema := domain.Schema{Version: envelope.Origin(), Latest: LatestVersion}
+	if !schema.IsOutdated() {
+		return schema, nil
+	}
+
+	// envelope is already upgraded on load, so saving writes the latest version
+	err = s.engine.Save(envelope)
+	if err != nil {
+		return domain.Schema{}, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	return schema, nil
+}
+
+// lockedKey marks the context of the caller holding the lock of the storage.
+type lockedKey struct{}
+
+// Lock holds the storage till the result is called, the calls with the returned
+// context do not wait for the lock again.
+func (s *Storage) Lock(ctx context.Context) (context.Context, func(), error) {
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return context.WithValue(ctx, lockedKey{}, s), unlock, nil
+}
+
+func (s *Storage) lock(ctx context.Context) (func(), error) {
+	if ctx.Value(lockedKey{}) == s {
+		return func() {}, nil
+	}
+
+	unlock, err := s.engine.Lock(ctx)
+
+	switch {
+	case errors.Is(err, jsonfile.ErrLockTimeout):
+		return nil, fmt.Errorf("%s error: %w: %w", name, domain.ErrStorageLocked, err)
+	case err != nil:
+		return nil, fmt.Errorf("%s error: %w", name, err)
+	}
+
+	return unlock, nil
+}
@@ -4,10 +4,13 @@ import (
 	"cmp"
 	"errors"
 	"os"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/therenotomorrow/tasker/internal/config"
 	"github.com/therenotomorrow/tasker/internal/domain"
@@ -17,12 +20,27 @@ import (
 	"github.com/therenotomorrow/tasker/pkg/testkit"
 )
 
-const ownerRO = 0o400
+const (
+	ownerRO = 0o400
+	ownerRW = 0o600
+)
+
+func copyFile(t *testing.T, filename string) string {
+	t.Helper()
+
+	data, _ := os.ReadFile(filepath.Clean(filename))
+	target := filepath.Join(t.TempDir(), filepath.Base(filename))
+
+	_ = os.WriteFile(target, data, 0o600)
+
+	return target
+}
 
 func TestUnitStorage(t *testing.T) {
 	t.Parallel()
 
 	var _ usecases.Storage = new(storage.Storage)
+	var _ usecases.Journal = new(storage.Storage)
 }
 
 func TestIntegrationNew(t *testing.T) {
@@ -95,12 +113,41 @@ func TestIntegrationStorageCRUDTask(t *testing.T) {
 
 	var (
 		ctx      = t.Context()
-		filename = config.Path("test", "data", "tasks-rw.json")
+		filename = copyFile(t, config.Path("test", "data", "tasks-rw.json"))
 		stor     = storage.MustNew(jsonfile.Config{File: filename})
 	)
 
-	got, _ := stor.SaveTask(ctx, &domain.Task{ID: 666, Description: "sleeper", Status: domain.StatusTodo})
-	want := &domain.Task{ID: 6, Description: "sleeper", Status: domain.StatusTodo}
+	dueAt := time.Date(2026, 5, 8, 23, 59, 59, 0, time.UTC)
+	tags := []string{"backend", "infra"}
+	task := &domain.Task{
+		ID:          666,
+		Description: "sleeper",
+		Status:      domain.StatusTodo,
+		DueAt:       dueAt,
+		Tags:        tags,
+		Project:     "platform.auth",
+		ParentID:    1,
+		BlockedBy:   []uint64{1, 2},
+		Recurrence:  "weekly:mon,thu",
+		RecurOf:     2,
+		Transition:  &domain.Transition{From: domain.StatusDone, To: domain.StatusTodo, Reason: "oops", At: dueAt},
+		History:     []domain.Change{{At: dueAt, Actor: "alice", Field: "status", Old: "done", New: "todo"}},
+	}
+	got, _ := stor.SaveTask(ctx, task)
+	want := &domain.Task{
+		ID:          6,
+		Description: "sleeper",
+		Status:      domain.StatusTodo,
+		DueAt:       dueAt,
+		Tags:        tags,
+		Project:     "platform.auth",
+		ParentID:    1,
+		BlockedBy:   []uint64{1, 2},
+		Recurrence:  "weekly:mon,thu",
+		RecurOf:     2,
+		Transition:  &domain.Transition{From: domain.StatusDone, To: domain.StatusTodo, Reason: "oops", At: dueAt},
+		History:     []domain.Change{{At: dueAt, Actor: "alice", Field: "status", Old: "done", New: "todo"}},
+	}
 
 	if !reflect.DeepEqual(got, want) {
 		t.Errorf("SaveTask() got = %v, want = %v", got, want)
@@ -125,6 +172,13 @@ func TestIntegrationStorageCRUDTask(t *testing.T) {
 		t.Errorf("UpdateTask() got = %v, want = %v", saved, got)
 	}
 
+	history, _ := stor.GetHistory(ctx, 6)
+	if !reflect.DeepEqual(history, got.History) {
+		t.Errorf("GetHistory() got = %v, want = %v", history, got.History)
+	}
+
+	got.History = append(got.History, domain.Change{At: dueAt, Field: domain.FieldTask, New: domain.TaskDeleted})
+
 	_ = stor.DeleteTask(ctx, got)
 
 	saved, _ = stor.GetByID(ctx, 6)
@@ -132,6 +186,17 @@ func TestIntegrationStorageCRUDTask(t *testing.T) {
 		t.Errorf("DeleteTask() got = %v, want = %v", saved, got)
 	}
 
+	// the history outlives the deleted task
+	history, _ = stor.GetHistory(ctx, 6)
+	if !reflect.DeepEqual(history, got.History) {
+		t.Errorf("GetHistory() got = %v, want = %v", history, got.History)
+	}
+
+	_, err := stor.GetHistory(ctx, 7)
+	if !errors.Is(err, domain.ErrTaskNotFound) {
+		t.Errorf("GetHistory() error = %v, want = %v", err, domain.ErrTaskNotFound)
+	}
+
 	list, _ = stor.ListAll(ctx)
 	if want := 4; len(list) != want {
 		t.Errorf("ListAll() got = %v, want = %v", len(list), want)
@@ -320,6 +385,26 @@ func TestIntegrationStorageGetByID(t *testing.T) {
 	}
 }
 
+func TestIntegrationStorageGetHistory(t *testing.T) {
+	t.Parallel()
+
+	const filename = "get-history.json"
+
+	var (
+		ctx  = t.Context()
+		stor = storage.MustNew(jsonfile.Config{File: filename})
+	)
+
+	defer func() { _ = os.Remove(filename) }()
+
+	_, _ = os.Create(filename)
+
+	got, err := stor.GetHistory(ctx, 1)
+	if err == nil || got != nil {
+		t.Fatalf("GetHistory() got = %v, error = %v, want = %v", got, err, nil)
+	}
+}
+
 func TestIntegrationStorageListAll(t *testing.T) {
 	t.Parallel()
 
@@ -359,3 +444,622 @@ func TestIntegrationStorageListByStatus(t *testing.T) {
 		t.Fatalf("ListByStatus() got = %v, error = %v, want = %v", got, err, nil)
 	}
 }
+
+func TestIntegrationStorageList(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = t.Context()
+		filename = copyFile(t, config.Path("test", "data", "tasks-rw.json"))
+		stor     = storage.MustNew(jsonfile.Config{File: filename})
+	)
+
+	all, _ := stor.ListAll(ctx)
+	ids := make([]uint64, len(all))
+
+	for idx, task := range all {
+		ids[idx] = task.ID
+	}
+
+	slices.Sort(ids)
+
+	list, err := stor.List(ctx, domain.ListOptions{Sort: domain.SortByID, Offset: 1, Limit: 2})
+	if err != nil || len(list) != 2 || list[0].ID != ids[1] || list[1].ID != ids[2] {
+		t.Fatalf("List() got = %v, error = %v, want = %v", list, err, ids[1:3])
+	}
+
+	_ = os.Truncate(filename, 0)
+
+	list, err = stor.List(ctx, domain.ListOptions{})
+	if err == nil || len(list) != 0 {
+		t.Fatalf("List() got = %v, error = %v, want = %v", list, err, nil)
+	}
+}
+
+func TestIntegrationStorageListByTags(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = t.Context()
+		filename = copyFile(t, config.Path("test", "data", "tasks-rw.json"))
+		stor     = storage.MustNew(jsonfile.Config{File: filename})
+	)
+
+	_, _ = stor.SaveTask(ctx, &domain.Task{Description: "api", Tags: []string{"backend"}})
+	_, _ = stor.SaveTask(ctx, &domain.Task{Description: "deploy", Tags: []string{"backend", "infra"}})
+
+	list, err := stor.ListByTags(ctx, domain.TagQuery{Include: []string{"backend"}, Exclude: []string{"infra"}})
+	if err != nil || len(list) != 1 || list[0].Description != "api" {
+		t.Fatalf("ListByTags() got = %v, error = %v, want = %v", list, err, nil)
+	}
+
+	list, _ = stor.ListByTags(ctx, domain.TagQuery{Include: []string{"infra"}})
+	if len(list) != 1 || list[0].Description != "deploy" {
+		t.Errorf("ListByTags() got = %v, want = %v", list, "deploy")
+	}
+
+	list, _ = stor.ListByTags(ctx, domain.TagQuery{Exclude: []string{"backend"}})
+	if want := 4; len(list) != want {
+		t.Errorf("ListByTags() got = %v, want = %v", len(list), want)
+	}
+
+	_ = os.Truncate(filename, 0)
+
+	list, err = stor.ListByTags(ctx, domain.TagQuery{})
+	if err == nil || len(list) != 0 {
+		t.Fatalf("ListByTags() got = %v, error = %v, want = %v", list, err, nil)
+	}
+}
+
+func TestIntegrationStorageSearch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = t.Context()
+		filename = copyFile(t, config.Path("test", "data", "tasks-rw.json"))
+		stor     = storage.MustNew(jsonfile.Config{File: filename})
+	)
+
+	_, _ = stor.SaveTask(ctx, &domain.Task{Description: "Deploy the API"})
+	_, _ = stor.SaveTask(ctx, &domain.Task{Description: "deploy docs"})
+
+	query, _ := domain.NewSearchQuery("deploy -docs")
+
+	list, err := stor.Search(ctx, query)
+	if err != nil || len(list) != 1 || list[0].Description != "Deploy the API" {
+		t.Fatalf("Search() got = %v, error = %v, want = %v", list, err, "Deploy the API")
+	}
+
+	_ = os.Truncate(filename, 0)
+
+	list, err = stor.Search(ctx, query)
+	if err == nil || len(list) != 0 {
+		t.Fatalf("Search() got = %v, error = %v, want = %v", list, err, nil)
+	}
+}
+
+func TestIntegrationStorageListByProject(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = t.Context()
+		filename = copyFile(t, config.Path("test", "data", "tasks-rw.json"))
+		stor     = storage.MustNew(jsonfile.Config{File: filename})
+	)
+
+	_, _ = stor.SaveTask(ctx, &domain.Task{Description: "login", Project: "platform.auth"})
+	_, _ = stor.SaveTask(ctx, &domain.Task{Description: "sso", Project: "platform.auth.sso"})
+	_, _ = stor.SaveTask(ctx, &domain.Task{Description: "invoices", Project: "platformer"})
+
+	list, err := stor.ListByProject(ctx, "platform")
+	if want := 2; err != nil || len(list) != want {
+		t.Fatalf("ListByProject() got = %v, error = %v, want = %v", len(list), err, want)
+	}
+
+	list, _ = stor.ListByProject(ctx, "platform.auth.sso")
+	if len(list) != 1 || list[0].Description != "sso" {
+		t.Errorf("ListByProject() got = %v, want = %v", list, "sso")
+	}
+
+	_ = os.Truncate(filename, 0)
+
+	list, err = stor.ListByProject(ctx, "platform")
+	if err == nil || len(list) != 0 {
+		t.Fatalf("ListByProject() got = %v, error = %v, want = %v", list, err, nil)
+	}
+}
+
+func TestIntegrationStorageSubtasks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = t.Context()
+		filename = copyFile(t, config.Path("test", "data", "tasks-rw.json"))
+		stor     = storage.MustNew(jsonfile.Config{File: filename})
+	)
+
+	parent, _ := stor.SaveTask(ctx, &domain.Task{Description: "release"})
+	first, _ := stor.SaveTask(ctx, &domain.Task{Description: "changelog", ParentID: parent.ID})
+	second, _ := stor.SaveTask(ctx, &domain.Task{Description: "tag", ParentID: parent.ID})
+
+	list, err := stor.ListChildren(ctx, parent.ID)
+	if want := 2; err != nil || len(list) != want {
+		t.Fatalf("ListChildren() got = %v, error = %v, want = %v", len(list), err, want)
+	}
+
+	err = stor.DeleteTasks(ctx, []*domain.Task{first, second, parent})
+	if err != nil {
+		t.Fatalf("DeleteTasks() error = %v, want = %v", err, nil)
+	}
+
+	list, _ = stor.ListAll(ctx)
+	if want := 4; len(list) != want {
+		t.Errorf("DeleteTasks() got = %v, want = %v", len(list), want)
+	}
+
+	_ = os.Truncate(filename, 0)
+
+	list, err = stor.ListChildren(ctx, parent.ID)
+	if err == nil || len(list) != 0 {
+		t.Fatalf("ListChildren() got = %v, error = %v, want = %v", list, err, nil)
+	}
+}
+
+func TestIntegrationStorageTrash(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = t.Context()
+		filename = copyFile(t, config.Path("test", "data", "tasks-rw.json"))
+		stor     = storage.MustNew(jsonfile.Config{File: filename})
+		at       = time.Date(2026, 5, 8, 10, 0, 0, 0, time.UTC)
+	)
+
+	task, _ := stor.SaveTask(ctx, &domain.Task{Description: "trashed", Status: domain.StatusTodo})
+	task.DeletedAt = at
+	task.History = []domain.Change{{At: at, Field: domain.FieldTask, New: domain.TaskDeleted}}
+
+	_ = stor.DeleteTask(ctx, task)
+
+	_, err := stor.GetByID(ctx, task.ID)
+	if !errors.Is(err, domain.ErrTaskNotFound) {
+		t.Errorf("GetByID() error = %v, want = %v", err, domain.ErrTaskNotFound)
+	}
+
+	got, err := stor.GetTrashed(ctx, task.ID)
+	if err != nil || !reflect.DeepEqual(got, task) {
+		t.Errorf("GetTrashed() got = %v, error = %v, want = %v", got, err, task)
+	}
+
+	trash, _ := stor.ListTrash(ctx)
+	if want := []*domain.Task{task}; !reflect.DeepEqual(trash, want) {
+		t.Errorf("ListTrash() got = %v, want = %v", trash, want)
+	}
+
+	// the update of another task leaves the trash alone, the trashed one is not updated at all
+	other, _ := stor.GetByID(ctx, 1)
+	_ = stor.UpdateTask(ctx, other)
+
+	err = stor.UpdateTask(ctx, task)
+	if !errors.Is(err, domain.ErrTaskNotFound) {
+		t.Errorf("UpdateTask() error = %v, want = %v", err, domain.ErrTaskNotFound)
+	}
+
+	trash, _ = stor.ListTrash(ctx)
+	if want := []*domain.Task{task}; !reflect.DeepEqual(trash, want) {
+		t.Errorf("UpdateTask() got = %v, want = %v", trash, want)
+	}
+
+	list, _ := stor.ListAll(ctx)
+	if want := 4; len(list) != want {
+		t.Errorf("ListAll() got = %v, want = %v", len(list), want)
+	}
+
+	task.DeletedAt = time.Time{}
+
+	err = stor.RestoreTasks(ctx, []*domain.Task{task})
+	if err != nil {
+		t.Fatalf("RestoreTasks() error = %v, want = %v", err, nil)
+	}
+
+	got, _ = stor.GetByID(ctx, task.ID)
+	if !reflect.DeepEqual(got, task) {
+		t.Errorf("RestoreTasks() got = %v, want = %v", got, task)
+	}
+
+	err = stor.RestoreTasks(ctx, []*domain.Task{task})
+	if !errors.Is(err, domain.ErrTaskNotFound) {
+		t.Errorf("RestoreTasks() error = %v, want = %v", err, domain.ErrTaskNotFound)
+	}
+
+	_ = stor.DeleteTask(ctx, task)
+
+	err = stor.PurgeTasks(ctx, []*domain.Task{task})
+	if err != nil {
+		t.Fatalf("PurgeTasks() error = %v, want = %v", err, nil)
+	}
+
+	_, err = stor.GetTrashed(ctx, task.ID)
+	if !errors.Is(err, domain.ErrTaskNotFound) {
+		t.Errorf("GetTrashed() error = %v, want = %v", err, domain.ErrTaskNotFound)
+	}
+
+	// the history outlives the purged task
+	history, _ := stor.GetHistory(ctx, task.ID)
+	if !reflect.DeepEqual(history, task.History) {
+		t.Errorf("GetHistory() got = %v, want = %v", history, task.History)
+	}
+
+	err = stor.PurgeTasks(ctx, []*domain.Task{task})
+	if !errors.Is(err, domain.ErrTaskNotFound) {
+		t.Errorf("PurgeTasks() error = %v, want = %v", err, domain.ErrTaskNotFound)
+	}
+
+	_ = os.Truncate(filename, 0)
+
+	_, err = stor.GetTrashed(ctx, task.ID)
+	if err == nil {
+		t.Errorf("GetTrashed() error = %v, want = %v", err, "not nil")
+	}
+
+	_, err = stor.ListTrash(ctx)
+	if err == nil {
+		t.Errorf("ListTrash() error = %v, want = %v", err, "not nil")
+	}
+
+	err = stor.RestoreTasks(ctx, []*domain.Task{task})
+	if err == nil {
+		t.Errorf("RestoreTasks() error = %v, want = %v", err, "not nil")
+	}
+
+	err = stor.PurgeTasks(ctx, []*domain.Task{task})
+	if err == nil {
+		t.Errorf("PurgeTasks() error = %v, want = %v", err, "not nil")
+	}
+}
+
+func TestIntegrationStorageArchive(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = t.Context()
+		filename = filepath.Join(t.TempDir(), "tasks.json")
+		archive  = strings.TrimSuffix(filename, ".json") + ".archive.json"
+		stor     = storage.MustNew(jsonfile.Config{File: filename})
+		at       = time.Date(2026, 5, 8, 10, 0, 0, 0, time.UTC)
+	)
+
+	done, _ := stor.SaveTask(ctx, &domain.Task{Description: "done", Status: domain.StatusDone})
+	_, _ = stor.SaveTask(ctx, &domain.Task{Description: "todo", Status: domain.StatusTodo})
+
+	// nothing is archived yet, so there is no file
+	if _, err := os.Stat(archive); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("New() error = %v, want = %v", err, os.ErrNotExist)
+	}
+
+	done.ArchivedAt = at
+	done.History = []domain.Change{{At: at, Field: domain.FieldTask, New: domain.TaskArchived}}
+
+	err := stor.ArchiveTasks(ctx, []*domain.Task{done})
+	if err != nil {
+		t.Fatalf("ArchiveTasks() error = %v, want = %v", err, nil)
+	}
+
+	got, err := stor.GetByID(ctx, done.ID)
+	if err != nil || !reflect.DeepEqual(got, done) {
+		t.Errorf("GetByID() got = %v, error = %v, want = %v", got, err, done)
+	}
+
+	list, _ := stor.ListAll(ctx)
+	if want := 1; len(list) != want {
+		t.Errorf("ListAll() got = %v, want = %v", len(list), want)
+	}
+
+	list, _ = stor.ListArchived(ctx)
+	if want := []*domain.Task{done}; !reflect.DeepEqual(list, want) {
+		t.Errorf("ListArchived() got = %v, want = %v", list, want)
+	}
+
+	history, _ := stor.GetHistory(ctx, done.ID)
+	if !reflect.DeepEqual(history, done.History) {
+		t.Errorf("GetHistory() got = %v, want = %v", history, done.History)
+	}
+
+	err = stor.ArchiveTasks(ctx, []*domain.Task{done})
+	if !errors.Is(err, domain.ErrTaskNotFound) {
+		t.Errorf("ArchiveTasks() error = %v, want = %v", err, domain.ErrTaskNotFound)
+	}
+
+	// the changed archived task goes back to the hot file
+	_ = stor.UpdateTask(ctx, got)
+
+	list, _ = stor.ListArchived(ctx)
+	if len(list) != 0 {
+		t.Errorf("UpdateTask() got = %v, want = %v", list, "empty archive")
+	}
+
+	got, _ = stor.GetByID(ctx, done.ID)
+	if got.IsArchived() {
+		t.Errorf("UpdateTask() got = %v, want = %v", got.ArchivedAt, time.Time{})
+	}
+
+	got.ArchivedAt = at
+	_ = stor.ArchiveTasks(ctx, []*domain.Task{got})
+	_ = stor.DeleteTask(ctx, got)
+
+	list, _ = stor.ListArchived(ctx)
+	if len(list) != 0 {
+		t.Errorf("DeleteTask() got = %v, want = %v", list, "empty archive")
+	}
+
+	_, err = stor.GetTrashed(ctx, done.ID)
+	if err != nil {
+		t.Errorf("DeleteTask() error = %v, want = %v", err, nil)
+	}
+
+	_ = os.WriteFile(archive, []byte("broken"), ownerRW)
+
+	_, err = stor.GetByID(ctx, 42)
+	if err == nil {
+		t.Errorf("GetByID() error = %v, want = %v", err, "not nil")
+	}
+
+	_, err = stor.ListArchived(ctx)
+	if err == nil {
+		t.Errorf("ListArchived() error = %v, want = %v", err, "not nil")
+	}
+
+	_, err = stor.GetHistory(ctx, 42)
+	if err == nil {
+		t.Errorf("GetHistory() error = %v, want = %v", err, "not nil")
+	}
+
+	err = stor.ArchiveTasks(ctx, nil)
+	if err == nil {
+		t.Errorf("ArchiveTasks() error = %v, want = %v", err, "not nil")
+	}
+}
+
+func TestIntegrationStorageConcurrentSaveTask(t *testing.T) {
+	t.Parallel()
+
+	const workers = 10
+
+	var (
+		ctx      = t.Context()
+		filename = filepath.Join(t.TempDir(), "tasks.json")
+		group    sync.WaitGroup
+	)
+
+	// every worker has its own storage as separate tasker process does
+	for range workers {
+		stor := storage.MustNew(jsonfile.Config{File: filename})
+
+		group.Add(1)
+
+		go func() {
+			defer group.Done()
+
+			_, err := stor.SaveTask(ctx, &domain.Task{Description: "parallel", Status: domain.StatusTodo})
+			if err != nil {
+				t.Errorf("SaveTask() error = %v, want = %v", err, nil)
+			}
+		}()
+	}
+
+	group.Wait()
+
+	list, _ := storage.MustNew(jsonfile.Config{File: filename}).ListAll(ctx)
+	if len(list) != workers {
+		t.Fatalf("ListAll() got = %v, want = %v", len(list), workers)
+	}
+
+	ids := make(map[uint64]bool)
+	for _, task := range list {
+		ids[task.ID] = true
+	}
+
+	if len(ids) != workers {
+		t.Errorf("SaveTask() got = %v, want = %v", len(ids), workers)
+	}
+}
+
+func TestIntegrationStorageLocked(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = t.Context()
+		filename = filepath.Join(t.TempDir(), "tasks.json")
+		stor     = storage.MustNew(jsonfile.Config{File: filename, LockTimeout: 20 * time.Millisecond})
+		task     = &domain.Task{ID: 1, Status: domain.StatusTodo}
+	)
+
+	engine, _ := jsonfile.New[storage.Envelope](jsonfile.Config{File: filename})
+
+	unlock, _ := engine.Lock(ctx)
+	defer unlock()
+
+	_, err := stor.SaveTask(ctx, task)
+	if !errors.Is(err, domain.ErrStorageLocked) {
+		t.Errorf("SaveTask() error = %v, want = %v", err, domain.ErrStorageLocked)
+	}
+
+	err = stor.UpdateTask(ctx, task)
+	if !errors.Is(err, domain.ErrStorageLocked) {
+		t.Errorf("UpdateTask() error = %v, want = %v", err, domain.ErrStorageLocked)
+	}
+
+	err = stor.DeleteTask(ctx, task)
+	if !errors.Is(err, domain.ErrStorageLocked) {
+		t.Errorf("DeleteTask() error = %v, want = %v", err, domain.ErrStorageLocked)
+	}
+
+	err = stor.RestoreTasks(ctx, []*domain.Task{task})
+	if !errors.Is(err, domain.ErrStorageLocked) {
+		t.Errorf("RestoreTasks() error = %v, want = %v", err, domain.ErrStorageLocked)
+	}
+
+	err = stor.PurgeTasks(ctx, []*domain.Task{task})
+	if !errors.Is(err, domain.ErrStorageLocked) {
+		t.Errorf("PurgeTasks() error = %v, want = %v", err, domain.ErrStorageLocked)
+	}
+
+	_, err = stor.AppendOperation(ctx, new(domain.Operation))
+	if !errors.Is(err, domain.ErrStorageLocked) {
+		t.Errorf("AppendOperation() error = %v, want = %v", err, domain.ErrStorageLocked)
+	}
+
+	err = stor.UpdateOperation(ctx, new(domain.Operation))
+	if !errors.Is(err, domain.ErrStorageLocked) {
+		t.Errorf("UpdateOperation() error = %v, want = %v", err, domain.ErrStorageLocked)
+	}
+}
+
+func TestIntegrationStorageLock(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = t.Context()
+		filename = filepath.Join(t.TempDir(), "tasks.json")
+		stor     = storage.MustNew(jsonfile.Config{File: filename, LockTimeout: 20 * time.Millisecond})
+		other    = storage.MustNew(jsonfile.Config{File: filename, LockTimeout: 20 * time.Millisecond})
+		task     = &domain.Task{ID: 1, Status: domain.StatusTodo}
+	)
+
+	locked, unlock, err := stor.Lock(ctx)
+	if err != nil {
+		t.Fatalf("Lock() error = %v, want = %v", err, nil)
+	}
+
+	// the holder goes on, the others wait for the lock
+	_, err = stor.SaveTask(locked, task)
+	if err != nil {
+		t.Errorf("SaveTask() error = %v, want = %v", err, nil)
+	}
+
+	_, err = other.SaveTask(ctx, task)
+	if !errors.Is(err, domain.ErrStorageLocked) {
+		t.Errorf("SaveTask() error = %v, want = %v", err, domain.ErrStorageLocked)
+	}
+
+	_, _, err = other.Lock(ctx)
+	if !errors.Is(err, domain.ErrStorageLocked) {
+		t.Errorf("Lock() error = %v, want = %v", err, domain.ErrStorageLocked)
+	}
+
+	unlock()
+
+	_, err = other.SaveTask(ctx, task)
+	if err != nil {
+		t.Errorf("SaveTask() error = %v, want = %v", err, nil)
+	}
+}
+
+func TestIntegrationStorageSequence(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = t.Context()
+		filename = copyFile(t, config.Path("test", "data", "tasks-ro.json"))
+		stor     = storage.MustNew(jsonfile.Config{File: filename})
+	)
+
+	task, _ := stor.SaveTask(ctx, &domain.Task{Description: "six", Status: domain.StatusTodo})
+	_ = stor.DeleteTask(ctx, task)
+
+	// restart must not reuse the deleted ID
+	stor = storage.MustNew(jsonfile.Config{File: filename})
+
+	if got, want := stor.LastID(), uint64(6); got != want {
+		t.Errorf("LastID() got = %v, want = %v", got, want)
+	}
+
+	// another process takes the next ID behind our back
+	other := storage.MustNew(jsonfile.Config{File: filename})
+	_, _ = other.SaveTask(ctx, &domain.Task{Description: "seven", Status: domain.StatusTodo})
+
+	task, _ = stor.SaveTask(ctx, &domain.Task{Description: "eight", Status: domain.StatusTodo})
+
+	if got, want := task.ID, uint64(8); got != want {
+		t.Errorf("SaveTask() got = %v, want = %v", got, want)
+	}
+
+	if got, want := stor.LastID(), uint64(8); got != want {
+		t.Errorf("LastID() got = %v, want = %v", got, want)
+	}
+
+	engine, _ := jsonfile.New[storage.Envelope](jsonfile.Config{File: filename})
+	envelope, _ := engine.Load()
+
+	if got, want := envelope.NextID, uint64(9); got != want {
+		t.Errorf("Load() got = %v, want = %v", got, want)
+	}
+}
+
+func TestIntegrationStorageJournal(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = t.Context()
+		filename = filepath.Join(t.TempDir(), "tasks.json")
+		stor     = storage.MustNew(jsonfile.Config{File: filename})
+		at       = time.Date(2026, 5, 8, 10, 0, 0, 0, time.UTC)
+	)
+
+	task := &domain.Task{ID: 1, Description: "first", Status: domain.StatusTodo, UpdatedAt: at}
+	first, _ := stor.AppendOperation(ctx, &domain.Operation{
+		Command:   "AddTask",
+		At:        at,
+		Actor:     "alice",
+		Revisions: []domain.Revision{{After: task}},
+	})
+	second, _ := stor.AppendOperation(ctx, &domain.Operation{
+		Command:   "DeleteTask",
+		At:        at,
+		Revisions: []domain.Revision{{Before: task}},
+	})
+
+	list, err := stor.ListOperations(ctx)
+	if want := []*domain.Operation{first, second}; err != nil || !reflect.DeepEqual(list, want) {
+		t.Fatalf("ListOperations() got = %v, error = %v, want = %v", list, err, want)
+	}
+
+	second.Undone = true
+
+	err = stor.UpdateOperation(ctx, second)
+	if err != nil {
+		t.Fatalf("UpdateOperation() error = %v, want = %v", err, nil)
+	}
+
+	// the new operation drops the undone ones, the IDs are not reused
+	third, _ := stor.AppendOperation(ctx, &domain.Operation{Command: "UpdateTask", At: at, Revisions: nil})
+	if want := uint64(3); third.ID != want {
+		t.Errorf("AppendOperation() got = %v, want = %v", third.ID, want)
+	}
+
+	list, _ = stor.ListOperations(ctx)
+	if len(list) != 2 || list[0].ID != first.ID || list[1].ID != third.ID {
+		t.Errorf("ListOperations() got = %v, want = %v", list, []*domain.Operation{first, third})
+	}
+
+	err = stor.UpdateOperation(ctx, second)
+	if !errors.Is(err, domain.ErrJournalConflict) {
+		t.Errorf("UpdateOperation() error = %v, want = %v", err, domain.ErrJournalConflict)
+	}
+
+	for range 60 {
+		_, _ = stor.AppendOperation(ctx, &domain.Operation{Command: "UpdateTask", At: at, Revisions: nil})
+	}
+
+	list, _ = stor.ListOperations(ctx)
+	if want := 50; len(list) != want {
+		t.Errorf("ListOperations() got = %v, want = %v", len(list), want)
+	}
+
+	_ = os.Truncate(filename, 0)
+
+	_, err = stor.ListOperations(ctx)
+	if err == nil {
+		t.Errorf("ListOperations() error = %v, want = %v", err, "not nil")
+	}
+}
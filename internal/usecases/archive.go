@@ -0,0 +1,111 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+// Scope tells which tasks are listed, the active ones by default.
+type Scope int
+
+const (
+	ScopeActive Scope = iota
+	ScopeArchived
+	ScopeAll
+)
+
+// ArchiveTasks moves the done tasks to the archive, only the ones done
+// before the period if it is given, like "14d".
+func (use *UseCases) ArchiveTasks(ctx context.Context, olderThan string) ([]*domain.Task, error) {
+	const where = "ArchiveTasks"
+
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
+	var period time.Duration
+
+	if olderThan != "" {
+		parsed, err := ParsePeriod(olderThan)
+		if err != nil {
+			return nil, fmt.Errorf("%s error: %w", where, err)
+		}
+
+		period = parsed
+	}
+
+	tasks, err := use.storage.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	// the task is done by its last update, done is the completion of the workflow
+	now := time.Now()
+	cutoff := now.Add(-period)
+	tasks = slices.DeleteFunc(tasks, func(task *domain.Task) bool {
+		return task.Status != use.workflow.Completed() || task.UpdatedAt.After(cutoff)
+	})
+
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+
+	slices.SortFunc(tasks, func(a, b *domain.Task) int { return cmp.Compare(a.ID, b.ID) })
+
+	revisions := make([]domain.Revision, len(tasks))
+
+	for idx, task := range tasks {
+		before := snapshot(task)
+
+		task.ArchivedAt = now
+		task.History = append(task.History, use.change(domain.FieldTask, "", domain.TaskArchived, now))
+		revisions[idx] = revise(&before, task)
+	}
+
+	err = use.storage.ArchiveTasks(ctx, tasks)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	err = use.writeJournal(ctx, where, revisions...)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return tasks, nil
+}
+
+// scoped lists the active tasks by the given way, the archived ones or all of them.
+func (use *UseCases) scoped(
+	ctx context.Context, scope Scope, active func() ([]*domain.Task, error),
+) ([]*domain.Task, error) {
+	tasks := make([]*domain.Task, 0)
+
+	if scope != ScopeArchived {
+		list, err := active()
+		if err != nil {
+			return nil, err
+		}
+
+		tasks = append(tasks, list...)
+	}
+
+	if scope != ScopeActive {
+		list, err := use.storage.ListArchived(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		tasks = append(tasks, list...)
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,187 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+	"github.com/therenotomorrow/tasker/internal/storage"
+	"github.com/therenotomorrow/tasker/internal/usecases"
+	"github.com/therenotomorrow/tasker/pkg/testkit"
+)
+
+func TestUnitUseCasesArchiveTasks(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		ids []uint64
+		err error
+	}
+
+	tests := []struct {
+		name      string
+		olderThan string
+		want      want
+	}{
+		{name: "invalid period", olderThan: "fortnight", want: want{err: domain.ErrInvalidPeriod}},
+		{name: "overflowed period", olderThan: "9999999999999d", want: want{err: domain.ErrInvalidPeriod}},
+		{name: "list failure", olderThan: "", want: want{err: testkit.ErrDummy}},
+		{name: testkit.FailureTest, olderThan: "", want: want{err: testkit.ErrDummy}},
+		{name: "journal failure", olderThan: "", want: want{err: testkit.ErrDummy}},
+		{name: "older than", olderThan: "14d", want: want{ids: []uint64{1}}},
+		{name: "nothing older", olderThan: "8w", want: want{ids: []uint64{}}},
+		{name: testkit.SuccessTest, olderThan: "", want: want{ids: []uint64{1, 4}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Now()
+			stor := new(storage.Mock)
+
+			stor.ListAllFunc = func(ctx context.Context) ([]*domain.Task, error) {
+				if test.name == "list failure" {
+					return nil, testkit.ErrDummy
+				}
+
+				// the cancelled task is closed, but not done
+				return []*domain.Task{
+					{ID: 4, Status: domain.StatusDone, UpdatedAt: now},
+					{ID: 3, Status: domain.StatusCancelled, UpdatedAt: now.AddDate(0, 0, -30)},
+					{ID: 2, Status: domain.StatusTodo, UpdatedAt: now.AddDate(0, 0, -30)},
+					{ID: 1, Status: domain.StatusDone, UpdatedAt: now.AddDate(0, 0, -30)},
+				}, nil
+			}
+			stor.ArchiveTasksFunc = func(ctx context.Context, tasks []*domain.Task) error {
+				if test.name == testkit.FailureTest {
+					return testkit.ErrDummy
+				}
+
+				return nil
+			}
+
+			var journaled []domain.Revision
+
+			stor.AppendOperationFunc = func(ctx context.Context, op *domain.Operation) (*domain.Operation, error) {
+				if test.name == "journal failure" {
+					return nil, testkit.ErrDummy
+				}
+
+				journaled = op.Revisions
+
+				return op, nil
+			}
+
+			use := usecases.New(usecases.Config{Storage: stor, Journal: stor})
+			got, err := use.ArchiveTasks(t.Context(), test.olderThan)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("ArchiveTasks() error = %v, want = %v", err, test.want.err)
+			}
+
+			if ids := taskIDs(got); err == nil && !reflect.DeepEqual(ids, test.want.ids) {
+				t.Errorf("ArchiveTasks() got = %v, want = %v", ids, test.want.ids)
+			}
+
+			for idx, task := range got {
+				last := task.History[len(task.History)-1]
+				if !task.IsArchived() || last.New != domain.TaskArchived {
+					t.Errorf("ArchiveTasks() got = %v, want = %v", task, "archived task")
+				}
+
+				revision := journaled[idx]
+				if revision.Before.IsArchived() || !revision.After.IsArchived() {
+					t.Errorf("ArchiveTasks() got = %v, want = %v", revision, "journaled archive")
+				}
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesListTasksScope(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		ids []uint64
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		params usecases.ListParams
+		want   want
+	}{
+		{name: "active", params: usecases.ListParams{Scope: usecases.ScopeActive}, want: want{ids: []uint64{1}}},
+		{name: "archived", params: usecases.ListParams{Scope: usecases.ScopeArchived}, want: want{ids: []uint64{2, 3}}},
+		{name: "all", params: usecases.ListParams{Scope: usecases.ScopeAll}, want: want{ids: []uint64{1, 2, 3}}},
+		{
+			name:   "archived by tags",
+			params: usecases.ListParams{Scope: usecases.ScopeArchived, Tags: []string{"infra"}},
+			want:   want{ids: []uint64{3}},
+		},
+		{
+			name:   "archived by project",
+			params: usecases.ListParams{Scope: usecases.ScopeAll, Project: "platform"},
+			want:   want{ids: []uint64{1, 2}},
+		},
+		{name: "active failure", params: usecases.ListParams{Scope: usecases.ScopeAll}, want: want{err: testkit.ErrDummy}},
+		{name: testkit.FailureTest, params: usecases.ListParams{Scope: usecases.ScopeAll}, want: want{err: testkit.ErrDummy}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Now()
+			active := []*domain.Task{{ID: 1, Status: domain.StatusTodo, Project: "platform", UpdatedAt: now}}
+			stor := new(storage.Mock)
+
+			stor.ListAllFunc = func(ctx context.Context) ([]*domain.Task, error) {
+				if test.name == "active failure" {
+					return nil, testkit.ErrDummy
+				}
+
+				return active, nil
+			}
+			stor.ListFunc = func(ctx context.Context, opts domain.ListOptions) ([]*domain.Task, error) {
+				tasks, err := stor.ListAll(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				return opts.Apply(tasks), nil
+			}
+			stor.ListByTagsFunc = func(ctx context.Context, query domain.TagQuery) ([]*domain.Task, error) {
+				return make([]*domain.Task, 0), nil
+			}
+			stor.ListByProjectFunc = func(ctx context.Context, project string) ([]*domain.Task, error) {
+				return active, nil
+			}
+			stor.ListArchivedFunc = func(ctx context.Context) ([]*domain.Task, error) {
+				if test.name == testkit.FailureTest {
+					return nil, testkit.ErrDummy
+				}
+
+				return []*domain.Task{
+					{ID: 2, Status: domain.StatusDone, Project: "platform", UpdatedAt: now.Add(-time.Hour)},
+					{ID: 3, Status: domain.StatusDone, Tags: []string{"infra"}, UpdatedAt: now.Add(-2 * time.Hour)},
+				}, nil
+			}
+
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.ListTasks(t.Context(), test.params)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("ListTasks() error = %v, want = %v", err, test.want.err)
+			}
+
+			if ids := taskIDs(got); err == nil && !reflect.DeepEqual(ids, test.want.ids) {
+				t.Errorf("ListTasks() got = %v, want = %v", ids, test.want.ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+const (
+	daysInWeek = 7
+	hoursInDay = 24
+	daysInYear = 366
+	// maxYear is the last year the task file could keep.
+	maxYear = 9999
+	// maxOffset is the hours taking any date past the max year, so larger ones are refused early.
+	maxOffset = (maxYear + 1) * daysInYear * hoursInDay
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// ParseDate understands the exact "2006-01-02 15:04" and RFC3339 times, the
+// dates "2006-01-02", "today", "tomorrow", "eow" (end of week), "fri" (this or
+// the coming friday), "next fri" and the relative "+3d", "+2w" or "+4h" forms.
+// Dates mean the end of the day, so the task is due during the whole day.
+func ParseDate(raw string, now time.Time) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04"} {
+		if date, err := time.ParseInLocation(layout, raw, now.Location()); err == nil {
+			return date, nil
+		}
+	}
+
+	raw = strings.ToLower(raw)
+
+	if date, err := time.ParseInLocation(time.DateOnly, raw, now.Location()); err == nil {
+		return endOfDay(date), nil
+	}
+
+	switch raw {
+	case "today":
+		return endOfDay(today), nil
+	case "tomorrow":
+		return endOfDay(today.AddDate(0, 0, 1)), nil
+	case "eow":
+		// weeks start on monday, so sunday is the last day
+		days := (daysInWeek - int(today.Weekday())) % daysInWeek
+
+		return endOfDay(today.AddDate(0, 0, days)), nil
+	}
+
+	if strings.HasPrefix(raw, "+") {
+		return parseOffset(raw[1:], now, today)
+	}
+
+	next := strings.HasPrefix(raw, "next ")
+
+	weekday, ok := weekdays[strings.TrimPrefix(raw, "next ")]
+	if !ok {
+		return time.Time{}, domain.ErrInvalidDate
+	}
+
+	days := (int(weekday) - int(today.Weekday()) + daysInWeek) % daysInWeek
+	if next && days == 0 {
+		days = daysInWeek
+	}
+
+	return endOfDay(today.AddDate(0, 0, days)), nil
+}
+
+func parseOffset(raw string, now time.Time, today time.Time) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, domain.ErrInvalidDate
+	}
+
+	amount, err := strconv.Atoi(raw[:len(raw)-1])
+	if err != nil || amount < 0 || amount > maxOffset {
+		return time.Time{}, domain.ErrInvalidDate
+	}
+
+	var date time.Time
+
+	switch raw[len(raw)-1] {
+	case 'h':
+		// the hours past the duration range would wrap around to the past
+		if amount > math.MaxInt64/int(time.Hour) {
+			return time.Time{}, domain.ErrInvalidDate
+		}
+
+		date = now.Add(time.Duration(amount) * time.Hour)
+	case 'd':
+		date = endOfDay(today.AddDate(0, 0, amount))
+	case 'w':
+		date = endOfDay(today.AddDate(0, 0, amount*daysInWeek))
+	default:
+		return time.Time{}, domain.ErrInvalidDate
+	}
+
+	if date.Year() < 0 || date.Year() > maxYear {
+		return time.Time{}, domain.ErrInvalidDate
+	}
+
+	return date, nil
+}
+
+// ParsePeriod understands the "12h", "30d" and "2w" periods.
+func ParsePeriod(raw string) (time.Duration, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if raw == "" {
+		return 0, domain.ErrInvalidPeriod
+	}
+
+	amount, err := strconv.Atoi(raw[:len(raw)-1])
+	if err != nil || amount < 0 {
+		return 0, domain.ErrInvalidPeriod
+	}
+
+	var hours int
+
+	switch raw[len(raw)-1] {
+	case 'h':
+		hours = 1
+	case 'd':
+		hours = hoursInDay
+	case 'w':
+		hours = daysInWeek * hoursInDay
+	default:
+		return 0, domain.ErrInvalidPeriod
+	}
+
+	// the longer period overflows the duration
+	if amount > math.MaxInt64/int(time.Hour)/hours {
+		return 0, domain.ErrInvalidPeriod
+	}
+
+	return time.Duration(amount*hours) * time.Hour, nil
+}
+
+func endOfDay(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, day.Location()).Add(-time.Second)
+}
@@ -0,0 +1,140 @@
+package usecases_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+	"github.com/therenotomorrow/tasker/internal/usecases"
+)
+
+func TestUnitParseDate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		wednesday = time.Date(2026, 5, 6, 10, 30, 0, 0, time.UTC)
+		sunday    = time.Date(2026, 5, 10, 10, 30, 0, 0, time.UTC)
+		endOfDay  = func(month time.Month, day int) time.Time {
+			return time.Date(2026, month, day, 23, 59, 59, 0, time.UTC)
+		}
+	)
+
+	type args struct {
+		raw string
+		now time.Time
+	}
+
+	type want struct {
+		date time.Time
+		err  error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{name: "iso date", args: args{raw: "2026-05-20", now: wednesday}, want: want{date: endOfDay(5, 20)}},
+		{
+			name: "iso time",
+			args: args{raw: "2026-05-20 14:00", now: wednesday},
+			want: want{date: time.Date(2026, 5, 20, 14, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "rfc3339",
+			args: args{raw: "2026-05-20T14:00:00Z", now: wednesday},
+			want: want{date: time.Date(2026, 5, 20, 14, 0, 0, 0, time.UTC)},
+		},
+		{name: "today", args: args{raw: "today", now: wednesday}, want: want{date: endOfDay(5, 6)}},
+		{name: "tomorrow", args: args{raw: " Tomorrow ", now: wednesday}, want: want{date: endOfDay(5, 7)}},
+		{name: "end of week", args: args{raw: "eow", now: wednesday}, want: want{date: endOfDay(5, 10)}},
+		{name: "end of week on sunday", args: args{raw: "eow", now: sunday}, want: want{date: endOfDay(5, 10)}},
+		{name: "weekday", args: args{raw: "fri", now: wednesday}, want: want{date: endOfDay(5, 8)}},
+		{name: "same weekday", args: args{raw: "wed", now: wednesday}, want: want{date: endOfDay(5, 6)}},
+		{name: "next weekday", args: args{raw: "next friday", now: wednesday}, want: want{date: endOfDay(5, 8)}},
+		{name: "next same weekday", args: args{raw: "next wed", now: wednesday}, want: want{date: endOfDay(5, 13)}},
+		{name: "days", args: args{raw: "+3d", now: wednesday}, want: want{date: endOfDay(5, 9)}},
+		{name: "weeks", args: args{raw: "+2w", now: wednesday}, want: want{date: endOfDay(5, 20)}},
+		{
+			name: "hours",
+			args: args{raw: "+4h", now: wednesday},
+			want: want{date: time.Date(2026, 5, 6, 14, 30, 0, 0, time.UTC)},
+		},
+		{name: "unknown word", args: args{raw: "someday", now: wednesday}, want: want{err: domain.ErrInvalidDate}},
+		{name: "unknown weekday", args: args{raw: "next", now: wednesday}, want: want{err: domain.ErrInvalidDate}},
+		{name: "empty offset", args: args{raw: "+", now: wednesday}, want: want{err: domain.ErrInvalidDate}},
+		{name: "invalid offset", args: args{raw: "+xd", now: wednesday}, want: want{err: domain.ErrInvalidDate}},
+		{name: "negative offset", args: args{raw: "+-1d", now: wednesday}, want: want{err: domain.ErrInvalidDate}},
+		{name: "unknown unit", args: args{raw: "+3y", now: wednesday}, want: want{err: domain.ErrInvalidDate}},
+		{
+			name: "last day",
+			args: args{raw: "+2912317d", now: wednesday},
+			want: want{date: time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)},
+		},
+		{name: "past last day", args: args{raw: "+2912318d", now: wednesday}, want: want{err: domain.ErrInvalidDate}},
+		{name: "overflow", args: args{raw: "+9999999999999999w", now: wednesday}, want: want{err: domain.ErrInvalidDate}},
+		{name: "hours overflow", args: args{raw: "+2600000h", now: wednesday}, want: want{err: domain.ErrInvalidDate}},
+		{name: "hours wrap", args: args{raw: "+5000000h", now: wednesday}, want: want{err: domain.ErrInvalidDate}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := usecases.ParseDate(test.args.raw, test.args.now)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("ParseDate() error = %v, want = %v", err, test.want.err)
+			}
+
+			if !got.Equal(test.want.date) {
+				t.Errorf("ParseDate() got = %v, want = %v", got, test.want.date)
+			}
+		})
+	}
+}
+
+func TestUnitParsePeriod(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		period time.Duration
+		err    error
+	}
+
+	tests := []struct {
+		name string
+		raw  string
+		want want
+	}{
+		{name: "hours", raw: "12h", want: want{period: 12 * time.Hour}},
+		{name: "days", raw: " 30D ", want: want{period: 30 * 24 * time.Hour}},
+		{name: "weeks", raw: "2w", want: want{period: 14 * 24 * time.Hour}},
+		{name: "zero", raw: "0d", want: want{period: 0}},
+		{name: "empty", raw: "", want: want{err: domain.ErrInvalidPeriod}},
+		{name: "negative", raw: "-1d", want: want{err: domain.ErrInvalidPeriod}},
+		{name: "no amount", raw: "d", want: want{err: domain.ErrInvalidPeriod}},
+		{name: "unknown unit", raw: "3m", want: want{err: domain.ErrInvalidPeriod}},
+		{name: "longest", raw: "106751d", want: want{period: 106751 * 24 * time.Hour}},
+		{name: "overflow days", raw: "9999999999999d", want: want{err: domain.ErrInvalidPeriod}},
+		{name: "overflow weeks", raw: "15251w", want: want{err: domain.ErrInvalidPeriod}},
+		{name: "overflow hours", raw: "2562048h", want: want{err: domain.ErrInvalidPeriod}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := usecases.ParsePeriod(test.raw)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("ParsePeriod() error = %v, want = %v", err, test.want.err)
+			}
+
+			if got != test.want.period {
+				t.Errorf("ParsePeriod() got = %v, want = %v", got, test.want.period)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+// dependencies indexes tasks by ID to walk "blocked by" edges.
+type dependencies map[uint64]*domain.Task
+
+func newDependencies(tasks []*domain.Task) dependencies {
+	deps := make(dependencies, len(tasks))
+	for _, task := range tasks {
+		deps[task.ID] = task
+	}
+
+	return deps
+}
+
+// isBlocked tells if any of the task dependencies is still open in the workflow,
+// dependencies that were deleted block nothing.
+func (deps dependencies) isBlocked(task *domain.Task, flow *domain.Workflow) bool {
+	for _, tid := range task.BlockedBy {
+		if dep, ok := deps[tid]; ok && !flow.IsTerminal(dep.Status) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reaches tells if the "from" task depends on the "to" one directly or through others.
+func (deps dependencies) reaches(from uint64, to uint64) bool {
+	seen := make(map[uint64]bool)
+	stack := []uint64{from}
+
+	for len(stack) > 0 {
+		tid := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if tid == to {
+			return true
+		}
+
+		if seen[tid] {
+			continue
+		}
+
+		seen[tid] = true
+
+		if task, ok := deps[tid]; ok {
+			stack = append(stack, task.BlockedBy...)
+		}
+	}
+
+	return false
+}
+
+// DependencyNode is the task together with the tasks it depends on.
+type DependencyNode struct {
+	Task      *domain.Task
+	Blocked   bool
+	BlockedBy []*DependencyNode
+}
+
+func (deps dependencies) node(task *domain.Task, path map[uint64]bool, flow *domain.Workflow) *DependencyNode {
+	node := &DependencyNode{Task: task, Blocked: deps.isBlocked(task, flow), BlockedBy: make([]*DependencyNode, 0)}
+
+	// the path guards against loops in broken files
+	path[task.ID] = true
+	defer delete(path, task.ID)
+
+	for _, tid := range task.BlockedBy {
+		if dep, ok := deps[tid]; ok && !path[tid] {
+			node.BlockedBy = append(node.BlockedBy, deps.node(dep, path, flow))
+		}
+	}
+
+	return node
+}
+
+type DependOptions struct {
+	// Remove drops the dependency instead of adding it.
+	Remove bool
+}
+
+func (use *UseCases) DependTask(ctx context.Context, tid string, on string, opts DependOptions) (*domain.Task, error) {
+	const where = "DependTask"
+
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
+	taskID, err := use.validateTaskID(tid)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	onID, err := use.validateTaskID(on)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	deps, err := use.listDependencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	task, ok := deps[taskID]
+	if !ok {
+		return nil, fmt.Errorf("%s error: %w", where, domain.ErrTaskNotFound)
+	}
+
+	// the archived tasks are not listed, but they are still the ones to depend on
+	if !opts.Remove {
+		_, err = use.storage.GetByID(ctx, onID)
+	}
+
+	before := snapshot(task)
+
+	switch {
+	case opts.Remove:
+		task.BlockedBy = slices.DeleteFunc(task.BlockedBy, func(dep uint64) bool { return dep == onID })
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return nil, fmt.Errorf("%s error: %w", where, domain.ErrDependencyNotFound)
+	case err != nil:
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	case deps.reaches(onID, taskID):
+		return nil, fmt.Errorf("%s error: %w", where, domain.ErrDependencyCycle)
+	default:
+		task.BlockedBy = append(task.BlockedBy, onID)
+		slices.Sort(task.BlockedBy)
+		task.BlockedBy = slices.Compact(task.BlockedBy)
+	}
+
+	task.UpdatedAt = time.Now()
+	use.record(task, before)
+
+	err = use.storage.UpdateTask(ctx, task)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	err = use.writeJournal(ctx, where, revise(&before, task))
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return task, nil
+}
+
+func (use *UseCases) DependencyGraph(ctx context.Context, tid string) (*DependencyNode, error) {
+	const where = "DependencyGraph"
+
+	taskID, err := use.validateTaskID(tid)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	deps, err := use.listDependencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	task, ok := deps[taskID]
+	if !ok {
+		return nil, fmt.Errorf("%s error: %w", where, domain.ErrTaskNotFound)
+	}
+
+	return deps.node(task, make(map[uint64]bool), use.workflow), nil
+}
+
+func (use *UseCases) listDependencies(ctx context.Context) (dependencies, error) {
+	tasks, err := use.storage.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDependencies(tasks), nil
+}
+
+// checkBlocked refuses to start or finish the task while any of its dependencies is open.
+func (use *UseCases) checkBlocked(ctx context.Context, task *domain.Task) error {
+	for _, tid := range task.BlockedBy {
+		dep, err := use.storage.GetByID(ctx, tid)
+
+		switch {
+		case errors.Is(err, domain.ErrTaskNotFound):
+			continue
+		case err != nil:
+			return err
+		case use.isOpen(dep):
+			return domain.ErrTaskBlocked
+		}
+	}
+
+	return nil
+}
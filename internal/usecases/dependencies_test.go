@@ -0,0 +1,215 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+	"github.com/therenotomorrow/tasker/internal/storage"
+	"github.com/therenotomorrow/tasker/internal/usecases"
+	"github.com/therenotomorrow/tasker/pkg/testkit"
+)
+
+// dependentTasks are 3 blocked by 2 blocked by done 1, and 5 blocked by deleted 99.
+func dependentTasks() []*domain.Task {
+	return []*domain.Task{
+		{ID: 1, Status: domain.StatusDone},
+		{ID: 2, Status: domain.StatusTodo, BlockedBy: []uint64{1}},
+		{ID: 3, Status: domain.StatusTodo, BlockedBy: []uint64{2}},
+		{ID: 4, Status: domain.StatusProgress},
+		{ID: 5, Status: domain.StatusTodo, BlockedBy: []uint64{99}},
+		{ID: 6, Status: domain.StatusCancelled},
+		{ID: 7, Status: domain.StatusTodo, BlockedBy: []uint64{6}},
+	}
+}
+
+func TestUnitUseCasesDependTask(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		tid  string
+		on   string
+		opts usecases.DependOptions
+	}
+
+	type want struct {
+		blockedBy []uint64
+		err       error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{name: "invalid taskID", args: args{tid: "one", on: "2"}, want: want{err: domain.ErrInvalidTaskID}},
+		{name: "invalid dependency", args: args{tid: "1", on: "two"}, want: want{err: domain.ErrInvalidTaskID}},
+		{name: "list failure", args: args{tid: "1", on: "2"}, want: want{err: testkit.ErrDummy}},
+		{name: taskNotFoundTest, args: args{tid: "42", on: "2"}, want: want{err: domain.ErrTaskNotFound}},
+		{name: "dependency not found", args: args{tid: "4", on: "42"}, want: want{err: domain.ErrDependencyNotFound}},
+		{name: "self", args: args{tid: "3", on: "3"}, want: want{err: domain.ErrDependencyCycle}},
+		{name: "cycle", args: args{tid: "1", on: "3"}, want: want{err: domain.ErrDependencyCycle}},
+		{name: testkit.FailureTest, args: args{tid: "4", on: "3"}, want: want{err: testkit.ErrDummy}},
+		{name: testkit.SuccessTest, args: args{tid: "4", on: "3"}, want: want{blockedBy: []uint64{3}}},
+		{name: "sorted", args: args{tid: "3", on: "1"}, want: want{blockedBy: []uint64{1, 2}}},
+		{name: "duplicate", args: args{tid: "3", on: "2"}, want: want{blockedBy: []uint64{2}}},
+		{name: "archived", args: args{tid: "4", on: "8"}, want: want{blockedBy: []uint64{8}}},
+		{name: "lookup failure", args: args{tid: "4", on: "3"}, want: want{err: testkit.ErrDummy}},
+		{
+			name: "remove",
+			args: args{tid: "3", on: "2", opts: usecases.DependOptions{Remove: true}},
+			want: want{blockedBy: []uint64{}},
+		},
+		{
+			name: "remove deleted",
+			args: args{tid: "5", on: "99", opts: usecases.DependOptions{Remove: true}},
+			want: want{blockedBy: []uint64{}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.ListAllFunc = func(ctx context.Context) ([]*domain.Task, error) {
+				if test.name == "list failure" {
+					return nil, testkit.ErrDummy
+				}
+
+				return dependentTasks(), nil
+			}
+			stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+				if test.name == "lookup failure" {
+					return nil, testkit.ErrDummy
+				}
+
+				// the archived task is found by its ID only
+				tasks := append(dependentTasks(), &domain.Task{ID: 8, Status: domain.StatusDone, ArchivedAt: time.Now()})
+
+				idx := slices.IndexFunc(tasks, func(task *domain.Task) bool { return task.ID == tid })
+				if idx < 0 {
+					return nil, domain.ErrTaskNotFound
+				}
+
+				return tasks[idx], nil
+			}
+			stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
+				if test.name == testkit.FailureTest {
+					return testkit.ErrDummy
+				}
+
+				return nil
+			}
+
+			ctx := t.Context()
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.DependTask(ctx, test.args.tid, test.args.on, test.args.opts)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("DependTask() error = %v, want = %v", err, test.want.err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(got.BlockedBy, test.want.blockedBy) {
+				t.Errorf("DependTask() got = %v, want = %v", got.BlockedBy, test.want.blockedBy)
+			}
+
+			if time.Since(got.UpdatedAt) > time.Minute {
+				t.Errorf("DependTask() got = %v, want = %v", got.UpdatedAt, time.Now())
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesDependencyGraph(t *testing.T) {
+	t.Parallel()
+
+	tasks := dependentTasks()
+
+	type want struct {
+		node *usecases.DependencyNode
+		err  error
+	}
+
+	tests := []struct {
+		name string
+		tid  string
+		want want
+	}{
+		{name: "invalid taskID", tid: "one", want: want{err: domain.ErrInvalidTaskID}},
+		{name: testkit.FailureTest, tid: "3", want: want{err: testkit.ErrDummy}},
+		{name: taskNotFoundTest, tid: "42", want: want{err: domain.ErrTaskNotFound}},
+		{
+			name: testkit.SuccessTest,
+			tid:  "3",
+			want: want{node: &usecases.DependencyNode{
+				Task:    tasks[2],
+				Blocked: true,
+				BlockedBy: []*usecases.DependencyNode{{
+					Task:    tasks[1],
+					Blocked: false,
+					BlockedBy: []*usecases.DependencyNode{
+						{Task: tasks[0], Blocked: false, BlockedBy: []*usecases.DependencyNode{}},
+					},
+				}},
+			}},
+		},
+		{
+			name: "deleted dependency",
+			tid:  "5",
+			want: want{node: &usecases.DependencyNode{
+				Task:      tasks[4],
+				Blocked:   false,
+				BlockedBy: []*usecases.DependencyNode{},
+			}},
+		},
+		{
+			name: "cancelled dependency",
+			tid:  "7",
+			want: want{node: &usecases.DependencyNode{
+				Task:    tasks[6],
+				Blocked: false,
+				BlockedBy: []*usecases.DependencyNode{
+					{Task: tasks[5], Blocked: false, BlockedBy: []*usecases.DependencyNode{}},
+				},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.ListAllFunc = func(ctx context.Context) ([]*domain.Task, error) {
+				if test.name == testkit.FailureTest {
+					return nil, testkit.ErrDummy
+				}
+
+				return dependentTasks(), nil
+			}
+
+			ctx := t.Context()
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.DependencyGraph(ctx, test.tid)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("DependencyGraph() error = %v, want = %v", err, test.want.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want.node) {
+				t.Errorf("DependencyGraph() got = %v, want = %v", got, test.want.node)
+			}
+		})
+	}
+}
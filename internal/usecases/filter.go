@@ -0,0 +1,463 @@
+package usecases
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+type FilterOp string
+
+const (
+	OpEqual        FilterOp = ":"
+	OpContains     FilterOp = "~"
+	OpGreater      FilterOp = ">"
+	OpGreaterEqual FilterOp = ">="
+	OpLess         FilterOp = "<"
+	OpLessEqual    FilterOp = "<="
+)
+
+const (
+	filterNegation  = '-'
+	filterQuote     = '"'
+	filterSeparator = ','
+)
+
+// Filter is the parsed filter expression like `status:todo,progress prio>=high -tag:infra`,
+// the task must match all of its terms.
+type Filter struct {
+	Terms []*FilterTerm
+}
+
+// FilterTerm compares the field of the task with any of the values, positions are in bytes.
+type FilterTerm struct {
+	Pos     int
+	Negated bool
+	Field   string
+	Op      FilterOp
+	Values  []FilterValue
+}
+
+type FilterValue struct {
+	Pos  int
+	Text string
+}
+
+// String gives the expression back in the form ParseFilter understands.
+func (f *Filter) String() string {
+	terms := make([]string, len(f.Terms))
+
+	for idx, term := range f.Terms {
+		values := make([]string, len(term.Values))
+
+		for num, value := range term.Values {
+			values[num] = value.Text
+			if value.Text == "" || strings.ContainsFunc(value.Text, isFilterDelimiter) {
+				values[num] = string(filterQuote) + value.Text + string(filterQuote)
+			}
+		}
+
+		negation := ""
+		if term.Negated {
+			negation = string(filterNegation)
+		}
+
+		terms[idx] = negation + term.Field + string(term.Op) + strings.Join(values, string(filterSeparator))
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// ParseFilter checks the syntax of the expression only, the fields and the values are checked
+// when the filter is compiled.
+func ParseFilter(raw string) (*Filter, error) {
+	parser := filterParser{raw: raw, pos: 0}
+	filter := &Filter{Terms: make([]*FilterTerm, 0)}
+
+	for parser.skipSpaces(); !parser.done(); parser.skipSpaces() {
+		term, err := parser.term()
+		if err != nil {
+			return nil, err
+		}
+
+		filter.Terms = append(filter.Terms, term)
+	}
+
+	return filter, nil
+}
+
+type filterParser struct {
+	raw string
+	pos int
+}
+
+func (p *filterParser) done() bool {
+	return p.pos >= len(p.raw)
+}
+
+func (p *filterParser) peek() rune {
+	char, _ := utf8.DecodeRuneInString(p.raw[p.pos:])
+
+	return char
+}
+
+func (p *filterParser) skipSpaces() {
+	for !p.done() && unicode.IsSpace(p.peek()) {
+		_, size := utf8.DecodeRuneInString(p.raw[p.pos:])
+		p.pos += size
+	}
+}
+
+func (p *filterParser) fail(pos int, reason string, args ...any) error {
+	return &domain.FilterError{Pos: pos, Reason: fmt.Sprintf(reason, args...)}
+}
+
+func (p *filterParser) term() (*FilterTerm, error) {
+	term := &FilterTerm{Pos: p.pos, Negated: false, Field: "", Op: "", Values: make([]FilterValue, 0)}
+
+	if p.peek() == filterNegation {
+		term.Negated = true
+		p.pos++
+	}
+
+	start := p.pos
+	for !p.done() && isFilterLetter(p.peek()) {
+		p.pos++
+	}
+
+	if start == p.pos {
+		return nil, p.fail(p.pos, "expected field name")
+	}
+
+	term.Field = strings.ToLower(p.raw[start:p.pos])
+
+	for _, op := range []FilterOp{OpGreaterEqual, OpLessEqual, OpEqual, OpContains, OpGreater, OpLess} {
+		if strings.HasPrefix(p.raw[p.pos:], string(op)) {
+			term.Op = op
+			p.pos += len(op)
+
+			break
+		}
+	}
+
+	if term.Op == "" {
+		return nil, p.fail(p.pos, "expected operator after %q", term.Field)
+	}
+
+	for {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+
+		term.Values = append(term.Values, value)
+
+		if p.done() || p.peek() != filterSeparator {
+			break
+		}
+
+		p.pos++
+	}
+
+	if !p.done() && !unicode.IsSpace(p.peek()) {
+		return nil, p.fail(p.pos, "unexpected %q", p.peek())
+	}
+
+	return term, nil
+}
+
+func (p *filterParser) value() (FilterValue, error) {
+	start := p.pos
+
+	if !p.done() && p.peek() == filterQuote {
+		end := strings.IndexRune(p.raw[start+1:], filterQuote)
+		if end < 0 {
+			return FilterValue{Pos: 0, Text: ""}, p.fail(start, "unterminated quote")
+		}
+
+		p.pos = start + 1 + end + 1
+
+		return FilterValue{Pos: start, Text: p.raw[start+1 : p.pos-1]}, nil
+	}
+
+	for !p.done() && !isFilterDelimiter(p.peek()) {
+		_, size := utf8.DecodeRuneInString(p.raw[p.pos:])
+		p.pos += size
+	}
+
+	switch {
+	case !p.done() && p.peek() == filterQuote:
+		return FilterValue{Pos: 0, Text: ""}, p.fail(p.pos, "unexpected %q", filterQuote)
+	case start == p.pos:
+		return FilterValue{Pos: 0, Text: ""}, p.fail(start, "missing value")
+	}
+
+	return FilterValue{Pos: start, Text: p.raw[start:p.pos]}, nil
+}
+
+func isFilterLetter(char rune) bool {
+	return char < utf8.RuneSelf && unicode.IsLetter(char)
+}
+
+func isFilterDelimiter(char rune) bool {
+	return unicode.IsSpace(char) || char == filterSeparator || char == filterQuote
+}
+
+// taskMatcher tells if the task fits the compiled filter.
+type taskMatcher func(task *domain.Task) bool
+
+// compileFilter checks the fields, the operators and the values of the terms
+// and gives the matcher of all of them.
+func (use *UseCases) compileFilter(filter *Filter, now time.Time) (taskMatcher, error) {
+	matchers := make([]taskMatcher, 0, len(filter.Terms))
+
+	for _, term := range filter.Terms {
+		match, err := use.compileTerm(term, now)
+		if err != nil {
+			return nil, err
+		}
+
+		if term.Negated {
+			positive := match
+			match = func(task *domain.Task) bool { return !positive(task) }
+		}
+
+		matchers = append(matchers, match)
+	}
+
+	return func(task *domain.Task) bool {
+		for _, match := range matchers {
+			if !match(task) {
+				return false
+			}
+		}
+
+		return true
+	}, nil
+}
+
+func (use *UseCases) compileTerm(term *FilterTerm, now time.Time) (taskMatcher, error) {
+	switch term.Field {
+	case "status":
+		return use.compileStatus(term)
+	case "prio", "priority":
+		return compileOrdered(term, func(raw string) (int, error) {
+			prio, err := use.validatePriority(raw)
+
+			return prio.Weight(), err
+		}, func(task *domain.Task) (int, bool) { return task.Priority.Weight(), true })
+	case "id":
+		return compileOrdered(term, use.validateTaskID, func(task *domain.Task) (uint64, bool) { return task.ID, true })
+	case "parent":
+		return compileOrdered(term, use.validateTaskID, func(task *domain.Task) (uint64, bool) {
+			return task.ParentID, task.HasParent()
+		})
+	case "created":
+		return compileDates(term, now, func(task *domain.Task) (time.Time, bool) { return task.CreatedAt, true })
+	case "updated":
+		return compileDates(term, now, func(task *domain.Task) (time.Time, bool) { return task.UpdatedAt, true })
+	case "due":
+		return compileDates(term, now, func(task *domain.Task) (time.Time, bool) { return task.DueAt, task.HasDue() })
+	case "desc", "description":
+		return compileText(term)
+	case "tag", "tags":
+		return use.compileTags(term)
+	case "project":
+		return use.compileProject(term)
+	default:
+		return nil, &domain.FilterError{Pos: fieldPos(term), Reason: fmt.Sprintf("unknown field %q", term.Field)}
+	}
+}
+
+func fieldPos(term *FilterTerm) int {
+	if term.Negated {
+		return term.Pos + 1
+	}
+
+	return term.Pos
+}
+
+func checkOps(term *FilterTerm, ops ...FilterOp) error {
+	if !slices.Contains(ops, term.Op) {
+		pos := fieldPos(term) + len(term.Field)
+
+		return &domain.FilterError{Pos: pos, Reason: fmt.Sprintf("operator %q is not supported by %q", term.Op, term.Field)}
+	}
+
+	// only the equality takes any of the values
+	if term.Op != OpEqual && term.Op != OpContains && len(term.Values) > 1 {
+		return &domain.FilterError{Pos: term.Values[1].Pos, Reason: fmt.Sprintf("operator %q takes one value", term.Op)}
+	}
+
+	return nil
+}
+
+func invalidValue(value FilterValue, field string) error {
+	return &domain.FilterError{Pos: value.Pos, Reason: fmt.Sprintf("invalid %s %q", field, value.Text)}
+}
+
+func (use *UseCases) compileStatus(term *FilterTerm) (taskMatcher, error) {
+	err := checkOps(term, OpEqual)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]domain.Status, len(term.Values))
+
+	for idx, value := range term.Values {
+		statuses[idx], err = use.validateStatus(value.Text)
+		if err != nil {
+			return nil, invalidValue(value, term.Field)
+		}
+	}
+
+	return func(task *domain.Task) bool { return slices.Contains(statuses, task.Status) }, nil
+}
+
+// compileOrdered compares the field of the task, the task without the field never matches.
+func compileOrdered[T int | uint64](
+	term *FilterTerm, parse func(raw string) (T, error), field func(task *domain.Task) (T, bool),
+) (taskMatcher, error) {
+	err := checkOps(term, OpEqual, OpGreater, OpGreaterEqual, OpLess, OpLessEqual)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]T, len(term.Values))
+
+	for idx, value := range term.Values {
+		values[idx], err = parse(value.Text)
+		if err != nil {
+			return nil, invalidValue(value, term.Field)
+		}
+	}
+
+	return func(task *domain.Task) bool {
+		got, ok := field(task)
+		if !ok {
+			return false
+		}
+
+		switch term.Op {
+		case OpGreater:
+			return got > values[0]
+		case OpGreaterEqual:
+			return got >= values[0]
+		case OpLess:
+			return got < values[0]
+		case OpLessEqual:
+			return got <= values[0]
+		default:
+			return slices.Contains(values, got)
+		}
+	}, nil
+}
+
+// compileDates compares the times with the whole day when the value is the date.
+func compileDates(
+	term *FilterTerm, now time.Time, field func(task *domain.Task) (time.Time, bool),
+) (taskMatcher, error) {
+	err := checkOps(term, OpEqual, OpGreater, OpGreaterEqual, OpLess, OpLessEqual)
+	if err != nil {
+		return nil, err
+	}
+
+	type period struct{ from, to time.Time }
+
+	periods := make([]period, len(term.Values))
+
+	for idx, value := range term.Values {
+		var when time.Time
+
+		when, err = ParseDate(value.Text, now)
+		if err != nil {
+			return nil, invalidValue(value, "date")
+		}
+
+		periods[idx] = period{from: when, to: when}
+		if endOfDay(when).Equal(when) {
+			periods[idx].from = when.Add(time.Second).AddDate(0, 0, -1)
+		}
+	}
+
+	return func(task *domain.Task) bool {
+		got, ok := field(task)
+		if !ok {
+			return false
+		}
+
+		switch term.Op {
+		case OpGreater:
+			return got.After(periods[0].to)
+		case OpGreaterEqual:
+			return !got.Before(periods[0].from)
+		case OpLess:
+			return got.Before(periods[0].from)
+		case OpLessEqual:
+			return !got.After(periods[0].to)
+		default:
+			return slices.ContainsFunc(periods, func(day period) bool {
+				return !got.Before(day.from) && !got.After(day.to)
+			})
+		}
+	}, nil
+}
+
+// compileText matches the description ignoring the case, whole or any part of it.
+func compileText(term *FilterTerm) (taskMatcher, error) {
+	err := checkOps(term, OpEqual, OpContains)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(task *domain.Task) bool {
+		return slices.ContainsFunc(term.Values, func(value FilterValue) bool {
+			if term.Op == OpContains {
+				return strings.Contains(strings.ToLower(task.Description), strings.ToLower(value.Text))
+			}
+
+			return strings.EqualFold(task.Description, value.Text)
+		})
+	}, nil
+}
+
+func (use *UseCases) compileTags(term *FilterTerm) (taskMatcher, error) {
+	err := checkOps(term, OpEqual)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, len(term.Values))
+
+	for idx, value := range term.Values {
+		tags[idx], err = domain.NewTag(value.Text)
+		if err != nil {
+			return nil, invalidValue(value, "tag")
+		}
+	}
+
+	return func(task *domain.Task) bool { return slices.ContainsFunc(tags, task.HasTag) }, nil
+}
+
+func (use *UseCases) compileProject(term *FilterTerm) (taskMatcher, error) {
+	err := checkOps(term, OpEqual)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]string, len(term.Values))
+
+	for idx, value := range term.Values {
+		projects[idx], err = use.validateProject(value.Text)
+		if err != nil {
+			return nil, invalidValue(value, term.Field)
+		}
+	}
+
+	return func(task *domain.Task) bool { return slices.ContainsFunc(projects, task.InProject) }, nil
+}
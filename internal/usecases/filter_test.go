@@ -0,0 +1,230 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+	"github.com/therenotomorrow/tasker/internal/storage"
+	"github.com/therenotomorrow/tasker/internal/usecases"
+)
+
+func TestUnitParseFilter(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		filter string
+		err    *domain.FilterError
+	}
+
+	tests := []struct {
+		name string
+		raw  string
+		want want
+	}{
+		{name: "empty", raw: "  ", want: want{filter: ""}},
+		{
+			name: "terms",
+			raw:  ` Status:todo,progress  prio>=high -tag:infra desc~"deploy api" `,
+			want: want{filter: `status:todo,progress prio>=high -tag:infra desc~"deploy api"`},
+		},
+		{name: "quoted", raw: `desc:"",plain,"a,b"`, want: want{filter: `desc:"",plain,"a,b"`}},
+		{
+			name: "no field",
+			raw:  "status:todo :x",
+			want: want{err: &domain.FilterError{Pos: 12, Reason: "expected field name"}},
+		},
+		{name: "only negation", raw: "-", want: want{err: &domain.FilterError{Pos: 1, Reason: "expected field name"}}},
+		{
+			name: "no operator",
+			raw:  "todo",
+			want: want{err: &domain.FilterError{Pos: 4, Reason: `expected operator after "todo"`}},
+		},
+		{name: "no value", raw: "prio>= x", want: want{err: &domain.FilterError{Pos: 6, Reason: "missing value"}}},
+		{name: "trailing comma", raw: "id:1,", want: want{err: &domain.FilterError{Pos: 5, Reason: "missing value"}}},
+		{
+			name: "open quote",
+			raw:  `desc~"deploy`,
+			want: want{err: &domain.FilterError{Pos: 5, Reason: "unterminated quote"}},
+		},
+		{
+			name: "quote inside",
+			raw:  `desc~de"ploy"`,
+			want: want{err: &domain.FilterError{Pos: 7, Reason: `unexpected '"'`}},
+		},
+		{
+			name: "after quote",
+			raw:  `desc~"de"ploy`,
+			want: want{err: &domain.FilterError{Pos: 9, Reason: `unexpected 'p'`}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := usecases.ParseFilter(test.raw)
+
+			if test.want.err != nil {
+				var filterErr *domain.FilterError
+				if !errors.As(err, &filterErr) || *filterErr != *test.want.err {
+					t.Fatalf("ParseFilter() error = %v, want = %v", err, test.want.err)
+				}
+
+				return
+			}
+
+			if err != nil || got.String() != test.want.filter {
+				t.Errorf("ParseFilter() got = %v, error = %v, want = %v", got, err, test.want.filter)
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesListTasksFilter(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		ids []uint64
+		err *domain.FilterError
+	}
+
+	now := time.Now()
+	tasks := []*domain.Task{
+		{
+			ID: 1, Description: "Deploy API", Status: domain.StatusTodo, Priority: domain.PriorityHigh,
+			CreatedAt: now, UpdatedAt: now, Tags: []string{"infra"}, Project: "platform.api",
+		},
+		{
+			ID: 2, Description: "write docs", Status: domain.StatusProgress, Priority: domain.PriorityLow,
+			CreatedAt: now.AddDate(0, 0, -3), UpdatedAt: now.Add(-time.Minute), DueAt: now.AddDate(0, 0, 1),
+			ParentID: 1,
+		},
+		{
+			ID: 3, Description: "deploy docs", Status: domain.StatusDone, Priority: domain.PriorityMedium,
+			CreatedAt: now.AddDate(0, 0, -10), UpdatedAt: now.Add(-time.Hour), Tags: []string{"docs"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		filter string
+		want   want
+	}{
+		{name: "status", filter: "status:todo,progress", want: want{ids: []uint64{1, 2}}},
+		{name: "priority", filter: "prio>=medium", want: want{ids: []uint64{1, 3}}},
+		{name: "description", filter: `desc~DEPLOY -desc:"deploy api"`, want: want{ids: []uint64{3}}},
+		{name: "tags", filter: "-tag:infra", want: want{ids: []uint64{2, 3}}},
+		{name: "project", filter: "project:platform", want: want{ids: []uint64{1}}},
+		{name: "created today", filter: "created:today", want: want{ids: []uint64{1}}},
+		{name: "created before", filter: "created<today", want: want{ids: []uint64{2, 3}}},
+		{name: "created since", filter: "created>=+0d updated<=+1h", want: want{ids: []uint64{1}}},
+		{name: "due", filter: "due<=tomorrow", want: want{ids: []uint64{2}}},
+		{name: "ids", filter: "id>1 parent:1", want: want{ids: []uint64{2}}},
+		{name: "nothing", filter: "id:4", want: want{err: nil}},
+		{
+			name:   "unknown field",
+			filter: "status:todo -size:1",
+			want:   want{err: &domain.FilterError{Pos: 13, Reason: `unknown field "size"`}},
+		},
+		{
+			name:   "unsupported operator",
+			filter: "tag>=infra",
+			want:   want{err: &domain.FilterError{Pos: 3, Reason: `operator ">=" is not supported by "tag"`}},
+		},
+		{
+			name:   "one value",
+			filter: "prio>low,high",
+			want:   want{err: &domain.FilterError{Pos: 9, Reason: `operator ">" takes one value`}},
+		},
+		{
+			name:   "invalid status",
+			filter: "status:todo,later",
+			want:   want{err: &domain.FilterError{Pos: 12, Reason: `invalid status "later"`}},
+		},
+		{
+			name:   "invalid date",
+			filter: "due<soon",
+			want:   want{err: &domain.FilterError{Pos: 4, Reason: `invalid date "soon"`}},
+		},
+		{
+			name:   "invalid id",
+			filter: "id:one",
+			want:   want{err: &domain.FilterError{Pos: 3, Reason: `invalid id "one"`}},
+		},
+		{
+			name:   "invalid tag",
+			filter: "tag:2fa",
+			want:   want{err: &domain.FilterError{Pos: 4, Reason: `invalid tag "2fa"`}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+			stor.ListAllFunc = func(ctx context.Context) ([]*domain.Task, error) {
+				return append([]*domain.Task(nil), tasks...), nil
+			}
+
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.ListTasks(t.Context(), usecases.ListParams{Filter: test.filter})
+
+			if test.want.err != nil {
+				var filterErr *domain.FilterError
+				if !errors.As(err, &filterErr) || *filterErr != *test.want.err {
+					t.Fatalf("ListTasks() error = %v, want = %v", err, test.want.err)
+				}
+
+				return
+			}
+
+			if test.want.ids == nil {
+				if !errors.Is(err, domain.ErrEmptyTasks) {
+					t.Fatalf("ListTasks() error = %v, want = %v", err, domain.ErrEmptyTasks)
+				}
+
+				return
+			}
+
+			if ids := taskIDs(got); !reflect.DeepEqual(ids, test.want.ids) {
+				t.Errorf("ListTasks() got = %v, error = %v, want = %v", ids, err, test.want.ids)
+			}
+		})
+	}
+}
+
+func FuzzParseFilter(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		`status:todo,progress prio>=high created>2026-01-01 desc~"deploy" -tag:infra`,
+		`desc:"",plain,"a,b"`,
+		`-id<=3 due<"next fri"`,
+		`desc~"open`,
+		"prio>= ,",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		filter, err := usecases.ParseFilter(raw)
+		if err != nil {
+			var filterErr *domain.FilterError
+			if !errors.As(err, &filterErr) || filterErr.Pos < 0 || filterErr.Pos > len(raw) {
+				t.Fatalf("ParseFilter(%q) error = %v, want = %v", raw, err, "position in the expression")
+			}
+
+			return
+		}
+
+		// the expression given back is parsed to the same one
+		again, err := usecases.ParseFilter(filter.String())
+		if err != nil || again.String() != filter.String() {
+			t.Fatalf("ParseFilter(%q) got = %v, error = %v, want = %v", filter.String(), again, err, filter)
+		}
+	})
+}
@@ -12,16 +12,47 @@ type Saver interface {
 
 type Updater interface {
 	UpdateTask(ctx context.Context, task *domain.Task) error
+	RestoreTasks(ctx context.Context, tasks []*domain.Task) error
+	ArchiveTasks(ctx context.Context, tasks []*domain.Task) error
 }
 
 type Deleter interface {
 	DeleteTask(ctx context.Context, task *domain.Task) error
+	DeleteTasks(ctx context.Context, tasks []*domain.Task) error
+	PurgeTasks(ctx context.Context, tasks []*domain.Task) error
 }
 
 type Retriever interface {
 	GetByID(ctx context.Context, tid uint64) (*domain.Task, error)
 	ListAll(ctx context.Context) ([]*domain.Task, error)
+	List(ctx context.Context, opts domain.ListOptions) ([]*domain.Task, error)
 	ListByStatus(ctx context.Context, status domain.Status) ([]*domain.Task, error)
+	ListByTags(ctx context.Context, query domain.TagQuery) ([]*domain.Task, error)
+	ListByProject(ctx context.Context, project string) ([]*domain.Task, error)
+	ListChildren(ctx context.Context, parentID uint64) ([]*domain.Task, error)
+	GetHistory(ctx context.Context, tid uint64) ([]domain.Change, error)
+	GetTrashed(ctx context.Context, tid uint64) (*domain.Task, error)
+	ListTrash(ctx context.Context) ([]*domain.Task, error)
+	ListArchived(ctx context.Context) ([]*domain.Task, error)
+	Search(ctx context.Context, query domain.SearchQuery) ([]*domain.Task, error)
+}
+
+type Migrator interface {
+	SchemaVersion(ctx context.Context) (domain.Schema, error)
+	Migrate(ctx context.Context) (domain.Schema, error)
+}
+
+// Journal keeps the operations made by the commands, so they could be undone and redone.
+type Journal interface {
+	AppendOperation(ctx context.Context, op *domain.Operation) (*domain.Operation, error)
+	UpdateOperation(ctx context.Context, op *domain.Operation) error
+	ListOperations(ctx context.Context) ([]*domain.Operation, error)
+}
+
+// Locker holds the storage till the result is called, the storage calls with the given context
+// do not wait for the lock again.
+type Locker interface {
+	Lock(ctx context.Context) (context.Context, func(), error)
 }
 
 type Storage interface {
@@ -29,4 +60,5 @@ type Storage interface {
 	Updater
 	Deleter
 	Retriever
+	Migrator
 }
@@ -29,3 +29,9 @@ func TestUnitStorageRetriever(t *testing.T) {
 
 	var _ usecases.Retriever = usecases.Storage(nil)
 }
+
+func TestUnitStorageMigrator(t *testing.T) {
+	t.Parallel()
+
+	var _ usecases.Migrator = usecases.Storage(nil)
+}
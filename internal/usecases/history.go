@@ -0,0 +1,54 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+// TaskHistory gives the changes of the task from the oldest one, the history
+// outlives the deleted task.
+func (use *UseCases) TaskHistory(ctx context.Context, tid string) ([]domain.Change, error) {
+	const where = "TaskHistory"
+
+	taskID, err := use.validateTaskID(tid)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	history, err := use.storage.GetHistory(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return history, nil
+}
+
+// snapshot copies the task before the changes, so the copy does not share slices with it.
+func snapshot(task *domain.Task) domain.Task {
+	before := *task
+	before.Tags = slices.Clone(task.Tags)
+	before.BlockedBy = slices.Clone(task.BlockedBy)
+	before.History = slices.Clone(task.History)
+
+	if task.Transition != nil {
+		transition := *task.Transition
+		before.Transition = &transition
+	}
+
+	return before
+}
+
+// record appends the changes made to the task since the snapshot to its history.
+func (use *UseCases) record(task *domain.Task, before domain.Task) {
+	for _, change := range domain.Diff(before, *task) {
+		task.History = append(task.History, use.change(change.Field, change.Old, change.New, task.UpdatedAt))
+	}
+}
+
+func (use *UseCases) change(field string, old string, value string, now time.Time) domain.Change {
+	return domain.Change{At: now, Actor: use.actor, Field: field, Old: old, New: value}
+}
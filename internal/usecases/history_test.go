@@ -0,0 +1,96 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+	"github.com/therenotomorrow/tasker/internal/storage"
+	"github.com/therenotomorrow/tasker/internal/usecases"
+	"github.com/therenotomorrow/tasker/pkg/testkit"
+)
+
+func TestUnitUseCasesTaskHistory(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		history []domain.Change
+		err     error
+	}
+
+	history := []domain.Change{
+		{At: time.Now(), Actor: "alice", Field: domain.FieldTask, New: domain.TaskAdded},
+		{At: time.Now(), Actor: "alice", Field: "status", Old: "todo", New: "progress"},
+	}
+
+	tests := []struct {
+		name string
+		tid  string
+		want want
+	}{
+		{name: "invalid taskID", tid: "invalid", want: want{err: domain.ErrInvalidTaskID}},
+		{name: taskNotFoundTest, tid: "1", want: want{err: domain.ErrTaskNotFound}},
+		{name: testkit.FailureTest, tid: "1", want: want{err: testkit.ErrDummy}},
+		{name: testkit.SuccessTest, tid: "1", want: want{history: history}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.GetHistoryFunc = func(ctx context.Context, tid uint64) ([]domain.Change, error) {
+				switch test.name {
+				case taskNotFoundTest:
+					return nil, domain.ErrTaskNotFound
+				case testkit.FailureTest:
+					return nil, testkit.ErrDummy
+				}
+
+				return history, nil
+			}
+
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.TaskHistory(t.Context(), test.tid)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("TaskHistory() error = %v, want = %v", err, test.want.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want.history) {
+				t.Errorf("TaskHistory() got = %v, want = %v", got, test.want.history)
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesHistoryActor(t *testing.T) {
+	t.Parallel()
+
+	stor := new(storage.Mock)
+
+	stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+		history := []domain.Change{{Actor: "bob", Field: domain.FieldTask, New: domain.TaskAdded}}
+
+		return &domain.Task{ID: tid, Description: "old", Status: domain.StatusTodo, History: history}, nil
+	}
+	stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
+		return nil
+	}
+
+	use := usecases.New(usecases.Config{Storage: stor, Actor: "alice"})
+	got, _ := use.UpdateTask(t.Context(), "1", "new")
+
+	want := []domain.Change{
+		{Actor: "bob", Field: domain.FieldTask, New: domain.TaskAdded},
+		{At: got.UpdatedAt, Actor: "alice", Field: "description", Old: "old", New: "new"},
+	}
+
+	if !reflect.DeepEqual(got.History, want) {
+		t.Errorf("UpdateTask() got = %v, want = %v", got.History, want)
+	}
+}
@@ -0,0 +1,335 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+// revise copies the task states into the revision, so the later changes do not leak there.
+func revise(before *domain.Task, after *domain.Task) domain.Revision {
+	var revision domain.Revision
+
+	if before != nil {
+		copied := snapshot(before)
+		revision.Before = &copied
+	}
+
+	if after != nil {
+		copied := snapshot(after)
+		revision.After = &copied
+	}
+
+	return revision
+}
+
+// writeJournal appends the operation of the command to the journal, if there is one.
+func (use *UseCases) writeJournal(ctx context.Context, command string, revisions ...domain.Revision) error {
+	if use.journal == nil {
+		return nil
+	}
+
+	op := &domain.Operation{
+		ID:        0,
+		Command:   command,
+		At:        time.Now(),
+		Actor:     use.actor,
+		Revisions: revisions,
+		Undone:    false,
+	}
+
+	_, err := use.journal.AppendOperation(ctx, op)
+
+	return err
+}
+
+// undoable lists the operations to undo from the latest one, the empty count stands for all of them.
+func (use *UseCases) undoable(ctx context.Context, count string) ([]*domain.Operation, error) {
+	limit := 0
+
+	if count != "" {
+		number, err := strconv.Atoi(count)
+		if err != nil || number < 1 {
+			return nil, domain.ErrInvalidCount
+		}
+
+		limit = number
+	}
+
+	if use.journal == nil {
+		return nil, domain.ErrNothingToUndo
+	}
+
+	ops, err := use.journal.ListOperations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ops = slices.DeleteFunc(ops, func(op *domain.Operation) bool { return op.Undone })
+	slices.Reverse(ops)
+
+	if len(ops) == 0 {
+		return nil, domain.ErrNothingToUndo
+	}
+
+	if limit > 0 && limit < len(ops) {
+		ops = ops[:limit]
+	}
+
+	return ops, nil
+}
+
+// UndoList shows what Undo with the same count would revert, the empty count lists all operations.
+func (use *UseCases) UndoList(ctx context.Context, count string) ([]*domain.Operation, error) {
+	const where = "UndoList"
+
+	ops, err := use.undoable(ctx, count)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return ops, nil
+}
+
+// Undo reverts the last operations one by one from the latest, the empty count stands for one.
+// Nothing is reverted if any operation has tasks changed since, so no later change is lost.
+func (use *UseCases) Undo(ctx context.Context, count string) ([]*domain.Operation, error) {
+	const where = "Undo"
+
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
+	if count == "" {
+		count = "1"
+	}
+
+	ops, err := use.undoable(ctx, count)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	// every operation is checked against the states the newer ones bring back, before any of them is reverted
+	planned := make(map[uint64]*domain.Task)
+
+	for _, op := range ops {
+		_, err = use.check(ctx, op, true, planned)
+		if err != nil {
+			return nil, fmt.Errorf("%s error: operation %d: %w", where, op.ID, err)
+		}
+	}
+
+	for _, op := range ops {
+		err = use.replay(ctx, op, true)
+		if err != nil {
+			return nil, fmt.Errorf("%s error: operation %d: %w", where, op.ID, err)
+		}
+	}
+
+	return ops, nil
+}
+
+// Redo makes again the operation undone the last.
+func (use *UseCases) Redo(ctx context.Context) (*domain.Operation, error) {
+	const where = "Redo"
+
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
+	if use.journal == nil {
+		return nil, fmt.Errorf("%s error: %w", where, domain.ErrNothingToRedo)
+	}
+
+	ops, err := use.journal.ListOperations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	// undone operations are always the tail of the journal
+	idx := slices.IndexFunc(ops, func(op *domain.Operation) bool { return op.Undone })
+	if idx < 0 {
+		return nil, fmt.Errorf("%s error: %w", where, domain.ErrNothingToRedo)
+	}
+
+	err = use.replay(ctx, ops[idx], false)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return ops[idx], nil
+}
+
+// replay moves the revised tasks to the states before the operation or after it,
+// nothing is changed if any of them is changed since.
+func (use *UseCases) replay(ctx context.Context, op *domain.Operation, undo bool) error {
+	currents, err := use.check(ctx, op, undo, make(map[uint64]*domain.Task))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	// the later revisions could be built on the earlier ones, so undo goes backwards
+	for step := range op.Revisions {
+		idx := step
+		if undo {
+			idx = len(op.Revisions) - 1 - step
+		}
+
+		revision := &op.Revisions[idx]
+
+		target := &revision.After
+		if undo {
+			target = &revision.Before
+		}
+
+		written, err := use.restore(ctx, revision.TaskID(), currents[idx], *target, now)
+		if err != nil {
+			return err
+		}
+
+		*target = written
+	}
+
+	op.Undone = undo
+
+	return use.journal.UpdateOperation(ctx, op)
+}
+
+// check tells the current states of the revised tasks, if they are the ones the operation left or started from.
+// The planned states of the tasks go before the stored ones and get the states the operation brings back.
+func (use *UseCases) check(
+	ctx context.Context, op *domain.Operation, undo bool, planned map[uint64]*domain.Task,
+) ([]*domain.Task, error) {
+	currents := make([]*domain.Task, len(op.Revisions))
+
+	for idx, revision := range op.Revisions {
+		expected := revision.Before
+		if undo {
+			expected = revision.After
+		}
+
+		current, ok := planned[revision.TaskID()]
+		if ok {
+			// the newer operation brings this state back, the task it deletes waits in the trash
+			if !sameState(current, expected) {
+				return nil, domain.ErrJournalConflict
+			}
+
+			currents[idx] = current
+
+			continue
+		}
+
+		current, err := use.storage.GetByID(ctx, revision.TaskID())
+		if err != nil && !errors.Is(err, domain.ErrTaskNotFound) {
+			return nil, err
+		}
+
+		if !sameState(current, expected) {
+			return nil, domain.ErrJournalConflict
+		}
+
+		// the task missing is the one in the trash, the purged task is gone for good
+		if current == nil {
+			_, err = use.storage.GetTrashed(ctx, revision.TaskID())
+		}
+
+		switch {
+		case errors.Is(err, domain.ErrTaskNotFound):
+			return nil, domain.ErrJournalConflict
+		case err != nil:
+			return nil, err
+		}
+
+		currents[idx] = current
+	}
+
+	for step := range op.Revisions {
+		idx := step
+		if undo {
+			idx = len(op.Revisions) - 1 - step
+		}
+
+		target := op.Revisions[idx].After
+		if undo {
+			target = op.Revisions[idx].Before
+		}
+
+		planned[op.Revisions[idx].TaskID()] = target
+	}
+
+	return currents, nil
+}
+
+// sameState tells if the task is not changed since it was journaled, nil is the task not existing.
+// Every change moves UpdatedAt, except the replay that brings the journaled state back.
+func sameState(current *domain.Task, expected *domain.Task) bool {
+	if current == nil || expected == nil {
+		return current == expected
+	}
+
+	return current.UpdatedAt.Equal(expected.UpdatedAt)
+}
+
+// restore writes the task state, nil target deletes the task, it gives the state written.
+func (use *UseCases) restore(
+	ctx context.Context, taskID uint64, current *domain.Task, target *domain.Task, now time.Time,
+) (*domain.Task, error) {
+	if target == nil {
+		use.discard(current, now)
+
+		return nil, use.storage.DeleteTask(ctx, current)
+	}
+
+	// the task keeps the time of the target state, so the neighbour operations still match it
+	task := snapshot(target)
+
+	before := domain.Task{}
+
+	if current != nil {
+		before = *current
+		task.History = current.History
+	} else {
+		history, err := use.storage.GetHistory(ctx, taskID)
+		if err != nil && !errors.Is(err, domain.ErrTaskNotFound) {
+			return nil, err
+		}
+
+		task.History = append(history, use.change(domain.FieldTask, "", domain.TaskRestored, now))
+	}
+
+	use.record(&task, before)
+
+	// the missing task waits in the trash, the archived one goes back to the archive
+	var err error
+
+	switch {
+	case current == nil:
+		err = use.storage.RestoreTasks(ctx, []*domain.Task{&task})
+	case task.IsArchived():
+		err = use.storage.ArchiveTasks(ctx, []*domain.Task{&task})
+	default:
+		err = use.storage.UpdateTask(ctx, &task)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	written := snapshot(&task)
+
+	return &written, nil
+}
@@ -0,0 +1,436 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+	"github.com/therenotomorrow/tasker/internal/storage"
+	"github.com/therenotomorrow/tasker/internal/usecases"
+	"github.com/therenotomorrow/tasker/pkg/testkit"
+)
+
+// journaled gives the mock keeping the tasks, the trash, the archive and the journal in memory
+// like the storage does.
+func journaled() *storage.Mock {
+	var (
+		tasks   = make(map[uint64]*domain.Task)
+		trash   = make(map[uint64]*domain.Task)
+		archive = make(map[uint64]*domain.Task)
+		journal = make([]*domain.Operation, 0)
+		lastID  = uint64(0)
+	)
+
+	clone := func(task *domain.Task) *domain.Task {
+		copied := *task
+		copied.History = slices.Clone(task.History)
+
+		return &copied
+	}
+
+	stor := new(storage.Mock)
+
+	stor.SaveTaskFunc = func(ctx context.Context, task *domain.Task) (*domain.Task, error) {
+		task.ID = uint64(len(tasks) + len(trash) + 1)
+		tasks[task.ID] = clone(task)
+
+		return task, nil
+	}
+	stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
+		if _, ok := trash[task.ID]; ok {
+			return domain.ErrTaskNotFound
+		}
+
+		task.ArchivedAt = time.Time{}
+		tasks[task.ID] = clone(task)
+		delete(archive, task.ID)
+
+		return nil
+	}
+	stor.ArchiveTasksFunc = func(ctx context.Context, archived []*domain.Task) error {
+		for _, task := range archived {
+			archive[task.ID] = clone(task)
+			delete(tasks, task.ID)
+		}
+
+		return nil
+	}
+	stor.ListAllFunc = func(ctx context.Context) ([]*domain.Task, error) {
+		list := make([]*domain.Task, 0, len(tasks))
+		for _, task := range tasks {
+			list = append(list, clone(task))
+		}
+
+		return list, nil
+	}
+	stor.DeleteTaskFunc = func(ctx context.Context, task *domain.Task) error {
+		trash[task.ID] = clone(task)
+		delete(tasks, task.ID)
+
+		return nil
+	}
+	stor.DeleteTasksFunc = func(ctx context.Context, deleted []*domain.Task) error {
+		for _, task := range deleted {
+			trash[task.ID] = clone(task)
+			delete(tasks, task.ID)
+		}
+
+		return nil
+	}
+	stor.GetTrashedFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+		task, ok := trash[tid]
+		if !ok {
+			return nil, domain.ErrTaskNotFound
+		}
+
+		return clone(task), nil
+	}
+	stor.ListTrashFunc = func(ctx context.Context) ([]*domain.Task, error) {
+		list := make([]*domain.Task, 0, len(trash))
+		for _, task := range trash {
+			list = append(list, clone(task))
+		}
+
+		return list, nil
+	}
+	stor.RestoreTasksFunc = func(ctx context.Context, restored []*domain.Task) error {
+		for _, task := range restored {
+			tasks[task.ID] = clone(task)
+			delete(trash, task.ID)
+		}
+
+		return nil
+	}
+	stor.PurgeTasksFunc = func(ctx context.Context, purged []*domain.Task) error {
+		for _, task := range purged {
+			delete(trash, task.ID)
+		}
+
+		return nil
+	}
+	stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+		task, ok := tasks[tid]
+		if !ok {
+			task, ok = archive[tid]
+		}
+
+		if !ok {
+			return nil, domain.ErrTaskNotFound
+		}
+
+		return clone(task), nil
+	}
+	stor.GetHistoryFunc = func(ctx context.Context, tid uint64) ([]domain.Change, error) {
+		task, ok := trash[tid]
+		if !ok {
+			return nil, domain.ErrTaskNotFound
+		}
+
+		return slices.Clone(task.History), nil
+	}
+	stor.ListChildrenFunc = func(ctx context.Context, parentID uint64) ([]*domain.Task, error) {
+		list := make([]*domain.Task, 0)
+
+		for _, task := range tasks {
+			if task.ParentID == parentID {
+				list = append(list, clone(task))
+			}
+		}
+
+		return list, nil
+	}
+	stor.AppendOperationFunc = func(ctx context.Context, op *domain.Operation) (*domain.Operation, error) {
+		lastID++
+		op.ID = lastID
+		journal = slices.DeleteFunc(journal, func(op *domain.Operation) bool { return op.Undone })
+		journal = append(journal, op)
+
+		return op, nil
+	}
+	stor.UpdateOperationFunc = func(ctx context.Context, op *domain.Operation) error {
+		return nil
+	}
+	stor.ListOperationsFunc = func(ctx context.Context) ([]*domain.Operation, error) {
+		return slices.Clone(journal), nil
+	}
+
+	return stor
+}
+
+func TestUnitUseCasesUndoRedo(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx  = t.Context()
+		stor = journaled()
+		use  = usecases.New(usecases.Config{Storage: stor, Journal: stor})
+	)
+
+	task, _ := use.AddTask(ctx, usecases.AddParams{Description: "old"})
+	_, _ = use.UpdateTask(ctx, "1", "new")
+
+	ops, err := use.Undo(ctx, "")
+	if err != nil || len(ops) != 1 || ops[0].Command != "UpdateTask" {
+		t.Fatalf("Undo() got = %v, error = %v, want = %v", ops, err, "UpdateTask")
+	}
+
+	got, _ := stor.GetByID(ctx, task.ID)
+	if got.Description != "old" {
+		t.Errorf("Undo() got = %v, want = %v", got.Description, "old")
+	}
+
+	op, err := use.Redo(ctx)
+	if err != nil || op.Command != "UpdateTask" {
+		t.Fatalf("Redo() got = %v, error = %v, want = %v", op, err, "UpdateTask")
+	}
+
+	got, _ = stor.GetByID(ctx, task.ID)
+	if got.Description != "new" {
+		t.Errorf("Redo() got = %v, want = %v", got.Description, "new")
+	}
+
+	_, err = use.Redo(ctx)
+	if !errors.Is(err, domain.ErrNothingToRedo) {
+		t.Errorf("Redo() error = %v, want = %v", err, domain.ErrNothingToRedo)
+	}
+
+	_ = use.DeleteTask(ctx, "1", usecases.DeleteOptions{})
+
+	ops, _ = use.Undo(ctx, "2")
+	if len(ops) != 2 || ops[0].Command != "DeleteTask" || ops[1].Command != "UpdateTask" {
+		t.Fatalf("Undo() got = %v, want = %v", ops, []string{"DeleteTask", "UpdateTask"})
+	}
+
+	got, err = stor.GetByID(ctx, task.ID)
+	if err != nil || got.Description != "old" {
+		t.Fatalf("Undo() got = %v, error = %v, want = %v", got, err, "old")
+	}
+
+	last := got.History[len(got.History)-1]
+	if last.Field != "description" || last.New != "old" {
+		t.Errorf("Undo() got = %v, want = %v", last, "description back to old")
+	}
+
+	ops, _ = use.UndoList(ctx, "")
+	if len(ops) != 1 || ops[0].Command != "AddTask" {
+		t.Errorf("UndoList() got = %v, want = %v", ops, "AddTask")
+	}
+
+	_, _ = use.Undo(ctx, "")
+
+	_, err = stor.GetByID(ctx, task.ID)
+	if !errors.Is(err, domain.ErrTaskNotFound) {
+		t.Errorf("Undo() error = %v, want = %v", err, domain.ErrTaskNotFound)
+	}
+
+	_, err = use.Undo(ctx, "")
+	if !errors.Is(err, domain.ErrNothingToUndo) {
+		t.Errorf("Undo() error = %v, want = %v", err, domain.ErrNothingToUndo)
+	}
+}
+
+func TestUnitUseCasesUndoArchive(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx  = t.Context()
+		stor = journaled()
+		use  = usecases.New(usecases.Config{Storage: stor, Journal: stor})
+	)
+
+	_, _ = use.AddTask(ctx, usecases.AddParams{Description: "shipped"})
+	_, _ = use.MarkTask(ctx, "1", "done", usecases.MarkOptions{})
+
+	archived, err := use.ArchiveTasks(ctx, "")
+	if err != nil || len(archived) != 1 {
+		t.Fatalf("ArchiveTasks() got = %v, error = %v, want = %v", archived, err, "one task")
+	}
+
+	ops, err := use.Undo(ctx, "")
+	if err != nil || len(ops) != 1 || ops[0].Command != "ArchiveTasks" {
+		t.Fatalf("Undo() got = %v, error = %v, want = %v", ops, err, "ArchiveTasks")
+	}
+
+	got, _ := stor.GetByID(ctx, 1)
+	if got.IsArchived() || got.Status != domain.StatusDone {
+		t.Errorf("Undo() got = %v, want = %v", got, "done task back from the archive")
+	}
+
+	_, err = use.Redo(ctx)
+	if err != nil {
+		t.Fatalf("Redo() error = %v, want = %v", err, nil)
+	}
+
+	got, _ = stor.GetByID(ctx, 1)
+	if !got.IsArchived() {
+		t.Errorf("Redo() got = %v, want = %v", got.ArchivedAt, "archived task")
+	}
+
+	list, _ := stor.ListAll(ctx)
+	if len(list) != 0 {
+		t.Errorf("Redo() got = %v, want = %v", list, "empty hot list")
+	}
+}
+
+func TestUnitUseCasesUndoConflict(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx  = t.Context()
+		stor = journaled()
+		use  = usecases.New(usecases.Config{Storage: stor, Journal: stor})
+	)
+
+	_, _ = use.AddTask(ctx, usecases.AddParams{Description: "journaled"})
+
+	// the change is made aside of the journal
+	task, _ := stor.GetByID(ctx, 1)
+	task.Description = "changed"
+	task.UpdatedAt = task.UpdatedAt.Add(1)
+	_ = stor.UpdateTask(ctx, task)
+
+	_, err := use.Undo(ctx, "")
+	if !errors.Is(err, domain.ErrJournalConflict) {
+		t.Errorf("Undo() error = %v, want = %v", err, domain.ErrJournalConflict)
+	}
+
+	got, _ := stor.GetByID(ctx, 1)
+	if got.Description != "changed" {
+		t.Errorf("Undo() got = %v, want = %v", got.Description, "changed")
+	}
+}
+
+func TestUnitUseCasesUndoConflictPartway(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx  = t.Context()
+		stor = journaled()
+		use  = usecases.New(usecases.Config{Storage: stor, Journal: stor})
+	)
+
+	_, _ = use.AddTask(ctx, usecases.AddParams{Description: "first"})
+	_, _ = use.UpdateTask(ctx, "1", "journaled")
+
+	// the change is made aside of the journal, so the second operation conflicts
+	task, _ := stor.GetByID(ctx, 1)
+	task.Description = "changed"
+	task.UpdatedAt = task.UpdatedAt.Add(1)
+	_ = stor.UpdateTask(ctx, task)
+
+	_, _ = use.AddTask(ctx, usecases.AddParams{Description: "second"})
+
+	_, err := use.Undo(ctx, "3")
+	if !errors.Is(err, domain.ErrJournalConflict) {
+		t.Errorf("Undo() error = %v, want = %v", err, domain.ErrJournalConflict)
+	}
+
+	// nothing is reverted, even the newest operation without conflict
+	_, err = stor.GetByID(ctx, 2)
+	if err != nil {
+		t.Errorf("Undo() error = %v, want = %v", err, nil)
+	}
+
+	got, _ := stor.GetByID(ctx, 1)
+	if got.Description != "changed" {
+		t.Errorf("Undo() got = %v, want = %v", got.Description, "changed")
+	}
+
+	ops, _ := use.UndoList(ctx, "")
+	if len(ops) != 3 {
+		t.Errorf("UndoList() got = %v, want = %v", len(ops), 3)
+	}
+}
+
+func TestUnitUseCasesUndo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		count   string
+		journal bool
+		want    error
+	}{
+		{name: "invalid count", count: "invalid", journal: true, want: domain.ErrInvalidCount},
+		{name: "zero count", count: "0", journal: true, want: domain.ErrInvalidCount},
+		{name: "no journal", count: "", journal: false, want: domain.ErrNothingToUndo},
+		{name: "empty journal", count: "", journal: true, want: domain.ErrNothingToUndo},
+		{name: testkit.FailureTest, count: "", journal: true, want: testkit.ErrDummy},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.ListOperationsFunc = func(ctx context.Context) ([]*domain.Operation, error) {
+				if test.name == testkit.FailureTest {
+					return nil, testkit.ErrDummy
+				}
+
+				return []*domain.Operation{{ID: 1, Command: "AddTask", Undone: true}}, nil
+			}
+
+			config := usecases.Config{Storage: stor}
+			if test.journal {
+				config.Journal = stor
+			}
+
+			use := usecases.New(config)
+
+			_, err := use.Undo(t.Context(), test.count)
+			if !errors.Is(err, test.want) {
+				t.Errorf("Undo() error = %v, want = %v", err, test.want)
+			}
+
+			_, err = use.UndoList(t.Context(), test.count)
+			if !errors.Is(err, test.want) {
+				t.Errorf("UndoList() error = %v, want = %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesRedo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		journal bool
+		want    error
+	}{
+		{name: "no journal", journal: false, want: domain.ErrNothingToRedo},
+		{name: "nothing undone", journal: true, want: domain.ErrNothingToRedo},
+		{name: testkit.FailureTest, journal: true, want: testkit.ErrDummy},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.ListOperationsFunc = func(ctx context.Context) ([]*domain.Operation, error) {
+				if test.name == testkit.FailureTest {
+					return nil, testkit.ErrDummy
+				}
+
+				return []*domain.Operation{{ID: 1, Command: "AddTask"}}, nil
+			}
+
+			config := usecases.Config{Storage: stor}
+			if test.journal {
+				config.Journal = stor
+			}
+
+			_, err := usecases.New(config).Redo(t.Context())
+			if !errors.Is(err, test.want) {
+				t.Errorf("Redo() error = %v, want = %v", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+type RecurOptions struct {
+	// Stop removes the recurrence rule, the rule parameter is ignored.
+	Stop bool
+}
+
+func (use *UseCases) RecurTask(ctx context.Context, tid string, rule string, opts RecurOptions) (*domain.Task, error) {
+	const where = "RecurTask"
+
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
+	taskID, err := use.validateTaskID(tid)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	var recurrence domain.Recurrence
+	if !opts.Stop {
+		recurrence, err = use.validateRecurrence(rule)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	task, err := use.storage.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	before := snapshot(task)
+
+	task.Recurrence = recurrence
+	task.UpdatedAt = time.Now()
+	use.record(task, before)
+
+	err = use.storage.UpdateTask(ctx, task)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	err = use.writeJournal(ctx, where, revise(&before, task))
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return task, nil
+}
+
+// nextDue gives the first occurrence after now, the task without due date recurs
+// from the end of today, so "daily" is due by the end of tomorrow.
+func nextDue(task *domain.Task, now time.Time) time.Time {
+	next := endOfDay(now)
+	if task.HasDue() {
+		next = task.DueAt
+	}
+
+	// the missed occurrences are skipped, it is a chore not a debt
+	for {
+		next = task.Recurrence.Next(next)
+		if next.IsZero() || next.After(now) {
+			return next
+		}
+	}
+}
+
+// spawnNext saves the next instance of the recurring task, all instances are
+// linked to the very first one.
+func (use *UseCases) spawnNext(ctx context.Context, task *domain.Task) (*domain.Task, error) {
+	recurOf := task.RecurOf
+	if recurOf == 0 {
+		recurOf = task.ID
+	}
+
+	now := time.Now()
+	next := &domain.Task{
+		ID:          0,
+		Description: task.Description,
+		Status:      use.workflow.Initial(),
+		Priority:    task.Priority,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		DueAt:       nextDue(task, now),
+		DeletedAt:   time.Time{},
+		ArchivedAt:  time.Time{},
+		Tags:        slices.Clone(task.Tags),
+		Project:     task.Project,
+		ParentID:    task.ParentID,
+		BlockedBy:   nil,
+		Recurrence:  task.Recurrence,
+		RecurOf:     recurOf,
+		Transition:  nil,
+		History:     []domain.Change{use.change(domain.FieldTask, "", domain.TaskAdded, now)},
+	}
+
+	use.record(next, domain.Task{})
+
+	return use.storage.SaveTask(ctx, next)
+}
@@ -0,0 +1,268 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+	"github.com/therenotomorrow/tasker/internal/storage"
+	"github.com/therenotomorrow/tasker/internal/usecases"
+	"github.com/therenotomorrow/tasker/pkg/testkit"
+)
+
+func TestUnitUseCasesRecurTask(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		tid  string
+		rule string
+		opts usecases.RecurOptions
+	}
+
+	type want struct {
+		task *domain.Task
+		err  error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "invalid taskID",
+			args: args{tid: "invalid", rule: "daily"},
+			want: want{err: domain.ErrInvalidTaskID},
+		},
+		{
+			name: "invalid rule",
+			args: args{tid: "1", rule: "sometimes"},
+			want: want{err: domain.ErrInvalidRecurrence},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{tid: "1", rule: "daily"},
+			want: want{err: domain.ErrTaskNotFound},
+		},
+		{
+			name: testkit.FailureTest,
+			args: args{tid: "1", rule: "daily"},
+			want: want{err: testkit.ErrDummy},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{tid: "1", rule: "Weekly:thu,mon"},
+			want: want{task: &domain.Task{
+				ID:         1,
+				Status:     domain.StatusTodo,
+				UpdatedAt:  time.Now().Truncate(time.Minute),
+				Recurrence: "weekly:mon,thu",
+				History: []domain.Change{
+					{At: time.Now().Truncate(time.Minute), Field: "recurrence", Old: "daily", New: "weekly:mon,thu"},
+				},
+			}},
+		},
+		{
+			name: "stop",
+			args: args{tid: "1", rule: "", opts: usecases.RecurOptions{Stop: true}},
+			want: want{task: &domain.Task{
+				ID:        1,
+				Status:    domain.StatusTodo,
+				UpdatedAt: time.Now().Truncate(time.Minute),
+				History:   []domain.Change{{At: time.Now().Truncate(time.Minute), Field: "recurrence", Old: "daily"}},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+				if test.name == taskNotFoundTest {
+					return nil, domain.ErrTaskNotFound
+				}
+
+				return &domain.Task{ID: 1, Status: domain.StatusTodo, Recurrence: "daily"}, nil
+			}
+			stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
+				if test.name == testkit.FailureTest {
+					return testkit.ErrDummy
+				}
+
+				return nil
+			}
+
+			ctx := t.Context()
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.RecurTask(ctx, test.args.tid, test.args.rule, test.args.opts)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("RecurTask() error = %v, want = %v", err, test.want.err)
+			}
+
+			if got != nil {
+				got.UpdatedAt = got.UpdatedAt.Truncate(time.Minute)
+				truncateHistory(got)
+			}
+
+			if !reflect.DeepEqual(got, test.want.task) {
+				t.Errorf("RecurTask() got = %v, want = %v", got, test.want.task)
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesMarkTaskRecurring(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, time.Local)
+	// the weekly task missed the last week, so it is due in a few days
+	missed := today.AddDate(0, 0, -10)
+
+	type want struct {
+		spawned *domain.Task
+		err     error
+	}
+
+	tests := []struct {
+		name string
+		task *domain.Task
+		mark string
+		want want
+	}{
+		{
+			name: "without due",
+			task: &domain.Task{ID: 3, Description: "standup", Status: domain.StatusTodo, Recurrence: "daily"},
+			mark: "done",
+			want: want{spawned: &domain.Task{
+				ID:          4,
+				Description: "standup",
+				Status:      domain.StatusTodo,
+				DueAt:       today.AddDate(0, 0, 1),
+				Recurrence:  "daily",
+				RecurOf:     3,
+				History: []domain.Change{
+					{Field: "task", New: "added"},
+					{Field: "description", New: "standup"},
+					{Field: "status", New: "todo"},
+					{Field: "due", New: today.AddDate(0, 0, 1).Format(time.DateTime)},
+					{Field: "recurrence", New: "daily"},
+				},
+			}},
+		},
+		{
+			name: "missed due",
+			task: &domain.Task{
+				ID:          5,
+				Description: "report",
+				Status:      domain.StatusProgress,
+				Priority:    domain.PriorityHigh,
+				DueAt:       missed,
+				Tags:        []string{"team"},
+				Project:     "office",
+				BlockedBy:   []uint64{2},
+				Recurrence:  "weekly",
+				RecurOf:     3,
+			},
+			mark: "done",
+			want: want{spawned: &domain.Task{
+				ID:          4,
+				Description: "report",
+				Status:      domain.StatusTodo,
+				Priority:    domain.PriorityHigh,
+				DueAt:       missed.AddDate(0, 0, 14),
+				Tags:        []string{"team"},
+				Project:     "office",
+				Recurrence:  "weekly",
+				RecurOf:     3,
+				History: []domain.Change{
+					{Field: "task", New: "added"},
+					{Field: "description", New: "report"},
+					{Field: "status", New: "todo"},
+					{Field: "priority", New: "high"},
+					{Field: "due", New: missed.AddDate(0, 0, 14).Format(time.DateTime)},
+					{Field: "tags", New: "team"},
+					{Field: "project", New: "office"},
+					{Field: "recurrence", New: "weekly"},
+				},
+			}},
+		},
+		{
+			name: "not done",
+			task: &domain.Task{ID: 3, Status: domain.StatusTodo, Recurrence: "daily"},
+			mark: "progress",
+			want: want{spawned: nil},
+		},
+		{
+			name: testkit.FailureTest,
+			task: &domain.Task{ID: 3, Status: domain.StatusTodo, Recurrence: "daily"},
+			mark: "done",
+			want: want{err: testkit.ErrDummy},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var spawned *domain.Task
+
+			stor := new(storage.Mock)
+
+			stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+				if tid == test.task.ID {
+					task := *test.task
+
+					return &task, nil
+				}
+
+				return &domain.Task{ID: tid, Status: domain.StatusDone}, nil
+			}
+			stor.ListChildrenFunc = func(ctx context.Context, parentID uint64) ([]*domain.Task, error) {
+				return make([]*domain.Task, 0), nil
+			}
+			stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
+				return nil
+			}
+			stor.SaveTaskFunc = func(ctx context.Context, task *domain.Task) (*domain.Task, error) {
+				if test.name == testkit.FailureTest {
+					return nil, testkit.ErrDummy
+				}
+
+				task.ID = 4
+				spawned = task
+
+				return task, nil
+			}
+
+			ctx := t.Context()
+			use := usecases.New(usecases.Config{Storage: stor})
+			_, err := use.MarkTask(ctx, strconv.FormatUint(test.task.ID, 10), test.mark, usecases.MarkOptions{})
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("MarkTask() error = %v, want = %v", err, test.want.err)
+			}
+
+			if spawned != nil {
+				spawned.CreatedAt = time.Time{}
+				spawned.UpdatedAt = time.Time{}
+
+				for idx := range spawned.History {
+					spawned.History[idx].At = time.Time{}
+				}
+			}
+
+			if !reflect.DeepEqual(spawned, test.want.spawned) {
+				t.Errorf("MarkTask() got = %v, want = %v", spawned, test.want.spawned)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+type SearchParams struct {
+	Query string
+	Scope Scope
+}
+
+// SearchResult is the tasks found, the most relevant first, with the query to highlight the matches.
+type SearchResult struct {
+	Query domain.SearchQuery
+	Tasks []*domain.Task
+}
+
+func (use *UseCases) SearchTasks(ctx context.Context, params SearchParams) (*SearchResult, error) {
+	const where = "SearchTasks"
+
+	query, err := domain.NewSearchQuery(params.Query)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	tasks, err := use.scoped(ctx, params.Scope, func() ([]*domain.Task, error) {
+		return use.storage.Search(ctx, query)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	// the archive is not searched by the storage
+	tasks = slices.DeleteFunc(tasks, func(task *domain.Task) bool { return !query.Match(*task) })
+
+	if len(tasks) == 0 {
+		return nil, domain.ErrEmptyTasks
+	}
+
+	scores := make(map[uint64]int, len(tasks))
+	for _, task := range tasks {
+		scores[task.ID] = query.Score(*task)
+	}
+
+	slices.SortFunc(tasks, func(a, b *domain.Task) int {
+		return cmp.Or(cmp.Compare(scores[b.ID], scores[a.ID]), cmp.Compare(a.ID, b.ID))
+	})
+
+	return &SearchResult{Query: query, Tasks: tasks}, nil
+}
@@ -0,0 +1,92 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+	"github.com/therenotomorrow/tasker/internal/storage"
+	"github.com/therenotomorrow/tasker/internal/usecases"
+	"github.com/therenotomorrow/tasker/pkg/testkit"
+)
+
+func TestUnitUseCasesSearchTasks(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		ids []uint64
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		params usecases.SearchParams
+		want   want
+	}{
+		{name: "invalid query", params: usecases.SearchParams{Query: `"open`}, want: want{err: domain.ErrInvalidQuery}},
+		{name: testkit.FailureTest, params: usecases.SearchParams{Query: "api"}, want: want{err: testkit.ErrDummy}},
+		{name: "nothing found", params: usecases.SearchParams{Query: "prod"}, want: want{err: domain.ErrEmptyTasks}},
+		{name: "ranked", params: usecases.SearchParams{Query: "api"}, want: want{ids: []uint64{3, 1, 2}}},
+		{name: "negated", params: usecases.SearchParams{Query: "api -rapid"}, want: want{ids: []uint64{3, 1}}},
+		{
+			name:   "archived",
+			params: usecases.SearchParams{Query: "api", Scope: usecases.ScopeArchived},
+			want:   want{ids: []uint64{4}},
+		},
+		{
+			name:   "all",
+			params: usecases.SearchParams{Query: "api", Scope: usecases.ScopeAll},
+			want:   want{ids: []uint64{3, 4, 1, 2}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.SearchFunc = func(ctx context.Context, query domain.SearchQuery) ([]*domain.Task, error) {
+				if test.name == testkit.FailureTest {
+					return nil, testkit.ErrDummy
+				}
+
+				tasks := []*domain.Task{
+					{ID: 1, Description: "apis"},
+					{ID: 2, Description: "rapid"},
+					{ID: 3, Description: "the api and the api"},
+				}
+
+				matched := make([]*domain.Task, 0)
+
+				for _, task := range tasks {
+					if query.Match(*task) {
+						matched = append(matched, task)
+					}
+				}
+
+				return matched, nil
+			}
+			stor.ListArchivedFunc = func(ctx context.Context) ([]*domain.Task, error) {
+				return []*domain.Task{{ID: 4, Description: "api"}, {ID: 5, Description: "docs"}}, nil
+			}
+
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.SearchTasks(t.Context(), test.params)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("SearchTasks() error = %v, want = %v", err, test.want.err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if ids := taskIDs(got.Tasks); !reflect.DeepEqual(ids, test.want.ids) {
+				t.Errorf("SearchTasks() got = %v, want = %v", ids, test.want.ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+func (use *UseCases) isOpen(task *domain.Task) bool {
+	return !use.workflow.IsTerminal(task.Status)
+}
+
+// checkSubtasks refuses to close the task while any of its subtasks is open.
+func (use *UseCases) checkSubtasks(ctx context.Context, task *domain.Task) error {
+	children, err := use.storage.ListChildren(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+
+	if slices.ContainsFunc(children, use.isOpen) {
+		return domain.ErrOpenSubtasks
+	}
+
+	return nil
+}
+
+// closeParents completes parents up the tree while all their subtasks are closed,
+// it gives the revisions of the closed parents.
+func (use *UseCases) closeParents(ctx context.Context, task *domain.Task) ([]domain.Revision, error) {
+	revisions := make([]domain.Revision, 0)
+
+	for task.HasParent() {
+		parent, err := use.storage.GetByID(ctx, task.ParentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !use.isOpen(parent) {
+			return revisions, nil
+		}
+
+		children, err := use.storage.ListChildren(ctx, parent.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.ContainsFunc(children, use.isOpen) {
+			return revisions, nil
+		}
+
+		before := snapshot(parent)
+
+		parent.Status = use.workflow.Completed()
+		parent.UpdatedAt = time.Now()
+		use.record(parent, before)
+
+		err = use.storage.UpdateTask(ctx, parent)
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, revise(&before, parent))
+		task = parent
+	}
+
+	return revisions, nil
+}
+
+// subtree collects the task with all its subtasks, parents go first.
+func (use *UseCases) subtree(ctx context.Context, task *domain.Task) ([]*domain.Task, error) {
+	tasks := []*domain.Task{task}
+	seen := map[uint64]bool{task.ID: true}
+
+	for idx := 0; idx < len(tasks); idx++ {
+		children, err := use.storage.ListChildren(ctx, tasks[idx].ID)
+		if err != nil {
+			return nil, err
+		}
+
+		// broken files could have loops, so every task is taken once
+		for _, child := range children {
+			if !seen[child.ID] {
+				seen[child.ID] = true
+				tasks = append(tasks, child)
+			}
+		}
+	}
+
+	return tasks, nil
+}
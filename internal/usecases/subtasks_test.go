@@ -0,0 +1 @@
+package usecases_test
@@ -0,0 +1,52 @@
+package usecases
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+// ExtractTags takes the inline "+tag" words out of the description, words that
+// are not valid tags like "+1" stay in the description as is, so does the spacing.
+func ExtractTags(description string) (string, []string) {
+	var text strings.Builder
+
+	tags := make([]string, 0)
+	rest := description
+
+	for rest != "" {
+		word := strings.TrimLeftFunc(rest, unicode.IsSpace)
+		space := rest[:len(rest)-len(word)]
+
+		end := strings.IndexFunc(word, unicode.IsSpace)
+		if end < 0 {
+			end = len(word)
+		}
+
+		word, rest = word[:end], word[end:]
+
+		tag, err := domain.NewTag(word)
+		if !strings.HasPrefix(word, "+") || err != nil {
+			text.WriteString(space + word)
+
+			continue
+		}
+
+		tags = append(tags, tag)
+
+		// the tag goes away with the space before it, the leading one with the space after it
+		if text.Len() == 0 && space == "" {
+			rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		}
+	}
+
+	if len(tags) == 0 {
+		return description, nil
+	}
+
+	slices.Sort(tags)
+
+	return text.String(), slices.Compact(tags)
+}
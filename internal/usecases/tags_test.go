@@ -0,0 +1,66 @@
+package usecases_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/therenotomorrow/tasker/internal/usecases"
+)
+
+func TestUnitExtractTags(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		description string
+		tags        []string
+	}
+
+	tests := []struct {
+		name        string
+		description string
+		want        want
+	}{
+		{name: "no tags", description: "  fix  login ", want: want{description: "  fix  login "}},
+		{
+			name:        "tags",
+			description: "+Infra fix login +backend",
+			want:        want{description: "fix login", tags: []string{"backend", "infra"}},
+		},
+		{
+			name:        "duplicates",
+			description: "fix +infra +INFRA",
+			want:        want{description: "fix", tags: []string{"infra"}},
+		},
+		{
+			name:        "not a tag",
+			description: "vote +1 for c++ +api",
+			want:        want{description: "vote +1 for c++", tags: []string{"api"}},
+		},
+		{
+			name:        "spacing",
+			description: "fix  the\tlogin +infra  now\n",
+			want:        want{description: "fix  the\tlogin  now\n", tags: []string{"infra"}},
+		},
+		{
+			name:        "leading tags",
+			description: "+infra  +api fix",
+			want:        want{description: "fix", tags: []string{"api", "infra"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			description, tags := usecases.ExtractTags(test.description)
+
+			if description != test.want.description {
+				t.Errorf("ExtractTags() got = %q, want = %q", description, test.want.description)
+			}
+
+			if !slices.Equal(tags, test.want.tags) {
+				t.Errorf("ExtractTags() got = %v, want = %v", tags, test.want.tags)
+			}
+		})
+	}
+}
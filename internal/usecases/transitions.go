@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+// ReopenTask brings the closed task back to the initial status, the normal MarkTask
+// never does it, so the transition is recorded with the reason why.
+func (use *UseCases) ReopenTask(ctx context.Context, tid string, reason string) (*domain.Task, error) {
+	const where = "ReopenTask"
+
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
+	taskID, err := use.validateTaskID(tid)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	task, err := use.storage.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	if use.isOpen(task) {
+		return nil, fmt.Errorf("%s error: %w", where, domain.ErrTaskNotClosed)
+	}
+
+	before := snapshot(task)
+
+	transit(task, use.workflow.Initial(), reason)
+	use.record(task, before)
+
+	err = use.storage.UpdateTask(ctx, task)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	err = use.writeJournal(ctx, where, revise(&before, task))
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return task, nil
+}
+
+type CancelOptions struct {
+	// Force cancels the task with open subtasks.
+	Force bool
+}
+
+// CancelTask closes the open task without completing it, the transition is
+// recorded with the reason why.
+func (use *UseCases) CancelTask(
+	ctx context.Context, tid string, reason string, opts CancelOptions,
+) (*domain.Task, error) {
+	const where = "CancelTask"
+
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
+	taskID, err := use.validateTaskID(tid)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	task, err := use.storage.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	cancelled := use.workflow.Cancelled()
+
+	switch {
+	case !use.isOpen(task):
+		err = domain.ErrTaskAlreadyDone
+	case cancelled == "":
+		// the workflow without the cancellation could not close tasks this way
+		err = &domain.TransitionError{
+			From:    task.Status,
+			To:      domain.StatusCancelled,
+			Allowed: use.workflow.Allowed(task.Status),
+		}
+	case !opts.Force:
+		err = use.checkSubtasks(ctx, task)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	before := snapshot(task)
+
+	transit(task, cancelled, reason)
+	use.record(task, before)
+
+	err = use.storage.UpdateTask(ctx, task)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	err = use.writeJournal(ctx, where, revise(&before, task))
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return task, nil
+}
+
+func transit(task *domain.Task, status domain.Status, reason string) {
+	now := time.Now()
+
+	task.Transition = &domain.Transition{
+		From:   task.Status,
+		To:     status,
+		Reason: strings.TrimSpace(reason),
+		At:     now,
+	}
+	task.Status = status
+	task.UpdatedAt = now
+}
@@ -0,0 +1,303 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+	"github.com/therenotomorrow/tasker/internal/storage"
+	"github.com/therenotomorrow/tasker/internal/usecases"
+	"github.com/therenotomorrow/tasker/pkg/testkit"
+)
+
+func TestUnitUseCasesReopenTask(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		tid    string
+		reason string
+	}
+
+	type want struct {
+		task *domain.Task
+		err  error
+	}
+
+	now := time.Now().Truncate(time.Minute)
+
+	tests := []struct {
+		name   string
+		status domain.Status
+		args   args
+		want   want
+	}{
+		{
+			name:   "invalid taskID",
+			status: domain.StatusDone,
+			args:   args{tid: "invalid"},
+			want:   want{err: domain.ErrInvalidTaskID},
+		},
+		{
+			name:   taskNotFoundTest,
+			status: domain.StatusDone,
+			args:   args{tid: "1"},
+			want:   want{err: domain.ErrTaskNotFound},
+		},
+		{
+			name:   "not closed",
+			status: domain.StatusProgress,
+			args:   args{tid: "1"},
+			want:   want{err: domain.ErrTaskNotClosed},
+		},
+		{
+			name:   testkit.FailureTest,
+			status: domain.StatusDone,
+			args:   args{tid: "1"},
+			want:   want{err: testkit.ErrDummy},
+		},
+		{
+			name:   "done",
+			status: domain.StatusDone,
+			args:   args{tid: "1", reason: " not finished yet "},
+			want: want{task: &domain.Task{
+				ID:        1,
+				Status:    domain.StatusTodo,
+				UpdatedAt: now,
+				Transition: &domain.Transition{
+					From:   domain.StatusDone,
+					To:     domain.StatusTodo,
+					Reason: "not finished yet",
+					At:     now,
+				},
+				History: []domain.Change{{At: now, Field: "status", Old: "done", New: "todo"}},
+			}},
+		},
+		{
+			name:   "cancelled",
+			status: domain.StatusCancelled,
+			args:   args{tid: "1"},
+			want: want{task: &domain.Task{
+				ID:         1,
+				Status:     domain.StatusTodo,
+				UpdatedAt:  now,
+				Transition: &domain.Transition{From: domain.StatusCancelled, To: domain.StatusTodo, At: now},
+				History:    []domain.Change{{At: now, Field: "status", Old: "cancelled", New: "todo"}},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+				if test.name == taskNotFoundTest {
+					return nil, domain.ErrTaskNotFound
+				}
+
+				return &domain.Task{ID: tid, Status: test.status}, nil
+			}
+			stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
+				if test.name == testkit.FailureTest {
+					return testkit.ErrDummy
+				}
+
+				return nil
+			}
+
+			ctx := t.Context()
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.ReopenTask(ctx, test.args.tid, test.args.reason)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("ReopenTask() error = %v, want = %v", err, test.want.err)
+			}
+
+			if got != nil {
+				got.UpdatedAt = got.UpdatedAt.Truncate(time.Minute)
+				got.Transition.At = got.Transition.At.Truncate(time.Minute)
+				truncateHistory(got)
+			}
+
+			if !reflect.DeepEqual(got, test.want.task) {
+				t.Errorf("ReopenTask() got = %v, want = %v", got, test.want.task)
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesCancelTask(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		tid    string
+		reason string
+		opts   usecases.CancelOptions
+	}
+
+	type want struct {
+		task *domain.Task
+		err  error
+	}
+
+	now := time.Now().Truncate(time.Minute)
+	cancelled := &domain.Task{
+		ID:        1,
+		Status:    domain.StatusCancelled,
+		UpdatedAt: now,
+		Transition: &domain.Transition{
+			From:   domain.StatusProgress,
+			To:     domain.StatusCancelled,
+			Reason: "duplicate",
+			At:     now,
+		},
+		History: []domain.Change{{At: now, Field: "status", Old: "progress", New: "cancelled"}},
+	}
+
+	tests := []struct {
+		name   string
+		status domain.Status
+		args   args
+		want   want
+	}{
+		{
+			name:   "invalid taskID",
+			status: domain.StatusProgress,
+			args:   args{tid: "invalid"},
+			want:   want{err: domain.ErrInvalidTaskID},
+		},
+		{
+			name:   taskNotFoundTest,
+			status: domain.StatusProgress,
+			args:   args{tid: "1"},
+			want:   want{err: domain.ErrTaskNotFound},
+		},
+		{
+			name:   "already closed",
+			status: domain.StatusDone,
+			args:   args{tid: "1"},
+			want:   want{err: domain.ErrTaskAlreadyDone},
+		},
+		{
+			name:   "open subtasks",
+			status: domain.StatusProgress,
+			args:   args{tid: "1"},
+			want:   want{err: domain.ErrOpenSubtasks},
+		},
+		{
+			name:   "open subtasks forced",
+			status: domain.StatusProgress,
+			args:   args{tid: "1", reason: "duplicate", opts: usecases.CancelOptions{Force: true}},
+			want:   want{task: cancelled},
+		},
+		{
+			name:   testkit.FailureTest,
+			status: domain.StatusProgress,
+			args:   args{tid: "1"},
+			want:   want{err: testkit.ErrDummy},
+		},
+		{
+			name:   testkit.SuccessTest,
+			status: domain.StatusProgress,
+			args:   args{tid: "1", reason: "duplicate"},
+			want:   want{task: cancelled},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+				if test.name == taskNotFoundTest {
+					return nil, domain.ErrTaskNotFound
+				}
+
+				return &domain.Task{ID: tid, Status: test.status}, nil
+			}
+			stor.ListChildrenFunc = func(ctx context.Context, parentID uint64) ([]*domain.Task, error) {
+				if test.name == "open subtasks" || test.name == "open subtasks forced" {
+					return []*domain.Task{{ID: 2, Status: domain.StatusTodo, ParentID: parentID}}, nil
+				}
+
+				return make([]*domain.Task, 0), nil
+			}
+			stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
+				if test.name == testkit.FailureTest {
+					return testkit.ErrDummy
+				}
+
+				return nil
+			}
+
+			ctx := t.Context()
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.CancelTask(ctx, test.args.tid, test.args.reason, test.args.opts)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("CancelTask() error = %v, want = %v", err, test.want.err)
+			}
+
+			if got != nil {
+				got.UpdatedAt = got.UpdatedAt.Truncate(time.Minute)
+				got.Transition.At = got.Transition.At.Truncate(time.Minute)
+				truncateHistory(got)
+			}
+
+			if !reflect.DeepEqual(got, test.want.task) {
+				t.Errorf("CancelTask() got = %v, want = %v", got, test.want.task)
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesCancelTaskWorkflow(t *testing.T) {
+	t.Parallel()
+
+	flow, _ := domain.NewWorkflow([]string{"todo", "done"}, []string{"done"}, map[string][]string{"todo": {"done"}})
+	stor := new(storage.Mock)
+
+	stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+		return &domain.Task{ID: tid, Status: domain.StatusTodo}, nil
+	}
+
+	use := usecases.New(usecases.Config{Storage: stor, Workflow: flow})
+	_, err := use.CancelTask(t.Context(), "1", "", usecases.CancelOptions{})
+
+	want := &domain.TransitionError{From: "todo", To: "cancelled", Allowed: []domain.Status{"done"}}
+
+	var got *domain.TransitionError
+	if !errors.As(err, &got) || !reflect.DeepEqual(got, want) {
+		t.Errorf("CancelTask() error = %v, want = %v", err, want)
+	}
+
+	// the renamed cancellation is the one the task gets
+	flow, _ = domain.NewWorkflow(
+		[]string{"open", "shipped", "dropped"},
+		[]string{"shipped", "dropped"},
+		map[string][]string{"open": {"shipped", "dropped"}},
+	)
+	stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+		return &domain.Task{ID: tid, Status: "open"}, nil
+	}
+	stor.ListChildrenFunc = func(ctx context.Context, parentID uint64) ([]*domain.Task, error) {
+		return make([]*domain.Task, 0), nil
+	}
+	stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
+		return nil
+	}
+
+	use = usecases.New(usecases.Config{Storage: stor, Workflow: flow})
+
+	task, err := use.CancelTask(t.Context(), "1", "", usecases.CancelOptions{})
+	if err != nil || task.Status != "dropped" {
+		t.Errorf("CancelTask() got = %v, error = %v, want = %v", task, err, "dropped")
+	}
+}
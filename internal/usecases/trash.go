@@ -0,0 +1,169 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+)
+
+// discard marks the task as moved to the trash.
+func (use *UseCases) discard(task *domain.Task, now time.Time) {
+	task.DeletedAt = now
+	task.History = append(task.History, use.change(domain.FieldTask, "", domain.TaskDeleted, now))
+}
+
+// ListTrash gives the deleted tasks from the latest deleted.
+func (use *UseCases) ListTrash(ctx context.Context) ([]*domain.Task, error) {
+	const where = "ListTrash"
+
+	tasks, err := use.storage.ListTrash(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	slices.SortFunc(tasks, func(a, b *domain.Task) int {
+		return cmp.Or(b.DeletedAt.Compare(a.DeletedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return tasks, nil
+}
+
+// RestoreTask brings the deleted task back together with the subtasks deleted along with it.
+func (use *UseCases) RestoreTask(ctx context.Context, tid string) ([]*domain.Task, error) {
+	const where = "RestoreTask"
+
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
+	taskID, err := use.validateTaskID(tid)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	task, err := use.storage.GetTrashed(ctx, taskID)
+	if errors.Is(err, domain.ErrTaskNotFound) {
+		if _, found := use.storage.GetByID(ctx, taskID); found == nil {
+			err = domain.ErrTaskNotDeleted
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	if task.HasParent() {
+		_, err = use.storage.GetTrashed(ctx, task.ParentID)
+		if err == nil {
+			err = domain.ErrParentDeleted
+		}
+
+		if !errors.Is(err, domain.ErrTaskNotFound) {
+			return nil, fmt.Errorf("%s error: %w", where, err)
+		}
+	}
+
+	trash, err := use.storage.ListTrash(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	tasks := trashedSubtree(task, trash)
+	now := time.Now()
+	revisions := make([]domain.Revision, len(tasks))
+
+	for idx, task := range tasks {
+		before := *task
+
+		task.DeletedAt = time.Time{}
+		task.UpdatedAt = now
+		task.History = append(task.History, use.change(domain.FieldTask, "", domain.TaskRestored, now))
+		use.record(task, before)
+
+		revisions[idx] = revise(nil, task)
+	}
+
+	err = use.storage.RestoreTasks(ctx, tasks)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	err = use.writeJournal(ctx, where, revisions...)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return tasks, nil
+}
+
+// trashedSubtree gives the task with its subtasks deleted at the same time, the parents go first.
+func trashedSubtree(task *domain.Task, trash []*domain.Task) []*domain.Task {
+	slices.SortFunc(trash, func(a, b *domain.Task) int { return cmp.Compare(a.ID, b.ID) })
+
+	tasks := []*domain.Task{task}
+	seen := map[uint64]bool{task.ID: true}
+
+	for idx := 0; idx < len(tasks); idx++ {
+		for _, child := range trash {
+			if !seen[child.ID] && child.ParentID == tasks[idx].ID && child.DeletedAt.Equal(task.DeletedAt) {
+				seen[child.ID] = true
+				tasks = append(tasks, child)
+			}
+		}
+	}
+
+	return tasks
+}
+
+// PurgeTrash removes the deleted tasks for good, only the ones deleted before
+// the period if it is given, like "30d".
+func (use *UseCases) PurgeTrash(ctx context.Context, olderThan string) ([]*domain.Task, error) {
+	const where = "PurgeTrash"
+
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
+	var period time.Duration
+
+	if olderThan != "" {
+		parsed, err := ParsePeriod(olderThan)
+		if err != nil {
+			return nil, fmt.Errorf("%s error: %w", where, err)
+		}
+
+		period = parsed
+	}
+
+	trash, err := use.storage.ListTrash(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	cutoff := time.Now().Add(-period)
+	tasks := slices.DeleteFunc(trash, func(task *domain.Task) bool { return task.DeletedAt.After(cutoff) })
+
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+
+	slices.SortFunc(tasks, func(a, b *domain.Task) int { return cmp.Compare(a.ID, b.ID) })
+
+	err = use.storage.PurgeTasks(ctx, tasks)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,238 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/therenotomorrow/tasker/internal/domain"
+	"github.com/therenotomorrow/tasker/internal/storage"
+	"github.com/therenotomorrow/tasker/internal/usecases"
+	"github.com/therenotomorrow/tasker/pkg/testkit"
+)
+
+func taskIDs(tasks []*domain.Task) []uint64 {
+	ids := make([]uint64, len(tasks))
+	for idx, task := range tasks {
+		ids[idx] = task.ID
+	}
+
+	return ids
+}
+
+func TestUnitUseCasesTrash(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx  = t.Context()
+		stor = journaled()
+		use  = usecases.New(usecases.Config{Storage: stor, Journal: stor})
+	)
+
+	_, _ = use.AddTask(ctx, usecases.AddParams{Description: "parent"})
+	_, _ = use.AddTask(ctx, usecases.AddParams{Description: "child", ParentID: "1"})
+	_, _ = use.AddTask(ctx, usecases.AddParams{Description: "other"})
+	_ = use.DeleteTask(ctx, "1", usecases.DeleteOptions{Cascade: true})
+
+	trash, _ := use.ListTrash(ctx)
+	if got, want := taskIDs(trash), []uint64{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListTrash() got = %v, want = %v", got, want)
+	}
+
+	for tid, want := range map[string]error{
+		"2":       domain.ErrParentDeleted,
+		"3":       domain.ErrTaskNotDeleted,
+		"42":      domain.ErrTaskNotFound,
+		"invalid": domain.ErrInvalidTaskID,
+	} {
+		if _, err := use.RestoreTask(ctx, tid); !errors.Is(err, want) {
+			t.Errorf("RestoreTask(%v) error = %v, want = %v", tid, err, want)
+		}
+	}
+
+	restored, err := use.RestoreTask(ctx, "1")
+	if got, want := taskIDs(restored), []uint64{1, 2}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("RestoreTask() got = %v, error = %v, want = %v", got, err, want)
+	}
+
+	child, _ := stor.GetByID(ctx, 2)
+	if last := child.History[len(child.History)-1]; child.IsDeleted() || last.New != domain.TaskRestored {
+		t.Errorf("RestoreTask() got = %v, want = %v", child, "restored child")
+	}
+
+	// undo puts the restored tasks back to the trash
+	_, _ = use.Undo(ctx, "")
+
+	trash, _ = use.ListTrash(ctx)
+	if got, want := taskIDs(trash), []uint64{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Undo() got = %v, want = %v", got, want)
+	}
+
+	_ = use.DeleteTask(ctx, "3", usecases.DeleteOptions{})
+
+	purged, _ := use.PurgeTrash(ctx, "1d")
+	if len(purged) != 0 {
+		t.Errorf("PurgeTrash() got = %v, want = %v", taskIDs(purged), []uint64{})
+	}
+
+	purged, _ = use.PurgeTrash(ctx, "")
+	if got, want := taskIDs(purged), []uint64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PurgeTrash() got = %v, want = %v", got, want)
+	}
+
+	// the purged task is gone for good
+	_, err = use.Undo(ctx, "")
+	if !errors.Is(err, domain.ErrJournalConflict) {
+		t.Errorf("Undo() error = %v, want = %v", err, domain.ErrJournalConflict)
+	}
+}
+
+func TestUnitUseCasesListTrash(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	stor := new(storage.Mock)
+
+	stor.ListTrashFunc = func(ctx context.Context) ([]*domain.Task, error) {
+		return []*domain.Task{
+			{ID: 3, DeletedAt: now.Add(-time.Hour)},
+			{ID: 2, DeletedAt: now},
+			{ID: 1, DeletedAt: now},
+		}, nil
+	}
+
+	use := usecases.New(usecases.Config{Storage: stor})
+
+	got, _ := use.ListTrash(t.Context())
+	if ids, want := taskIDs(got), []uint64{1, 2, 3}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ListTrash() got = %v, want = %v", ids, want)
+	}
+
+	stor.ListTrashFunc = func(ctx context.Context) ([]*domain.Task, error) {
+		return nil, testkit.ErrDummy
+	}
+
+	_, err := use.ListTrash(t.Context())
+	if !errors.Is(err, testkit.ErrDummy) {
+		t.Errorf("ListTrash() error = %v, want = %v", err, testkit.ErrDummy)
+	}
+}
+
+func TestUnitUseCasesRestoreTask(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want error
+	}{
+		{name: "get failure", want: testkit.ErrDummy},
+		{name: "parent failure", want: testkit.ErrDummy},
+		{name: "list failure", want: testkit.ErrDummy},
+		{name: testkit.FailureTest, want: testkit.ErrDummy},
+		{name: testkit.SuccessTest, want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.GetTrashedFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+				switch {
+				case test.name == "get failure", test.name == "parent failure" && tid == 1:
+					return nil, testkit.ErrDummy
+				case tid == 1:
+					return nil, domain.ErrTaskNotFound
+				}
+
+				return &domain.Task{ID: tid, ParentID: 1, DeletedAt: time.Now()}, nil
+			}
+			stor.ListTrashFunc = func(ctx context.Context) ([]*domain.Task, error) {
+				if test.name == "list failure" {
+					return nil, testkit.ErrDummy
+				}
+
+				return make([]*domain.Task, 0), nil
+			}
+			stor.RestoreTasksFunc = func(ctx context.Context, tasks []*domain.Task) error {
+				if test.name == testkit.FailureTest {
+					return testkit.ErrDummy
+				}
+
+				return nil
+			}
+
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.RestoreTask(t.Context(), "2")
+
+			if !errors.Is(err, test.want) {
+				t.Fatalf("RestoreTask() error = %v, want = %v", err, test.want)
+			}
+
+			if err == nil && (len(got) != 1 || got[0].IsDeleted()) {
+				t.Errorf("RestoreTask() got = %v, want = %v", got, "restored task")
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesPurgeTrash(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		ids []uint64
+		err error
+	}
+
+	tests := []struct {
+		name      string
+		olderThan string
+		want      want
+	}{
+		{name: "invalid period", olderThan: "month", want: want{err: domain.ErrInvalidPeriod}},
+		{name: "overflowed period", olderThan: "9999999999999d", want: want{err: domain.ErrInvalidPeriod}},
+		{name: "list failure", olderThan: "", want: want{err: testkit.ErrDummy}},
+		{name: testkit.FailureTest, olderThan: "", want: want{err: testkit.ErrDummy}},
+		{name: "older than", olderThan: "30d", want: want{ids: []uint64{1}}},
+		{name: "nothing older", olderThan: "8w", want: want{ids: []uint64{}}},
+		{name: testkit.SuccessTest, olderThan: "", want: want{ids: []uint64{1, 2}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Now()
+			stor := new(storage.Mock)
+
+			stor.ListTrashFunc = func(ctx context.Context) ([]*domain.Task, error) {
+				if test.name == "list failure" {
+					return nil, testkit.ErrDummy
+				}
+
+				return []*domain.Task{{ID: 2, DeletedAt: now}, {ID: 1, DeletedAt: now.AddDate(0, 0, -40)}}, nil
+			}
+			stor.PurgeTasksFunc = func(ctx context.Context, tasks []*domain.Task) error {
+				if test.name == testkit.FailureTest {
+					return testkit.ErrDummy
+				}
+
+				return nil
+			}
+
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.PurgeTrash(t.Context(), test.olderThan)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("PurgeTrash() error = %v, want = %v", err, test.want.err)
+			}
+
+			if ids := taskIDs(got); err == nil && !reflect.DeepEqual(ids, test.want.ids) {
+				t.Errorf("PurgeTrash() got = %v, want = %v", ids, test.want.ids)
+			}
+		})
+	}
+}
@@ -1,25 +1,119 @@
 package usecases
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/therenotomorrow/tasker/internal/domain"
 )
 
+type Config struct {
+	Storage Storage
+	// Workflow declares the task statuses, nil stands for the default one.
+	Workflow *domain.Workflow
+	// Actor is the one making the changes, it goes to the task history.
+	Actor string
+	// Journal keeps the operations to undo, nil turns the journal off.
+	Journal Journal
+	// Locker holds the storage for the whole command, nil leaves every storage call to lock alone.
+	Locker Locker
+}
+
 type UseCases struct {
-	storage Storage
+	storage  Storage
+	workflow *domain.Workflow
+	actor    string
+	journal  Journal
+	locker   Locker
+}
+
+func New(config Config) *UseCases {
+	workflow := config.Workflow
+	if workflow == nil {
+		workflow = domain.DefaultWorkflow()
+	}
+
+	return &UseCases{
+		storage:  config.Storage,
+		workflow: workflow,
+		actor:    config.Actor,
+		journal:  config.Journal,
+		locker:   config.Locker,
+	}
+}
+
+// lock keeps other processes from changing the tasks between the reads and writes of the command.
+func (use *UseCases) lock(ctx context.Context) (context.Context, func(), error) {
+	if use.locker == nil {
+		return ctx, func() {}, nil
+	}
+
+	return use.locker.Lock(ctx)
 }
 
-func New(storage Storage) *UseCases {
-	return &UseCases{storage: storage}
+func (use *UseCases) Workflow() *domain.Workflow {
+	return use.workflow
 }
 
-func (use *UseCases) AddTask(ctx context.Context, description string) (*domain.Task, error) {
+type AddParams struct {
+	Description string
+	Priority    string
+	Project     string
+	ParentID    string
+}
+
+func (use *UseCases) AddTask(ctx context.Context, params AddParams) (*domain.Task, error) {
 	const where = "AddTask"
 
-	description, err := use.validateDescription(description)
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
+	description, tags := ExtractTags(params.Description)
+
+	description, err = use.validateDescription(description)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	priority := domain.PriorityMedium
+	if params.Priority != "" {
+		priority, err = use.validatePriority(params.Priority)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	project := ""
+	if params.Project != "" {
+		project, err = use.validateProject(params.Project)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	var parentID uint64
+	if params.ParentID != "" {
+		parentID, err = use.validateTaskID(params.ParentID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	if parentID != 0 {
+		_, err = use.storage.GetByID(ctx, parentID)
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("%s error: %w", where, err)
 	}
@@ -28,22 +122,48 @@ func (use *UseCases) AddTask(ctx context.Context, description string) (*domain.T
 	task := &domain.Task{
 		ID:          0,
 		Description: description,
-		Status:      domain.StatusTodo,
+		Status:      use.workflow.Initial(),
+		Priority:    priority,
 		CreatedAt:   now,
 		UpdatedAt:   now,
+		DueAt:       time.Time{},
+		DeletedAt:   time.Time{},
+		ArchivedAt:  time.Time{},
+		Tags:        tags,
+		Project:     project,
+		ParentID:    parentID,
+		BlockedBy:   nil,
+		Recurrence:  "",
+		RecurOf:     0,
+		Transition:  nil,
+		History:     []domain.Change{use.change(domain.FieldTask, "", domain.TaskAdded, now)},
 	}
 
+	use.record(task, domain.Task{})
+
 	task, err = use.storage.SaveTask(ctx, task)
 	if err != nil {
 		return nil, fmt.Errorf("%s error: %w", where, err)
 	}
 
+	err = use.writeJournal(ctx, where, revise(nil, task))
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
 	return task, nil
 }
 
 func (use *UseCases) UpdateTask(ctx context.Context, tid string, description string) (*domain.Task, error) {
 	const where = "UpdateTask"
 
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
 	taskID, err := use.validateTaskID(tid)
 	if err != nil {
 		return nil, fmt.Errorf("%s error: %w", where, err)
@@ -59,20 +179,40 @@ func (use *UseCases) UpdateTask(ctx context.Context, tid string, description str
 		return nil, fmt.Errorf("%s error: %w", where, err)
 	}
 
+	before := snapshot(task)
+
 	task.Description = description
 	task.UpdatedAt = time.Now()
+	use.record(task, before)
 
 	err = use.storage.UpdateTask(ctx, task)
 	if err != nil {
 		return nil, fmt.Errorf("%s error: %w", where, err)
 	}
 
+	err = use.writeJournal(ctx, where, revise(&before, task))
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
 	return task, nil
 }
 
-func (use *UseCases) DeleteTask(ctx context.Context, tid string) error {
+type DeleteOptions struct {
+	// Cascade deletes the task together with all its subtasks.
+	Cascade bool
+}
+
+func (use *UseCases) DeleteTask(ctx context.Context, tid string, opts DeleteOptions) error {
 	const where = "DeleteTask"
 
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
 	taskID, err := use.validateTaskID(tid)
 	if err != nil {
 		return fmt.Errorf("%s error: %w", where, err)
@@ -83,7 +223,34 @@ func (use *UseCases) DeleteTask(ctx context.Context, tid string) error {
 		return fmt.Errorf("%s error: %w", where, err)
 	}
 
-	err = use.storage.DeleteTask(ctx, task)
+	tasks, err := use.subtree(ctx, task)
+	if err != nil {
+		return fmt.Errorf("%s error: %w", where, err)
+	}
+
+	// the deleted tasks go to the trash together with their history
+	now := time.Now()
+	revisions := make([]domain.Revision, len(tasks))
+
+	for idx, task := range tasks {
+		revisions[idx] = revise(task, nil)
+		use.discard(task, now)
+	}
+
+	switch {
+	case len(tasks) == 1:
+		err = use.storage.DeleteTask(ctx, task)
+	case opts.Cascade:
+		err = use.storage.DeleteTasks(ctx, tasks)
+	default:
+		err = domain.ErrHasSubtasks
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s error: %w", where, err)
+	}
+
+	err = use.writeJournal(ctx, where, revisions...)
 	if err != nil {
 		return fmt.Errorf("%s error: %w", where, err)
 	}
@@ -91,9 +258,23 @@ func (use *UseCases) DeleteTask(ctx context.Context, tid string) error {
 	return nil
 }
 
-func (use *UseCases) MarkTask(ctx context.Context, tid string, mark string) (*domain.Task, error) {
+type MarkOptions struct {
+	// Force changes the status even with open subtasks or dependencies.
+	Force bool
+	// CloseParent marks the parent as done together with its last open subtask.
+	CloseParent bool
+}
+
+func (use *UseCases) MarkTask(ctx context.Context, tid string, mark string, opts MarkOptions) (*domain.Task, error) {
 	const where = "MarkTask"
 
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
 	taskID, err := use.validateTaskID(tid)
 	if err != nil {
 		return nil, fmt.Errorf("%s error: %w", where, err)
@@ -109,23 +290,246 @@ func (use *UseCases) MarkTask(ctx context.Context, tid string, mark string) (*do
 		return nil, fmt.Errorf("%s error: %w", where, err)
 	}
 
-	if task.IsDone() {
+	if use.workflow.IsTerminal(task.Status) {
 		return nil, fmt.Errorf("%s error: %w", where, domain.ErrTaskAlreadyDone)
 	}
 
+	if !use.workflow.CanMove(task.Status, status) {
+		err = &domain.TransitionError{From: task.Status, To: status, Allowed: use.workflow.Allowed(task.Status)}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	if use.workflow.IsTerminal(status) && !opts.Force {
+		err = use.checkSubtasks(ctx, task)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	if status != use.workflow.Initial() && !opts.Force {
+		err = use.checkBlocked(ctx, task)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	before := snapshot(task)
+
 	task.Status = status
 	task.UpdatedAt = time.Now()
+	use.record(task, before)
+
+	err = use.storage.UpdateTask(ctx, task)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	revisions := []domain.Revision{revise(&before, task)}
+
+	var next *domain.Task
+	if status == use.workflow.Completed() && task.IsRecurring() {
+		next, err = use.spawnNext(ctx, task)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	if next != nil {
+		revisions = append(revisions, revise(nil, next))
+	}
+
+	var parents []domain.Revision
+	if status == use.workflow.Completed() && opts.CloseParent {
+		parents, err = use.closeParents(ctx, task)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	err = use.writeJournal(ctx, where, append(revisions, parents...)...)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return task, nil
+}
+
+func (use *UseCases) PrioritizeTask(ctx context.Context, tid string, level string) (*domain.Task, error) {
+	const where = "PrioritizeTask"
+
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
+	taskID, err := use.validateTaskID(tid)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	priority, err := use.validatePriority(level)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	task, err := use.storage.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	before := snapshot(task)
+
+	task.Priority = priority
+	task.UpdatedAt = time.Now()
+	use.record(task, before)
+
+	err = use.storage.UpdateTask(ctx, task)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	err = use.writeJournal(ctx, where, revise(&before, task))
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return task, nil
+}
+
+// noDue is the value of DueTask "when" parameter that removes the due date.
+const noDue = "none"
+
+func (use *UseCases) DueTask(ctx context.Context, tid string, when string) (*domain.Task, error) {
+	const where = "DueTask"
+
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
+	taskID, err := use.validateTaskID(tid)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	var dueAt time.Time
+	if when != noDue {
+		dueAt, err = use.validateDate(when)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	task, err := use.storage.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	before := snapshot(task)
+
+	task.DueAt = dueAt
+	task.UpdatedAt = time.Now()
+	use.record(task, before)
 
 	err = use.storage.UpdateTask(ctx, task)
 	if err != nil {
 		return nil, fmt.Errorf("%s error: %w", where, err)
 	}
 
+	err = use.writeJournal(ctx, where, revise(&before, task))
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
 	return task, nil
 }
 
+func (use *UseCases) TagTask(ctx context.Context, tid string, add []string, remove []string) (*domain.Task, error) {
+	const where = "TagTask"
+
+	ctx, unlock, err := use.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	defer unlock()
+
+	taskID, err := use.validateTaskID(tid)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	add, err = use.validateTags(add)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	remove, err = use.validateTags(remove)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	task, err := use.storage.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	before := snapshot(task)
+
+	tags := slices.Concat(task.Tags, add)
+	tags = slices.DeleteFunc(tags, func(tag string) bool { return slices.Contains(remove, tag) })
+	slices.Sort(tags)
+
+	task.Tags = slices.Compact(tags)
+	task.UpdatedAt = time.Now()
+	use.record(task, before)
+
+	err = use.storage.UpdateTask(ctx, task)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	err = use.writeJournal(ctx, where, revise(&before, task))
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return task, nil
+}
+
+// isOverdue is the domain overdue check knowing the tasks closed in any terminal status.
+func (use *UseCases) isOverdue(task *domain.Task, now time.Time) bool {
+	return task.IsOverdue(now) && use.isOpen(task)
+}
+
 type ListParams struct {
-	Status string
+	Status        string
+	Priority      string
+	PriorityFirst bool
+	Overdue       bool
+	DueBefore     string
+	Tags          []string
+	ExcludeTags   []string
+	Project       string
+	Ready         bool
+	Scope         Scope
+	Filter        string
+	Sort          string
+	Limit         string
+	Offset        string
+	Reverse       bool
 }
 
 func (use *UseCases) ListTasks(ctx context.Context, params ListParams) ([]*domain.Task, error) {
@@ -142,21 +546,238 @@ func (use *UseCases) ListTasks(ctx context.Context, params ListParams) ([]*domai
 		return nil, fmt.Errorf("%s error: %w", where, err)
 	}
 
-	var tasks []*domain.Task
+	priority := domain.Priority("")
+	if params.Priority != "" {
+		priority, err = use.validatePriority(params.Priority)
+	}
 
-	if status == "" {
-		tasks, err = use.storage.ListAll(ctx)
-	} else {
-		tasks, err = use.storage.ListByStatus(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	var dueBefore time.Time
+	if params.DueBefore != "" {
+		dueBefore, err = use.validateDate(params.DueBefore)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	project := ""
+	if params.Project != "" {
+		project, err = use.validateProject(params.Project)
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("%s error: %w", where, err)
 	}
 
+	var query domain.TagQuery
+
+	query.Include, err = use.validateTags(params.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	query.Exclude, err = use.validateTags(params.ExcludeTags)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	now := time.Now()
+
+	filter, err := ParseFilter(params.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	match, err := use.compileFilter(filter, now)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	opts, err := use.listOptions(params)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	// the storage gives the page itself when nothing is filtered out after it
+	paged := params.Scope == ScopeActive && status == "" && priority == "" && !params.Overdue &&
+		dueBefore.IsZero() && project == "" && query.IsEmpty() && !params.Ready && len(filter.Terms) == 0
+
+	tasks, err := use.scoped(ctx, params.Scope, func() ([]*domain.Task, error) {
+		switch {
+		case !query.IsEmpty():
+			return use.storage.ListByTags(ctx, query)
+		case project != "":
+			return use.storage.ListByProject(ctx, project)
+		case status != "":
+			return use.storage.ListByStatus(ctx, status)
+		case paged:
+			return use.storage.List(ctx, opts)
+		default:
+			return use.storage.ListAll(ctx)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	// blocked state needs all the tasks, not only the listed ones
+	var deps dependencies
+	if params.Ready {
+		deps, err = use.listDependencies(ctx)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	tasks = slices.DeleteFunc(tasks, func(task *domain.Task) bool {
+		switch {
+		case status != "" && task.Status != status:
+			return true
+		case !query.Match(*task):
+			return true
+		case !match(task):
+			return true
+		case priority != "" && task.Priority != priority:
+			return true
+		case project != "" && !task.InProject(project):
+			return true
+		case params.Ready && (task.Status != use.workflow.Initial() || deps.isBlocked(task, use.workflow)):
+			return true
+		case params.Overdue && !use.isOverdue(task, now):
+			return true
+		case !dueBefore.IsZero() && (!task.HasDue() || task.DueAt.After(dueBefore)):
+			return true
+		}
+
+		return false
+	})
+
+	if !paged {
+		tasks = opts.Apply(tasks)
+	}
+
 	if len(tasks) == 0 {
 		return nil, domain.ErrEmptyTasks
 	}
 
 	return tasks, nil
 }
+
+// listOptions sorts the recently updated first by default, or the most important first when asked.
+func (use *UseCases) listOptions(params ListParams) (domain.ListOptions, error) {
+	opts := domain.ListOptions{
+		Sort:     domain.SortByUpdated,
+		Desc:     true,
+		Statuses: use.workflow.Statuses(),
+		Offset:   0,
+		Limit:    0,
+		Reverse:  params.Reverse,
+	}
+
+	if params.PriorityFirst {
+		opts.Sort = domain.SortByPriority
+	}
+
+	var err error
+
+	if params.Sort != "" {
+		opts.Sort, opts.Desc, err = use.validateSort(params.Sort)
+	}
+
+	if err != nil {
+		return opts, err
+	}
+
+	if params.Limit != "" {
+		opts.Limit, err = use.validateLimit(params.Limit)
+	}
+
+	if err != nil {
+		return opts, err
+	}
+
+	if params.Offset != "" {
+		opts.Offset, err = use.validateOffset(params.Offset)
+	}
+
+	return opts, err
+}
+
+// ProjectStats counts tasks by status in the project together with its sub-projects,
+// the empty Project stands for tasks without any.
+type ProjectStats struct {
+	Project string
+	Depth   int
+	Counts  map[domain.Status]int
+}
+
+func (use *UseCases) ListProjects(ctx context.Context) ([]*ProjectStats, error) {
+	const where = "ListProjects"
+
+	tasks, err := use.storage.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	if len(tasks) == 0 {
+		return nil, domain.ErrEmptyTasks
+	}
+
+	projects := make(map[string]*ProjectStats)
+
+	for _, task := range tasks {
+		hierarchy := domain.ProjectHierarchy(task.Project)
+		if len(hierarchy) == 0 {
+			hierarchy = []string{""}
+		}
+
+		for depth, project := range hierarchy {
+			stats, ok := projects[project]
+			if !ok {
+				stats = &ProjectStats{Project: project, Depth: depth, Counts: make(map[domain.Status]int)}
+				projects[project] = stats
+			}
+
+			stats.Counts[task.Status]++
+		}
+	}
+
+	// parents go before their children, tasks without project go last
+	list := slices.Collect(maps.Values(projects))
+	slices.SortFunc(list, func(a, b *ProjectStats) int {
+		if a.Project == "" || b.Project == "" {
+			return cmp.Compare(b.Project, a.Project)
+		}
+
+		return domain.CompareProjects(a.Project, b.Project)
+	})
+
+	return list, nil
+}
+
+func (use *UseCases) CheckSchema(ctx context.Context) (domain.Schema, error) {
+	const where = "CheckSchema"
+
+	schema, err := use.storage.SchemaVersion(ctx)
+	if err != nil {
+		return domain.Schema{}, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return schema, nil
+}
+
+func (use *UseCases) MigrateSchema(ctx context.Context) (domain.Schema, error) {
+	const where = "MigrateSchema"
+
+	schema, err := use.storage.Migrate(ctx)
+	if err != nil {
+		return domain.Schema{}, fmt.Errorf("%s error: %w", where, err)
+	}
+
+	return schema, nil
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/therenotomorrow/tasker/internal/config"
 	"github.com/therenotomorrow/tasker/internal/domain"
 	"github.com/therenotomorrow/tasker/internal/storage"
 	"github.com/therenotomorrow/tasker/internal/usecases"
@@ -18,18 +21,82 @@ const taskNotFoundTest = "task not found"
 func TestUnitNew(t *testing.T) {
 	t.Parallel()
 
-	use := usecases.New(new(storage.Mock))
+	use := usecases.New(usecases.Config{Storage: new(storage.Mock)})
 
 	if use == nil {
 		t.Errorf("New() got = %v, want = %v", use, new(usecases.UseCases))
 	}
+
+	if got, want := use.Workflow(), domain.DefaultWorkflow(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Workflow() got = %v, want = %v", got, want)
+	}
+}
+
+func TestUnitUseCasesLock(t *testing.T) {
+	t.Parallel()
+
+	type lockedKey struct{}
+
+	tests := []struct {
+		name string
+		want error
+	}{
+		{name: testkit.FailureTest, want: testkit.ErrDummy},
+		{name: testkit.SuccessTest, want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var locked, unlocked bool
+
+			stor := new(storage.Mock)
+
+			stor.LockFunc = func(ctx context.Context) (context.Context, func(), error) {
+				if test.name == testkit.FailureTest {
+					return nil, nil, testkit.ErrDummy
+				}
+
+				locked = true
+
+				return context.WithValue(ctx, lockedKey{}, true), func() { unlocked = true }, nil
+			}
+			// the whole read-modify-write goes under the lock
+			stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+				if ctx.Value(lockedKey{}) == nil || unlocked {
+					t.Errorf("GetByID() got = %v, want = %v", "unlocked", "locked")
+				}
+
+				return &domain.Task{ID: tid, Status: domain.StatusTodo}, nil
+			}
+			stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
+				if ctx.Value(lockedKey{}) == nil || unlocked {
+					t.Errorf("UpdateTask() got = %v, want = %v", "unlocked", "locked")
+				}
+
+				return nil
+			}
+
+			use := usecases.New(usecases.Config{Storage: stor, Locker: stor})
+			_, err := use.PrioritizeTask(t.Context(), "1", "high")
+
+			if !errors.Is(err, test.want) {
+				t.Fatalf("PrioritizeTask() error = %v, want = %v", err, test.want)
+			}
+
+			if locked != unlocked {
+				t.Errorf("PrioritizeTask() got = %v, want = %v", unlocked, locked)
+			}
+		})
+	}
 }
 
 func TestUnitUseCasesAddTask(t *testing.T) {
 	t.Parallel()
 
 	type args struct {
-		description string
+		params usecases.AddParams
 	}
 
 	type want struct {
@@ -37,6 +104,16 @@ func TestUnitUseCasesAddTask(t *testing.T) {
 		err  error
 	}
 
+	now := time.Now().Truncate(time.Minute)
+	added := func(priority string, changes ...domain.Change) []domain.Change {
+		return append([]domain.Change{
+			{At: now, Field: "task", New: "added"},
+			{At: now, Field: "description", New: "some task here"},
+			{At: now, Field: "status", New: "todo"},
+			{At: now, Field: "priority", New: priority},
+		}, changes...)
+	}
+
 	tests := []struct {
 		name string
 		args args
@@ -44,28 +121,116 @@ func TestUnitUseCasesAddTask(t *testing.T) {
 	}{
 		{
 			name: "empty description",
-			args: args{description: ""},
+			args: args{params: usecases.AddParams{Description: ""}},
 			want: want{err: domain.ErrEmptyDescription},
 		},
 		{
 			name: "empty trimmed description",
-			args: args{description: "    "},
+			args: args{params: usecases.AddParams{Description: "    "}},
 			want: want{err: domain.ErrEmptyDescription},
 		},
 		{
 			name: testkit.FailureTest,
-			args: args{description: "some description"},
+			args: args{params: usecases.AddParams{Description: "some description"}},
 			want: want{err: testkit.ErrDummy},
 		},
+		{
+			name: "invalid priority",
+			args: args{params: usecases.AddParams{Description: "some description", Priority: "urgent"}},
+			want: want{err: domain.ErrInvalidPriority},
+		},
 		{
 			name: testkit.SuccessTest,
-			args: args{description: "  some task here  "},
+			args: args{params: usecases.AddParams{Description: "  some task here  "}},
+			want: want{task: &domain.Task{
+				ID:          1,
+				Description: "some task here",
+				Status:      domain.StatusTodo,
+				Priority:    domain.PriorityMedium,
+				CreatedAt:   time.Now().Truncate(time.Minute),
+				UpdatedAt:   time.Now().Truncate(time.Minute),
+				History:     added("medium"),
+			}},
+		},
+		{
+			name: "with priority",
+			args: args{params: usecases.AddParams{Description: "some task here", Priority: "high"}},
+			want: want{task: &domain.Task{
+				ID:          1,
+				Description: "some task here",
+				Status:      domain.StatusTodo,
+				Priority:    domain.PriorityHigh,
+				CreatedAt:   time.Now().Truncate(time.Minute),
+				UpdatedAt:   time.Now().Truncate(time.Minute),
+				History:     added("high"),
+			}},
+		},
+		{
+			name: "only tags",
+			args: args{params: usecases.AddParams{Description: "+backend +infra"}},
+			want: want{err: domain.ErrEmptyDescription},
+		},
+		{
+			name: "with tags",
+			args: args{params: usecases.AddParams{Description: "fix +Infra login  +backend +1"}},
+			want: want{task: &domain.Task{
+				ID:          1,
+				Description: "fix login +1",
+				Status:      domain.StatusTodo,
+				Priority:    domain.PriorityMedium,
+				CreatedAt:   time.Now().Truncate(time.Minute),
+				UpdatedAt:   time.Now().Truncate(time.Minute),
+				Tags:        []string{"backend", "infra"},
+				History: []domain.Change{
+					{At: now, Field: "task", New: "added"},
+					{At: now, Field: "description", New: "fix login +1"},
+					{At: now, Field: "status", New: "todo"},
+					{At: now, Field: "priority", New: "medium"},
+					{At: now, Field: "tags", New: "backend infra"},
+				},
+			}},
+		},
+		{
+			name: "invalid project",
+			args: args{params: usecases.AddParams{Description: "some task here", Project: "platform..sso"}},
+			want: want{err: domain.ErrInvalidProject},
+		},
+		{
+			name: "with project",
+			args: args{params: usecases.AddParams{Description: "some task here", Project: "Platform.Auth"}},
+			want: want{task: &domain.Task{
+				ID:          1,
+				Description: "some task here",
+				Status:      domain.StatusTodo,
+				Priority:    domain.PriorityMedium,
+				CreatedAt:   time.Now().Truncate(time.Minute),
+				UpdatedAt:   time.Now().Truncate(time.Minute),
+				Project:     "platform.auth",
+				History:     added("medium", domain.Change{At: now, Field: "project", New: "platform.auth"}),
+			}},
+		},
+		{
+			name: "invalid parent",
+			args: args{params: usecases.AddParams{Description: "some task here", ParentID: "first"}},
+			want: want{err: domain.ErrInvalidTaskID},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{params: usecases.AddParams{Description: "some task here", ParentID: "3"}},
+			want: want{err: domain.ErrTaskNotFound},
+		},
+		{
+			name: "with parent",
+			args: args{params: usecases.AddParams{Description: "some task here", ParentID: "3"}},
 			want: want{task: &domain.Task{
 				ID:          1,
 				Description: "some task here",
 				Status:      domain.StatusTodo,
+				Priority:    domain.PriorityMedium,
 				CreatedAt:   time.Now().Truncate(time.Minute),
 				UpdatedAt:   time.Now().Truncate(time.Minute),
+				ParentID:    3,
+				History:     added("medium", domain.Change{At: now, Field: "parent", New: "3"}),
 			}},
 		},
 	}
@@ -80,7 +245,7 @@ func TestUnitUseCasesAddTask(t *testing.T) {
 				var err error
 
 				switch test.name {
-				case testkit.SuccessTest:
+				case testkit.SuccessTest, "with priority", "with tags", "with project", "with parent":
 					task.ID = 1
 				case testkit.FailureTest:
 					err = testkit.ErrDummy
@@ -88,10 +253,17 @@ func TestUnitUseCasesAddTask(t *testing.T) {
 
 				return task, err
 			}
+			stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+				if test.name == taskNotFoundTest {
+					return nil, domain.ErrTaskNotFound
+				}
+
+				return &domain.Task{ID: tid}, nil
+			}
 
 			ctx := t.Context()
-			use := usecases.New(stor)
-			got, err := use.AddTask(ctx, test.args.description)
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.AddTask(ctx, test.args.params)
 
 			if !errors.Is(err, test.want.err) {
 				t.Fatalf("AddTask() error = %v, want = %v", err, test.want.err)
@@ -100,6 +272,7 @@ func TestUnitUseCasesAddTask(t *testing.T) {
 			if got != nil {
 				got.CreatedAt = got.CreatedAt.Truncate(time.Minute)
 				got.UpdatedAt = got.UpdatedAt.Truncate(time.Minute)
+				truncateHistory(got)
 			}
 
 			if !reflect.DeepEqual(got, test.want.task) {
@@ -165,6 +338,12 @@ func TestUnitUseCasesUpdateTask(t *testing.T) {
 				Description: "new description",
 				Status:      domain.StatusDone,
 				UpdatedAt:   time.Now().Truncate(time.Minute),
+				History: []domain.Change{{
+					At:    time.Now().Truncate(time.Minute),
+					Field: "description",
+					Old:   "old description",
+					New:   "new description",
+				}},
 			}},
 		},
 	}
@@ -191,7 +370,7 @@ func TestUnitUseCasesUpdateTask(t *testing.T) {
 			}
 
 			ctx := t.Context()
-			use := usecases.New(stor)
+			use := usecases.New(usecases.Config{Storage: stor})
 			got, err := use.UpdateTask(ctx, test.args.tid, test.args.description)
 
 			if !errors.Is(err, test.want.err) {
@@ -200,6 +379,7 @@ func TestUnitUseCasesUpdateTask(t *testing.T) {
 
 			if got != nil {
 				got.UpdatedAt = got.UpdatedAt.Truncate(time.Minute)
+				truncateHistory(got)
 			}
 
 			if !reflect.DeepEqual(got, test.want.task) {
@@ -213,25 +393,50 @@ func TestUnitUseCasesDeleteTask(t *testing.T) {
 	t.Parallel()
 
 	type args struct {
-		tid string
+		tid  string
+		opts usecases.DeleteOptions
+	}
+
+	type want struct {
+		deleted []uint64
+		err     error
 	}
 
 	tests := []struct {
 		name string
 		args args
-		want error
+		want want
 	}{
-		{name: "invalid taskID", args: args{tid: "invalid"}, want: domain.ErrInvalidTaskID},
-		{name: "negative taskID", args: args{tid: "-1"}, want: domain.ErrInvalidTaskID},
-		{name: taskNotFoundTest, args: args{tid: "0"}, want: domain.ErrTaskNotFound},
-		{name: testkit.FailureTest, args: args{tid: "1"}, want: testkit.ErrDummy},
-		{name: testkit.SuccessTest, args: args{tid: "1"}, want: nil},
+		{name: "invalid taskID", args: args{tid: "invalid"}, want: want{err: domain.ErrInvalidTaskID}},
+		{name: "negative taskID", args: args{tid: "-1"}, want: want{err: domain.ErrInvalidTaskID}},
+		{name: taskNotFoundTest, args: args{tid: "0"}, want: want{err: domain.ErrTaskNotFound}},
+		{name: testkit.FailureTest, args: args{tid: "1"}, want: want{err: testkit.ErrDummy}},
+		{name: testkit.SuccessTest, args: args{tid: "1"}, want: want{deleted: []uint64{1}}},
+		{name: "subtasks failure", args: args{tid: "1"}, want: want{err: testkit.ErrDummy}},
+		{name: "has subtasks", args: args{tid: "1"}, want: want{err: domain.ErrHasSubtasks}},
+		{
+			name: "cascade",
+			args: args{tid: "1", opts: usecases.DeleteOptions{Cascade: true}},
+			want: want{deleted: []uint64{1, 2, 3, 4}},
+		},
+		{
+			name: "cascade failure",
+			args: args{tid: "1", opts: usecases.DeleteOptions{Cascade: true}},
+			want: want{err: testkit.ErrDummy},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
+			var deleted []uint64
+
+			// the deleted tasks are handed to the storage with the last history entry
+			markedDeleted := func(task *domain.Task) bool {
+				return len(task.History) == 1 && task.History[0].New == domain.TaskDeleted
+			}
+
 			stor := new(storage.Mock)
 
 			stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
@@ -241,20 +446,55 @@ func TestUnitUseCasesDeleteTask(t *testing.T) {
 
 				return &domain.Task{ID: 1, Description: "description", Status: domain.StatusDone}, nil
 			}
+			stor.ListChildrenFunc = func(ctx context.Context, parentID uint64) ([]*domain.Task, error) {
+				switch {
+				case test.name == "subtasks failure":
+					return nil, testkit.ErrDummy
+				case test.name != "has subtasks" && !strings.HasPrefix(test.name, "cascade"):
+					return nil, nil
+				case parentID == 1:
+					return []*domain.Task{{ID: 2, ParentID: 1}, {ID: 3, ParentID: 1}}, nil
+				case parentID == 3:
+					return []*domain.Task{{ID: 4, ParentID: 3}}, nil
+				}
+
+				return nil, nil
+			}
 			stor.DeleteTaskFunc = func(ctx context.Context, task *domain.Task) error {
 				if test.name == testkit.FailureTest {
 					return testkit.ErrDummy
 				}
 
+				if markedDeleted(task) {
+					deleted = append(deleted, task.ID)
+				}
+
+				return nil
+			}
+			stor.DeleteTasksFunc = func(ctx context.Context, tasks []*domain.Task) error {
+				if test.name == "cascade failure" {
+					return testkit.ErrDummy
+				}
+
+				for _, task := range tasks {
+					if markedDeleted(task) {
+						deleted = append(deleted, task.ID)
+					}
+				}
+
 				return nil
 			}
 
 			ctx := t.Context()
-			use := usecases.New(stor)
-			err := use.DeleteTask(ctx, test.args.tid)
+			use := usecases.New(usecases.Config{Storage: stor})
+			err := use.DeleteTask(ctx, test.args.tid, test.args.opts)
 
-			if !errors.Is(err, test.want) {
-				t.Fatalf("DeleteTask() error = %v, want = %v", err, test.want)
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("DeleteTask() error = %v, want = %v", err, test.want.err)
+			}
+
+			if !slices.Equal(deleted, test.want.deleted) {
+				t.Errorf("DeleteTask() got = %v, want = %v", deleted, test.want.deleted)
 			}
 		})
 	}
@@ -266,11 +506,18 @@ func TestUnitUseCasesMarkTask(t *testing.T) {
 	type args struct {
 		tid  string
 		mark string
+		opts usecases.MarkOptions
 	}
 
 	type want struct {
-		task *domain.Task
-		err  error
+		task    *domain.Task
+		updated []uint64
+		err     error
+	}
+
+	now := time.Now().Truncate(time.Minute)
+	marked := func(old domain.Status, status domain.Status) []domain.Change {
+		return []domain.Change{{At: now, Field: "status", Old: string(old), New: string(status)}}
 	}
 
 	tests := []struct {
@@ -316,11 +563,136 @@ func TestUnitUseCasesMarkTask(t *testing.T) {
 		{
 			name: testkit.SuccessTest,
 			args: args{tid: "1", mark: "done"},
-			want: want{task: &domain.Task{
-				ID:        1,
-				Status:    domain.StatusDone,
-				UpdatedAt: time.Now().Truncate(time.Minute),
-			}},
+			want: want{
+				task: &domain.Task{
+					ID:        1,
+					Status:    domain.StatusDone,
+					UpdatedAt: time.Now().Truncate(time.Minute),
+					History:   marked(domain.StatusTodo, domain.StatusDone),
+				},
+				updated: []uint64{1},
+			},
+		},
+		{
+			name: "subtasks failure",
+			args: args{tid: "1", mark: "done"},
+			want: want{err: testkit.ErrDummy},
+		},
+		{
+			name: "open subtasks",
+			args: args{tid: "1", mark: "done"},
+			want: want{err: domain.ErrOpenSubtasks},
+		},
+		{
+			name: "open subtasks forced",
+			args: args{tid: "1", mark: "done", opts: usecases.MarkOptions{Force: true}},
+			want: want{
+				task: &domain.Task{
+					ID:        1,
+					Status:    domain.StatusDone,
+					UpdatedAt: time.Now().Truncate(time.Minute),
+					History:   marked(domain.StatusTodo, domain.StatusDone),
+				},
+				updated: []uint64{1},
+			},
+		},
+		{
+			name: "close parents",
+			args: args{tid: "3", mark: "done", opts: usecases.MarkOptions{CloseParent: true}},
+			want: want{
+				task: &domain.Task{
+					ID:        3,
+					Status:    domain.StatusDone,
+					UpdatedAt: time.Now().Truncate(time.Minute),
+					ParentID:  2,
+					History:   marked(domain.StatusTodo, domain.StatusDone),
+				},
+				updated: []uint64{3, 2, 1},
+			},
+		},
+		{
+			name: "close parents with open sibling",
+			args: args{tid: "4", mark: "done", opts: usecases.MarkOptions{CloseParent: true}},
+			want: want{
+				task: &domain.Task{
+					ID:        4,
+					Status:    domain.StatusDone,
+					UpdatedAt: time.Now().Truncate(time.Minute),
+					ParentID:  1,
+					History:   marked(domain.StatusTodo, domain.StatusDone),
+				},
+				updated: []uint64{4},
+			},
+		},
+		{
+			name: "close parents failure",
+			args: args{tid: "3", mark: "done", opts: usecases.MarkOptions{CloseParent: true}},
+			want: want{err: testkit.ErrDummy},
+		},
+		{
+			name: "blocked",
+			args: args{tid: "1", mark: "progress"},
+			want: want{err: domain.ErrTaskBlocked},
+		},
+		{
+			name: "blocked failure",
+			args: args{tid: "1", mark: "done"},
+			want: want{err: testkit.ErrDummy},
+		},
+		{
+			name: "blocked forced",
+			args: args{tid: "1", mark: "progress", opts: usecases.MarkOptions{Force: true}},
+			want: want{
+				task: &domain.Task{
+					ID:        1,
+					Status:    domain.StatusProgress,
+					UpdatedAt: time.Now().Truncate(time.Minute),
+					BlockedBy: []uint64{5, 6},
+					History:   marked(domain.StatusTodo, domain.StatusProgress),
+				},
+				updated: []uint64{1},
+			},
+		},
+		{
+			name: "blocked back to todo",
+			args: args{tid: "1", mark: "todo"},
+			want: want{
+				task: &domain.Task{
+					ID:        1,
+					Status:    domain.StatusTodo,
+					UpdatedAt: time.Now().Truncate(time.Minute),
+					BlockedBy: []uint64{5, 6},
+				},
+				updated: []uint64{1},
+			},
+		},
+		{
+			name: "blocked by cancelled and deleted",
+			args: args{tid: "1", mark: "progress"},
+			want: want{
+				task: &domain.Task{
+					ID:        1,
+					Status:    domain.StatusProgress,
+					UpdatedAt: time.Now().Truncate(time.Minute),
+					BlockedBy: []uint64{5, 6},
+					History:   marked(domain.StatusTodo, domain.StatusProgress),
+				},
+				updated: []uint64{1},
+			},
+		},
+		{
+			name: "blocked by done and deleted",
+			args: args{tid: "1", mark: "done"},
+			want: want{
+				task: &domain.Task{
+					ID:        1,
+					Status:    domain.StatusDone,
+					UpdatedAt: time.Now().Truncate(time.Minute),
+					BlockedBy: []uint64{5, 6},
+					History:   marked(domain.StatusTodo, domain.StatusDone),
+				},
+				updated: []uint64{1},
+			},
 		},
 	}
 
@@ -328,29 +700,78 @@ func TestUnitUseCasesMarkTask(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
+			var updated []uint64
+
+			// 1 is the root task with 2 (having 3) and 4 subtasks
+			tree := map[uint64]*domain.Task{
+				1: {ID: 1, Status: domain.StatusTodo},
+				2: {ID: 2, Status: domain.StatusTodo, ParentID: 1},
+				3: {ID: 3, Status: domain.StatusTodo, ParentID: 2},
+				4: {ID: 4, Status: domain.StatusTodo, ParentID: 1},
+			}
+
+			if test.name == "close parents" {
+				tree[4].Status = domain.StatusDone
+			}
+
 			stor := new(storage.Mock)
 
 			stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
-				switch test.name {
-				case taskNotFoundTest:
+				switch {
+				case test.name == taskNotFoundTest:
 					return nil, domain.ErrTaskNotFound
-				case "task is done":
+				case test.name == "task is done":
 					return &domain.Task{ID: 1, Status: domain.StatusDone}, nil
+				case test.name == "close parents failure" && tid != 3:
+					return nil, testkit.ErrDummy
+				case strings.HasPrefix(test.name, "close parents"):
+					task := *tree[tid]
+
+					return &task, nil
+				case strings.HasPrefix(test.name, "blocked"):
+					return blockedTask(test.name, tid)
 				}
 
 				return &domain.Task{ID: 1, Status: domain.StatusTodo}, nil
 			}
+			stor.ListChildrenFunc = func(ctx context.Context, parentID uint64) ([]*domain.Task, error) {
+				switch test.name {
+				case "subtasks failure":
+					return nil, testkit.ErrDummy
+				case "open subtasks", "open subtasks forced":
+					return []*domain.Task{{ID: 2, Status: domain.StatusTodo, ParentID: 1}}, nil
+				}
+
+				children := make([]*domain.Task, 0)
+				if !strings.HasPrefix(test.name, "close parents") {
+					return children, nil
+				}
+
+				for _, task := range tree {
+					if task.ParentID == parentID {
+						children = append(children, task)
+					}
+				}
+
+				return children, nil
+			}
 			stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
 				if test.name == testkit.FailureTest {
 					return testkit.ErrDummy
 				}
 
+				if saved, ok := tree[task.ID]; ok {
+					saved.Status = task.Status
+				}
+
+				updated = append(updated, task.ID)
+
 				return nil
 			}
 
 			ctx := t.Context()
-			use := usecases.New(stor)
-			got, err := use.MarkTask(ctx, test.args.tid, test.args.mark)
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.MarkTask(ctx, test.args.tid, test.args.mark, test.args.opts)
 
 			if !errors.Is(err, test.want.err) {
 				t.Fatalf("MarkTask() error = %v, want = %v", err, test.want.err)
@@ -358,25 +779,37 @@ func TestUnitUseCasesMarkTask(t *testing.T) {
 
 			if got != nil {
 				got.UpdatedAt = got.UpdatedAt.Truncate(time.Minute)
+				truncateHistory(got)
 			}
 
 			if !reflect.DeepEqual(got, test.want.task) {
 				t.Errorf("MarkTask() got = %v, want = %v", got, test.want.task)
 			}
+
+			if err == nil && !slices.Equal(updated, test.want.updated) {
+				t.Errorf("MarkTask() got = %v, want = %v", updated, test.want.updated)
+			}
 		})
 	}
 }
 
-func TestUnitUseCasesListTasks(t *testing.T) {
+func TestUnitUseCasesMarkTaskWorkflow(t *testing.T) {
 	t.Parallel()
 
+	flow, _ := domain.NewWorkflow(
+		[]string{"todo", "review", "done", "cancelled"},
+		[]string{"done", "cancelled"},
+		map[string][]string{"todo": {"review", "cancelled"}, "review": {"todo", "done"}},
+	)
+
 	type args struct {
-		params usecases.ListParams
+		status domain.Status
+		mark   string
 	}
 
 	type want struct {
-		tasks []*domain.Task
-		err   error
+		status domain.Status
+		err    error
 	}
 
 	tests := []struct {
@@ -384,41 +817,15 @@ func TestUnitUseCasesListTasks(t *testing.T) {
 		args args
 		want want
 	}{
+		{name: "allowed", args: args{status: "todo", mark: "review"}, want: want{status: "review"}},
+		{name: "stay", args: args{status: "review", mark: "review"}, want: want{status: "review"}},
+		{name: "terminal", args: args{status: "todo", mark: "cancelled"}, want: want{status: "cancelled"}},
+		{name: "unknown", args: args{status: "todo", mark: "progress"}, want: want{err: domain.ErrInvalidStatus}},
+		{name: "closed", args: args{status: "cancelled", mark: "todo"}, want: want{err: domain.ErrTaskAlreadyDone}},
 		{
-			name: "invalid status",
-			args: args{params: usecases.ListParams{Status: "invalid"}},
-			want: want{err: domain.ErrInvalidStatus},
-		},
-		{
-			name: "list all success",
-			args: args{params: usecases.ListParams{Status: ""}},
-			want: want{tasks: []*domain.Task{
-				{ID: 1, Status: domain.StatusDone},
-				{ID: 2, Status: domain.StatusTodo},
-			}},
-		},
-		{
-			name: "list all failure",
-			args: args{params: usecases.ListParams{Status: ""}},
-			want: want{err: testkit.ErrDummy},
-		},
-		{
-			name: "list by status success",
-			args: args{params: usecases.ListParams{Status: "todo"}},
-			want: want{tasks: []*domain.Task{
-				{ID: 1, Status: domain.StatusTodo},
-				{ID: 2, Status: domain.StatusTodo},
-			}},
-		},
-		{
-			name: "list by status failure",
-			args: args{params: usecases.ListParams{Status: "done"}},
-			want: want{err: testkit.ErrDummy},
-		},
-		{
-			name: "empty list",
-			args: args{params: usecases.ListParams{Status: ""}},
-			want: want{err: domain.ErrEmptyTasks},
+			name: "not allowed",
+			args: args{status: "todo", mark: "done"},
+			want: want{err: &domain.TransitionError{From: "todo", To: "done", Allowed: []domain.Status{"review", "cancelled"}}},
 		},
 	}
 
@@ -428,33 +835,723 @@ func TestUnitUseCasesListTasks(t *testing.T) {
 
 			stor := new(storage.Mock)
 
-			stor.ListAllFunc = func(ctx context.Context) ([]*domain.Task, error) {
-				switch test.name {
-				case "empty list":
-					return make([]*domain.Task, 0), nil
-				case "list all failure":
-					return nil, testkit.ErrDummy
-				}
-
-				return []*domain.Task{{ID: 1, Status: domain.StatusDone}, {ID: 2, Status: domain.StatusTodo}}, nil
+			stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+				return &domain.Task{ID: tid, Status: test.args.status}, nil
+			}
+			stor.ListChildrenFunc = func(ctx context.Context, parentID uint64) ([]*domain.Task, error) {
+				return []*domain.Task{{ID: 2, Status: "cancelled", ParentID: parentID}}, nil
+			}
+			stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
+				return nil
 			}
-			stor.ListByStatusFunc = func(ctx context.Context, status domain.Status) ([]*domain.Task, error) {
-				if test.name == "list by status failure" {
-					return nil, testkit.ErrDummy
-				}
 
-				if status != domain.StatusTodo {
-					panic("invalid status for mock")
+			ctx := t.Context()
+			use := usecases.New(usecases.Config{Storage: stor, Workflow: flow})
+			got, err := use.MarkTask(ctx, "1", test.args.mark, usecases.MarkOptions{})
+
+			var transition *domain.TransitionError
+			if errors.As(test.want.err, &transition) {
+				if !errors.As(err, &transition) || !reflect.DeepEqual(transition, test.want.err) {
+					t.Fatalf("MarkTask() error = %v, want = %v", err, test.want.err)
 				}
 
-				return []*domain.Task{
-					{ID: 1, Status: domain.StatusTodo},
+				return
+			}
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("MarkTask() error = %v, want = %v", err, test.want.err)
+			}
+
+			if got != nil && got.Status != test.want.status {
+				t.Errorf("MarkTask() got = %v, want = %v", got.Status, test.want.status)
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesMarkTaskCompleted(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		workflow string
+		before   domain.Status
+	}{
+		{name: "valid", workflow: "valid.json", before: "review"},
+		{name: "without done", workflow: "shipping.json", before: "building"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			flow, err := config.LoadWorkflow(config.Path("test", "data", "workflow", test.workflow))
+			if err != nil {
+				t.Fatalf("LoadWorkflow() error = %v, want = %v", err, nil)
+			}
+
+			tasks := map[uint64]*domain.Task{
+				1: {ID: 1, Status: flow.Initial()},
+				2: {ID: 2, Status: test.before, ParentID: 1, Recurrence: "daily"},
+			}
+
+			var spawned *domain.Task
+
+			stor := new(storage.Mock)
+
+			stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+				task := *tasks[tid]
+
+				return &task, nil
+			}
+			stor.ListChildrenFunc = func(ctx context.Context, parentID uint64) ([]*domain.Task, error) {
+				if parentID == 1 {
+					return []*domain.Task{tasks[2]}, nil
+				}
+
+				return make([]*domain.Task, 0), nil
+			}
+			stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
+				tasks[task.ID] = task
+
+				return nil
+			}
+			stor.SaveTaskFunc = func(ctx context.Context, task *domain.Task) (*domain.Task, error) {
+				spawned = task
+
+				return task, nil
+			}
+
+			ctx := t.Context()
+			use := usecases.New(usecases.Config{Storage: stor, Workflow: flow})
+
+			_, err = use.MarkTask(ctx, "2", string(flow.Completed()), usecases.MarkOptions{CloseParent: true})
+			if err != nil {
+				t.Fatalf("MarkTask() error = %v, want = %v", err, nil)
+			}
+
+			if spawned == nil || spawned.Status != flow.Initial() {
+				t.Errorf("MarkTask() got = %v, want = %v", spawned, flow.Initial())
+			}
+
+			if got := tasks[1].Status; got != flow.Completed() {
+				t.Errorf("MarkTask() got = %v, want = %v", got, flow.Completed())
+			}
+		})
+	}
+}
+
+// blockedTask is the storage of task 1 blocked by task 5 and deleted task 6.
+func blockedTask(testName string, tid uint64) (*domain.Task, error) {
+	switch {
+	case tid == 1:
+		return &domain.Task{ID: 1, Status: domain.StatusTodo, BlockedBy: []uint64{5, 6}}, nil
+	case testName == "blocked failure":
+		return nil, testkit.ErrDummy
+	case tid == 6:
+		return nil, domain.ErrTaskNotFound
+	case testName == "blocked by done and deleted":
+		return &domain.Task{ID: 5, Status: domain.StatusDone}, nil
+	case testName == "blocked by cancelled and deleted":
+		return &domain.Task{ID: 5, Status: domain.StatusCancelled}, nil
+	}
+
+	return &domain.Task{ID: 5, Status: domain.StatusTodo}, nil
+}
+
+func TestUnitUseCasesPrioritizeTask(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		tid   string
+		level string
+	}
+
+	type want struct {
+		task *domain.Task
+		err  error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "invalid taskID",
+			args: args{tid: "invalid", level: "high"},
+			want: want{err: domain.ErrInvalidTaskID},
+		},
+		{
+			name: "invalid priority",
+			args: args{tid: "1", level: "urgent"},
+			want: want{err: domain.ErrInvalidPriority},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{tid: "1", level: "high"},
+			want: want{err: domain.ErrTaskNotFound},
+		},
+		{
+			name: testkit.FailureTest,
+			args: args{tid: "1", level: "high"},
+			want: want{err: testkit.ErrDummy},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{tid: "1", level: "high"},
+			want: want{task: &domain.Task{
+				ID:        1,
+				Status:    domain.StatusTodo,
+				Priority:  domain.PriorityHigh,
+				UpdatedAt: time.Now().Truncate(time.Minute),
+				History: []domain.Change{
+					{At: time.Now().Truncate(time.Minute), Field: "priority", Old: "low", New: "high"},
+				},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+				if test.name == taskNotFoundTest {
+					return nil, domain.ErrTaskNotFound
+				}
+
+				return &domain.Task{ID: 1, Status: domain.StatusTodo, Priority: domain.PriorityLow}, nil
+			}
+			stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
+				if test.name == testkit.FailureTest {
+					return testkit.ErrDummy
+				}
+
+				return nil
+			}
+
+			ctx := t.Context()
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.PrioritizeTask(ctx, test.args.tid, test.args.level)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("PrioritizeTask() error = %v, want = %v", err, test.want.err)
+			}
+
+			if got != nil {
+				got.UpdatedAt = got.UpdatedAt.Truncate(time.Minute)
+				truncateHistory(got)
+			}
+
+			if !reflect.DeepEqual(got, test.want.task) {
+				t.Errorf("PrioritizeTask() got = %v, want = %v", got, test.want.task)
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesDueTask(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		tid  string
+		when string
+	}
+
+	type want struct {
+		task *domain.Task
+		err  error
+	}
+
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	endOfTomorrow := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 23, 59, 59, 0, time.Local)
+	now := time.Now().Truncate(time.Minute)
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "invalid taskID",
+			args: args{tid: "invalid", when: "today"},
+			want: want{err: domain.ErrInvalidTaskID},
+		},
+		{
+			name: "invalid date",
+			args: args{tid: "1", when: "someday"},
+			want: want{err: domain.ErrInvalidDate},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{tid: "1", when: "today"},
+			want: want{err: domain.ErrTaskNotFound},
+		},
+		{
+			name: testkit.FailureTest,
+			args: args{tid: "1", when: "today"},
+			want: want{err: testkit.ErrDummy},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{tid: "1", when: "tomorrow"},
+			want: want{task: &domain.Task{
+				ID:        1,
+				Status:    domain.StatusTodo,
+				UpdatedAt: time.Now().Truncate(time.Minute),
+				DueAt:     endOfTomorrow,
+				History: []domain.Change{{
+					At:    now,
+					Field: "due",
+					Old:   now.Format(time.DateTime),
+					New:   endOfTomorrow.Format(time.DateTime),
+				}},
+			}},
+		},
+		{
+			name: "remove due",
+			args: args{tid: "1", when: "none"},
+			want: want{task: &domain.Task{
+				ID:        1,
+				Status:    domain.StatusTodo,
+				UpdatedAt: time.Now().Truncate(time.Minute),
+				History:   []domain.Change{{At: now, Field: "due", Old: now.Format(time.DateTime)}},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+				if test.name == taskNotFoundTest {
+					return nil, domain.ErrTaskNotFound
+				}
+
+				return &domain.Task{ID: 1, Status: domain.StatusTodo, DueAt: now}, nil
+			}
+			stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
+				if test.name == testkit.FailureTest {
+					return testkit.ErrDummy
+				}
+
+				return nil
+			}
+
+			ctx := t.Context()
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.DueTask(ctx, test.args.tid, test.args.when)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("DueTask() error = %v, want = %v", err, test.want.err)
+			}
+
+			if got != nil {
+				got.UpdatedAt = got.UpdatedAt.Truncate(time.Minute)
+				truncateHistory(got)
+			}
+
+			if !reflect.DeepEqual(got, test.want.task) {
+				t.Errorf("DueTask() got = %v, want = %v", got, test.want.task)
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesTagTask(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		tid    string
+		add    []string
+		remove []string
+	}
+
+	type want struct {
+		task *domain.Task
+		err  error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "invalid taskID",
+			args: args{tid: "invalid", add: []string{"infra"}},
+			want: want{err: domain.ErrInvalidTaskID},
+		},
+		{
+			name: "invalid added tag",
+			args: args{tid: "1", add: []string{"1st"}},
+			want: want{err: domain.ErrInvalidTag},
+		},
+		{
+			name: "invalid removed tag",
+			args: args{tid: "1", remove: []string{"on call"}},
+			want: want{err: domain.ErrInvalidTag},
+		},
+		{
+			name: taskNotFoundTest,
+			args: args{tid: "1", add: []string{"infra"}},
+			want: want{err: domain.ErrTaskNotFound},
+		},
+		{
+			name: testkit.FailureTest,
+			args: args{tid: "1", add: []string{"infra"}},
+			want: want{err: testkit.ErrDummy},
+		},
+		{
+			name: testkit.SuccessTest,
+			args: args{tid: "1", add: []string{"Infra", "api"}, remove: []string{"backend", "unknown"}},
+			want: want{task: &domain.Task{
+				ID:        1,
+				Status:    domain.StatusTodo,
+				UpdatedAt: time.Now().Truncate(time.Minute),
+				Tags:      []string{"api", "infra", "oncall"},
+				History: []domain.Change{{
+					At:    time.Now().Truncate(time.Minute),
+					Field: "tags",
+					Old:   "backend oncall",
+					New:   "api infra oncall",
+				}},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.GetByIDFunc = func(ctx context.Context, tid uint64) (*domain.Task, error) {
+				if test.name == taskNotFoundTest {
+					return nil, domain.ErrTaskNotFound
+				}
+
+				return &domain.Task{ID: 1, Status: domain.StatusTodo, Tags: []string{"backend", "oncall"}}, nil
+			}
+			stor.UpdateTaskFunc = func(ctx context.Context, task *domain.Task) error {
+				if test.name == testkit.FailureTest {
+					return testkit.ErrDummy
+				}
+
+				return nil
+			}
+
+			ctx := t.Context()
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.TagTask(ctx, test.args.tid, test.args.add, test.args.remove)
+
+			if !errors.Is(err, test.want.err) {
+				t.Fatalf("TagTask() error = %v, want = %v", err, test.want.err)
+			}
+
+			if got != nil {
+				got.UpdatedAt = got.UpdatedAt.Truncate(time.Minute)
+				truncateHistory(got)
+			}
+
+			if !reflect.DeepEqual(got, test.want.task) {
+				t.Errorf("TagTask() got = %v, want = %v", got, test.want.task)
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesListTasks(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		params usecases.ListParams
+	}
+
+	type want struct {
+		tasks []*domain.Task
+		err   error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "invalid status",
+			args: args{params: usecases.ListParams{Status: "invalid"}},
+			want: want{err: domain.ErrInvalidStatus},
+		},
+		{
+			name: "list all success",
+			args: args{params: usecases.ListParams{Status: ""}},
+			want: want{tasks: []*domain.Task{
+				{ID: 1, Status: domain.StatusDone},
+				{ID: 2, Status: domain.StatusTodo},
+			}},
+		},
+		{
+			name: "list all failure",
+			args: args{params: usecases.ListParams{Status: ""}},
+			want: want{err: testkit.ErrDummy},
+		},
+		{
+			name: "list by status success",
+			args: args{params: usecases.ListParams{Status: "todo"}},
+			want: want{tasks: []*domain.Task{
+				{ID: 1, Status: domain.StatusTodo},
+				{ID: 2, Status: domain.StatusTodo},
+			}},
+		},
+		{
+			name: "list by status failure",
+			args: args{params: usecases.ListParams{Status: "done"}},
+			want: want{err: testkit.ErrDummy},
+		},
+		{
+			name: "empty list",
+			args: args{params: usecases.ListParams{Status: ""}},
+			want: want{err: domain.ErrEmptyTasks},
+		},
+		{
+			name: "invalid priority",
+			args: args{params: usecases.ListParams{Priority: "urgent"}},
+			want: want{err: domain.ErrInvalidPriority},
+		},
+		{
+			name: "filter by priority",
+			args: args{params: usecases.ListParams{Priority: "high"}},
+			want: want{tasks: []*domain.Task{
+				{ID: 2, Status: domain.StatusTodo, Priority: domain.PriorityHigh},
+			}},
+		},
+		{
+			name: "empty filter by priority",
+			args: args{params: usecases.ListParams{Priority: "low"}},
+			want: want{err: domain.ErrEmptyTasks},
+		},
+		{
+			name: "invalid due before",
+			args: args{params: usecases.ListParams{DueBefore: "someday"}},
+			want: want{err: domain.ErrInvalidDate},
+		},
+		{
+			name: "overdue",
+			args: args{params: usecases.ListParams{Overdue: true}},
+			want: want{tasks: []*domain.Task{
+				{ID: 2, Status: domain.StatusTodo, DueAt: time.Unix(1, 0)},
+			}},
+		},
+		{
+			name: "due before",
+			args: args{params: usecases.ListParams{DueBefore: "2000-01-01"}},
+			want: want{tasks: []*domain.Task{
+				{ID: 1, Status: domain.StatusDone, DueAt: time.Unix(1, 0)},
+				{ID: 2, Status: domain.StatusTodo, DueAt: time.Unix(1, 0)},
+			}},
+		},
+		{
+			name: "priority first",
+			args: args{params: usecases.ListParams{PriorityFirst: true}},
+			want: want{tasks: []*domain.Task{
+				{ID: 2, Status: domain.StatusTodo, Priority: domain.PriorityHigh},
+				{ID: 3, Status: domain.StatusTodo, Priority: domain.PriorityMedium, UpdatedAt: time.Unix(2, 0)},
+				{ID: 1, Status: domain.StatusDone, Priority: domain.PriorityMedium, UpdatedAt: time.Unix(1, 0)},
+			}},
+		},
+		{
+			name: "invalid tag",
+			args: args{params: usecases.ListParams{Tags: []string{"back end"}}},
+			want: want{err: domain.ErrInvalidTag},
+		},
+		{
+			name: "invalid excluded tag",
+			args: args{params: usecases.ListParams{ExcludeTags: []string{"+"}}},
+			want: want{err: domain.ErrInvalidTag},
+		},
+		{
+			name: "filter by tags",
+			args: args{params: usecases.ListParams{Tags: []string{"+Backend"}, ExcludeTags: []string{"infra"}}},
+			want: want{tasks: []*domain.Task{
+				{ID: 1, Status: domain.StatusDone, Tags: []string{"backend"}},
+				{ID: 2, Status: domain.StatusTodo, Tags: []string{"backend"}},
+			}},
+		},
+		{
+			name: "filter by tags and status",
+			args: args{params: usecases.ListParams{Status: "todo", Tags: []string{"backend"}}},
+			want: want{tasks: []*domain.Task{
+				{ID: 2, Status: domain.StatusTodo, Tags: []string{"backend"}},
+			}},
+		},
+		{
+			name: "filter by tags failure",
+			args: args{params: usecases.ListParams{Tags: []string{"backend"}}},
+			want: want{err: testkit.ErrDummy},
+		},
+		{
+			name: "invalid project",
+			args: args{params: usecases.ListParams{Project: "platform."}},
+			want: want{err: domain.ErrInvalidProject},
+		},
+		{
+			name: "filter by project",
+			args: args{params: usecases.ListParams{Project: "platform"}},
+			want: want{tasks: []*domain.Task{
+				{ID: 1, Status: domain.StatusTodo, Project: "platform"},
+				{ID: 2, Status: domain.StatusTodo, Project: "platform.auth"},
+			}},
+		},
+		{
+			name: "ready",
+			args: args{params: usecases.ListParams{Ready: true}},
+			want: want{tasks: []*domain.Task{
+				{ID: 2, Status: domain.StatusTodo, BlockedBy: []uint64{1}},
+				{ID: 5, Status: domain.StatusTodo, BlockedBy: []uint64{99}},
+				{ID: 7, Status: domain.StatusTodo, BlockedBy: []uint64{6}},
+			}},
+		},
+		{
+			name: "ready failure",
+			args: args{params: usecases.ListParams{Status: "todo", Ready: true}},
+			want: want{err: testkit.ErrDummy},
+		},
+		{
+			name: "filter by project and tags",
+			args: args{params: usecases.ListParams{Project: "platform.auth", Tags: []string{"backend"}}},
+			want: want{err: domain.ErrEmptyTasks},
+		},
+		{
+			name: "invalid sort",
+			args: args{params: usecases.ListParams{Sort: "title"}},
+			want: want{err: domain.ErrInvalidSort},
+		},
+		{
+			name: "invalid sort order",
+			args: args{params: usecases.ListParams{Sort: "id:up"}},
+			want: want{err: domain.ErrInvalidSort},
+		},
+		{
+			name: "invalid limit",
+			args: args{params: usecases.ListParams{Limit: "0"}},
+			want: want{err: domain.ErrInvalidLimit},
+		},
+		{
+			name: "invalid offset",
+			args: args{params: usecases.ListParams{Offset: "-1"}},
+			want: want{err: domain.ErrInvalidOffset},
+		},
+		{
+			name: "sorted page",
+			args: args{params: usecases.ListParams{Sort: "id:DESC", Limit: "1", Offset: "1"}},
+			want: want{tasks: []*domain.Task{{ID: 1, Status: domain.StatusDone}}},
+		},
+		{
+			name: "reversed",
+			args: args{params: usecases.ListParams{Reverse: true}},
+			want: want{tasks: []*domain.Task{{ID: 2, Status: domain.StatusTodo}, {ID: 1, Status: domain.StatusDone}}},
+		},
+		{
+			name: "filtered page",
+			args: args{params: usecases.ListParams{Priority: "medium", Sort: "updated", Limit: "1"}},
+			want: want{tasks: []*domain.Task{
+				{ID: 1, Status: domain.StatusDone, Priority: domain.PriorityMedium, UpdatedAt: time.Unix(1, 0)},
+			}},
+		},
+		{
+			name: "empty page",
+			args: args{params: usecases.ListParams{Offset: "2"}},
+			want: want{err: domain.ErrEmptyTasks},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.ListAllFunc = func(ctx context.Context) ([]*domain.Task, error) {
+				switch test.name {
+				case "empty list":
+					return make([]*domain.Task, 0), nil
+				case "list all failure", "ready failure":
+					return nil, testkit.ErrDummy
+				case "ready":
+					return dependentTasks(), nil
+				case "overdue", "due before":
+					return []*domain.Task{
+						{ID: 1, Status: domain.StatusDone, DueAt: time.Unix(1, 0)},
+						{ID: 2, Status: domain.StatusTodo, DueAt: time.Unix(1, 0)},
+						{ID: 3, Status: domain.StatusTodo, DueAt: time.Now().Add(time.Hour)},
+						{ID: 4, Status: domain.StatusTodo},
+					}, nil
+				case "filter by priority", "empty filter by priority", "priority first", "filtered page":
+					return []*domain.Task{
+						{ID: 1, Status: domain.StatusDone, Priority: domain.PriorityMedium, UpdatedAt: time.Unix(1, 0)},
+						{ID: 2, Status: domain.StatusTodo, Priority: domain.PriorityHigh},
+						{ID: 3, Status: domain.StatusTodo, Priority: domain.PriorityMedium, UpdatedAt: time.Unix(2, 0)},
+					}, nil
+				}
+
+				return []*domain.Task{{ID: 1, Status: domain.StatusDone}, {ID: 2, Status: domain.StatusTodo}}, nil
+			}
+			stor.ListFunc = func(ctx context.Context, opts domain.ListOptions) ([]*domain.Task, error) {
+				tasks, err := stor.ListAll(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				return opts.Apply(tasks), nil
+			}
+			stor.ListByStatusFunc = func(ctx context.Context, status domain.Status) ([]*domain.Task, error) {
+				if test.name == "list by status failure" {
+					return nil, testkit.ErrDummy
+				}
+
+				if status != domain.StatusTodo {
+					panic("invalid status for mock")
+				}
+
+				return []*domain.Task{
+					{ID: 1, Status: domain.StatusTodo},
 					{ID: 2, Status: domain.StatusTodo},
 				}, nil
 			}
+			stor.ListByTagsFunc = func(ctx context.Context, query domain.TagQuery) ([]*domain.Task, error) {
+				if test.name == "filter by tags failure" {
+					return nil, testkit.ErrDummy
+				}
+
+				if !slices.Equal(query.Include, []string{"backend"}) {
+					panic("invalid tags for mock")
+				}
+
+				return []*domain.Task{
+					{ID: 1, Status: domain.StatusDone, Tags: []string{"backend"}},
+					{ID: 2, Status: domain.StatusTodo, Tags: []string{"backend"}},
+				}, nil
+			}
+			stor.ListByProjectFunc = func(ctx context.Context, project string) ([]*domain.Task, error) {
+				if project != "platform" {
+					panic("invalid project for mock")
+				}
+
+				return []*domain.Task{
+					{ID: 1, Status: domain.StatusTodo, Project: "platform"},
+					{ID: 2, Status: domain.StatusTodo, Project: "platform.auth"},
+				}, nil
+			}
 
 			ctx := t.Context()
-			use := usecases.New(stor)
+			use := usecases.New(usecases.Config{Storage: stor})
 			got, err := use.ListTasks(ctx, test.args.params)
 
 			if !errors.Is(err, test.want.err) {
@@ -467,3 +1564,150 @@ func TestUnitUseCasesListTasks(t *testing.T) {
 		})
 	}
 }
+
+func TestUnitUseCasesListProjects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want []*usecases.ProjectStats
+		err  error
+	}{
+		{name: testkit.FailureTest, err: testkit.ErrDummy},
+		{name: "empty list", err: domain.ErrEmptyTasks},
+		{
+			name: testkit.SuccessTest,
+			want: []*usecases.ProjectStats{
+				{Project: "billing", Depth: 0, Counts: map[domain.Status]int{domain.StatusDone: 1}},
+				{Project: "platform", Depth: 0, Counts: map[domain.Status]int{domain.StatusTodo: 2, domain.StatusDone: 1}},
+				{Project: "platform.auth", Depth: 1, Counts: map[domain.Status]int{domain.StatusTodo: 1}},
+				{Project: "platform.auth.sso", Depth: 2, Counts: map[domain.Status]int{domain.StatusTodo: 1}},
+				{Project: "platform.db", Depth: 1, Counts: map[domain.Status]int{domain.StatusDone: 1}},
+				{Project: "", Depth: 0, Counts: map[domain.Status]int{domain.StatusProgress: 1}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.ListAllFunc = func(ctx context.Context) ([]*domain.Task, error) {
+				switch test.name {
+				case testkit.FailureTest:
+					return nil, testkit.ErrDummy
+				case "empty list":
+					return make([]*domain.Task, 0), nil
+				}
+
+				return []*domain.Task{
+					{ID: 1, Status: domain.StatusTodo, Project: "platform.auth.sso"},
+					{ID: 2, Status: domain.StatusDone, Project: "platform.db"},
+					{ID: 3, Status: domain.StatusProgress},
+					{ID: 4, Status: domain.StatusTodo, Project: "platform"},
+					{ID: 5, Status: domain.StatusDone, Project: "billing"},
+				}, nil
+			}
+
+			ctx := t.Context()
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.ListProjects(ctx)
+
+			if !errors.Is(err, test.err) {
+				t.Fatalf("ListProjects() error = %v, want = %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ListProjects() got = %v, want = %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesCheckSchema(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want error
+	}{
+		{name: testkit.FailureTest, want: testkit.ErrDummy},
+		{name: testkit.SuccessTest, want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.SchemaVersionFunc = func(ctx context.Context) (domain.Schema, error) {
+				if test.name == testkit.FailureTest {
+					return domain.Schema{}, testkit.ErrDummy
+				}
+
+				return domain.Schema{Version: 1, Latest: 2}, nil
+			}
+
+			ctx := t.Context()
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.CheckSchema(ctx)
+
+			if !errors.Is(err, test.want) {
+				t.Fatalf("CheckSchema() error = %v, want = %v", err, test.want)
+			}
+
+			if want := (domain.Schema{Version: 1, Latest: 2}); err == nil && got != want {
+				t.Errorf("CheckSchema() got = %v, want = %v", got, want)
+			}
+		})
+	}
+}
+
+func TestUnitUseCasesMigrateSchema(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want error
+	}{
+		{name: testkit.FailureTest, want: testkit.ErrDummy},
+		{name: testkit.SuccessTest, want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stor := new(storage.Mock)
+
+			stor.MigrateFunc = func(ctx context.Context) (domain.Schema, error) {
+				if test.name == testkit.FailureTest {
+					return domain.Schema{}, testkit.ErrDummy
+				}
+
+				return domain.Schema{Version: 1, Latest: 2}, nil
+			}
+
+			ctx := t.Context()
+			use := usecases.New(usecases.Config{Storage: stor})
+			got, err := use.MigrateSchema(ctx)
+
+			if !errors.Is(err, test.want) {
+				t.Fatalf("MigrateSchema() error = %v, want = %v", err, test.want)
+			}
+
+			if want := (domain.Schema{Version: 1, Latest: 2}); err == nil && got != want {
+				t.Errorf("MigrateSchema() got = %v, want = %v", got, want)
+			}
+		})
+	}
+}
+
+func truncateHistory(task *domain.Task) {
+	for idx := range task.History {
+		task.History[idx].At = task.History[idx].At.Truncate(time.Minute)
+	}
+}
@@ -3,6 +3,7 @@ package usecases
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/therenotomorrow/tasker/internal/domain"
 )
@@ -27,10 +28,92 @@ func (use *UseCases) validateTaskID(tid string) (uint64, error) {
 }
 
 func (use *UseCases) validateStatus(status string) (domain.Status, error) {
-	stat, err := domain.NewStatus(status)
+	stat, err := use.workflow.Status(status)
 	if err != nil {
 		return "", domain.ErrInvalidStatus
 	}
 
 	return stat, nil
 }
+
+func (use *UseCases) validatePriority(priority string) (domain.Priority, error) {
+	prio, err := domain.NewPriority(priority)
+	if err != nil {
+		return "", domain.ErrInvalidPriority
+	}
+
+	return prio, nil
+}
+
+func (use *UseCases) validateDate(date string) (time.Time, error) {
+	when, err := ParseDate(date, time.Now())
+	if err != nil {
+		return time.Time{}, domain.ErrInvalidDate
+	}
+
+	return when, nil
+}
+
+func (use *UseCases) validateTags(tags []string) ([]string, error) {
+	normalized, err := domain.NewTags(tags)
+	if err != nil {
+		return nil, domain.ErrInvalidTag
+	}
+
+	return normalized, nil
+}
+
+func (use *UseCases) validateProject(project string) (string, error) {
+	proj, err := domain.NewProject(project)
+	if err != nil {
+		return "", domain.ErrInvalidProject
+	}
+
+	return proj, nil
+}
+
+func (use *UseCases) validateRecurrence(rule string) (domain.Recurrence, error) {
+	recurrence, err := domain.NewRecurrence(rule)
+	if err != nil {
+		return "", domain.ErrInvalidRecurrence
+	}
+
+	return recurrence, nil
+}
+
+// validateSort understands the "field", "field:asc" and "field:desc" orders.
+func (use *UseCases) validateSort(sort string) (domain.SortField, bool, error) {
+	raw, order, _ := strings.Cut(sort, ":")
+
+	field, err := domain.NewSortField(raw)
+	if err != nil {
+		return "", false, domain.ErrInvalidSort
+	}
+
+	switch strings.ToLower(order) {
+	case "", "asc":
+		return field, false, nil
+	case "desc":
+		return field, true, nil
+	default:
+		return "", false, domain.ErrInvalidSort
+	}
+}
+
+func (use *UseCases) validateLimit(limit string) (int, error) {
+	number, err := strconv.Atoi(limit)
+	if err != nil || number < 1 {
+		return 0, domain.ErrInvalidLimit
+	}
+
+	return number, nil
+}
+
+func (use *UseCases) validateOffset(offset string) (int, error) {
+	number, err := strconv.Atoi(offset)
+	if err != nil || number < 0 {
+		return 0, domain.ErrInvalidOffset
+	}
+
+	return number, nil
+}
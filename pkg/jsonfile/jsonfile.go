@@ -1,24 +1,34 @@
 package jsonfile
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 const (
 	name = "JSONFile"
 
-	defaultFilePerm = 0o600
+	defaultFilePerm    = 0o600
+	defaultLockTimeout = 5 * time.Second
+	lockRetryInterval  = 10 * time.Millisecond
+	lockFileExt        = ".lock"
 )
 
-var ErrFileIsNotJSON = errors.New("file is not *.json")
+var (
+	ErrFileIsNotJSON   = errors.New("file is not *.json")
+	ErrLockTimeout     = errors.New("lock timeout")
+	ErrLockUnsupported = errors.New("lock unsupported")
+)
 
 type Config struct {
-	File     string
-	TestHook func(file *os.File)
+	File        string
+	LockTimeout time.Duration
+	TestHook    func(file *os.File)
 }
 
 type JSONFile[T any] struct {
@@ -56,24 +66,178 @@ func (fs *JSONFile[T]) Load() (T, error) {
 }
 
 func (fs *JSONFile[T]) Save(data T) error {
-	file, err := os.Create(fs.filename)
+	mode, err := fs.writable()
+	if err != nil {
+		return err
+	}
+
+	// write a sibling temporary file and rename it over the target,
+	// so crash in the middle of saving never leaves truncated data behind
+	dir, base := filepath.Split(fs.filename)
+	dir = filepath.Clean(dir)
+
+	temp, err := os.CreateTemp(dir, base+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("%s save error: %w", name, err)
+	}
+
+	// remove is a no-op once the temporary file has been renamed
+	defer func() { _ = os.Remove(temp.Name()) }()
+
+	err = fs.write(temp, data, mode)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(temp.Name(), fs.filename)
 	if err != nil {
 		return fmt.Errorf("%s save error: %w", name, err)
 	}
 
+	return syncDir(dir)
+}
+
+// refuse to replace the file that could not be written in place
+// and keep its permissions for the replacement.
+func (fs *JSONFile[T]) writable() (os.FileMode, error) {
+	file, err := os.OpenFile(fs.filename, os.O_WRONLY, 0)
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return defaultFilePerm, nil
+	case err != nil:
+		return 0, fmt.Errorf("%s save error: %w", name, err)
+	}
+
 	defer func() { _ = file.Close() }()
 
-	enc := json.NewEncoder(file)
+	info, err := file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("%s save error: %w", name, err)
+	}
+
+	return info.Mode().Perm(), nil
+}
+
+func (fs *JSONFile[T]) write(temp *os.File, data T, mode os.FileMode) error {
+	defer func() { _ = temp.Close() }()
+
+	if fs.config.TestHook != nil {
+		fs.config.TestHook(temp)
+	}
+
+	enc := json.NewEncoder(temp)
 	enc.SetIndent("", "  ")
 
-	err = enc.Encode(data)
+	err := enc.Encode(data)
 	if err != nil {
 		return fmt.Errorf("%s encode error: %w", name, err)
 	}
 
+	err = temp.Chmod(mode)
+	if err != nil {
+		return fmt.Errorf("%s save error: %w", name, err)
+	}
+
+	err = temp.Sync()
+	if err != nil {
+		return fmt.Errorf("%s save error: %w", name, err)
+	}
+
+	err = temp.Close()
+	if err != nil {
+		return fmt.Errorf("%s save error: %w", name, err)
+	}
+
+	return nil
+}
+
+func syncDir(dir string) error {
+	file, err := os.Open(filepath.Clean(dir))
+	if err != nil {
+		return fmt.Errorf("%s save error: %w", name, err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	err = file.Sync()
+	if err != nil {
+		return fmt.Errorf("%s save error: %w", name, err)
+	}
+
 	return nil
 }
 
+// Lock takes an exclusive advisory lock on the sidecar "<file>.lock" file
+// and waits for it up to Config.LockTimeout, the result releases the lock.
+func (fs *JSONFile[T]) Lock(ctx context.Context) (func(), error) {
+	timeout := fs.config.LockTimeout
+	if timeout <= 0 {
+		timeout = defaultLockTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(lockRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		file, locked, err := fs.acquire()
+		if err != nil {
+			return nil, fmt.Errorf("%s lock error: %w", name, err)
+		}
+
+		if locked {
+			return func() { release(file) }, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("%s lock error: %w", name, ErrLockTimeout)
+			}
+
+			return nil, fmt.Errorf("%s lock error: %w", name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (fs *JSONFile[T]) acquire() (*os.File, bool, error) {
+	lockname := filepath.Clean(fs.filename + lockFileExt)
+
+	file, err := os.OpenFile(lockname, os.O_CREATE|os.O_RDWR, defaultFilePerm)
+	if err != nil {
+		return nil, false, err
+	}
+
+	locked, err := tryLock(file)
+	if err != nil || !locked {
+		_ = file.Close()
+
+		return nil, false, err
+	}
+
+	// the previous owner could remove the file between our open and lock calls
+	opened, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return nil, false, err
+	}
+
+	current, err := os.Stat(lockname)
+	if err != nil || !os.SameFile(opened, current) {
+		_ = unlock(file)
+		_ = file.Close()
+
+		return nil, false, nil
+	}
+
+	return file, true, nil
+}
+
 func (fs *JSONFile[T]) init() error {
 	fs.filename = filepath.Clean(fs.filename)
 
@@ -1,12 +1,14 @@
 package jsonfile_test
 
 import (
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/therenotomorrow/tasker/internal/config"
 	"github.com/therenotomorrow/tasker/pkg/jsonfile"
@@ -153,3 +155,131 @@ func TestIntegrationJSONFileSave(t *testing.T) {
 		t.Fatalf("Save() got = %v, error = %v, want = %v", got, err, want)
 	}
 }
+
+func TestIntegrationJSONFileSaveAtomic(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		hook func(file *os.File)
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "cannot encode",
+			args: args{hook: func(file *os.File) { _ = file.Close() }},
+			want: "JSONFile encode error",
+		},
+		{
+			name: "cannot rename",
+			args: args{hook: func(file *os.File) { _ = os.Remove(file.Name()) }},
+			want: "JSONFile save error",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			filename := filepath.Join(dir, "tasks.json")
+			before := `{"str":"24","num":42}`
+
+			_ = os.WriteFile(filename, []byte(before), regularPerms)
+
+			// inject failure only into the temporary file of the Save call
+			hook := func(file *os.File) {
+				if strings.HasSuffix(file.Name(), ".tmp") {
+					test.args.hook(file)
+				}
+			}
+
+			file, _ := jsonfile.New[Type](jsonfile.Config{File: filename, TestHook: hook})
+
+			err := file.Save(Type{Str: "42", Num: 24})
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Fatalf("Save() error = %v, want = %v", err, test.want)
+			}
+
+			got, _ := os.ReadFile(filepath.Clean(filename))
+			if string(got) != before {
+				t.Errorf("Save() got = %v, want = %v", string(got), before)
+			}
+
+			entries, _ := os.ReadDir(dir)
+			if len(entries) != 1 {
+				t.Errorf("Save() got = %v, want = %v", len(entries), 1)
+			}
+		})
+	}
+}
+
+func TestIntegrationJSONFileSaveKeepsPerms(t *testing.T) {
+	t.Parallel()
+
+	const sharedPerms = 0o640
+
+	filename := filepath.Join(t.TempDir(), "tasks.json")
+
+	_ = os.WriteFile(filename, []byte("{}"), sharedPerms)
+
+	file, _ := jsonfile.New[Type](jsonfile.Config{File: filename})
+
+	err := file.Save(Type{Str: "42", Num: 24})
+	if err != nil {
+		t.Fatalf("Save() error = %v, want = %v", err, nil)
+	}
+
+	info, _ := os.Stat(filename)
+	if got := info.Mode().Perm(); got != sharedPerms {
+		t.Errorf("Save() got = %v, want = %v", got, os.FileMode(sharedPerms))
+	}
+}
+
+func TestIntegrationJSONFileLock(t *testing.T) {
+	t.Parallel()
+
+	const timeout = 50 * time.Millisecond
+
+	var (
+		ctx      = t.Context()
+		filename = filepath.Join(t.TempDir(), "tasks.json")
+		first, _ = jsonfile.New[Type](jsonfile.Config{File: filename})
+		other, _ = jsonfile.New[Type](jsonfile.Config{File: filename, LockTimeout: timeout})
+	)
+
+	unlock, err := first.Lock(ctx)
+	if err != nil {
+		t.Fatalf("Lock() error = %v, want = %v", err, nil)
+	}
+
+	_, err = other.Lock(ctx)
+	if !errors.Is(err, jsonfile.ErrLockTimeout) {
+		t.Errorf("Lock() error = %v, want = %v", err, jsonfile.ErrLockTimeout)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err = other.Lock(canceled)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Lock() error = %v, want = %v", err, context.Canceled)
+	}
+
+	unlock()
+
+	unlock, err = other.Lock(ctx)
+	if err != nil {
+		t.Fatalf("Lock() error = %v, want = %v", err, nil)
+	}
+
+	unlock()
+
+	// released lock must not leave the sidecar file behind
+	if _, err = os.Stat(filename + ".lock"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Lock() error = %v, want = %v", err, os.ErrNotExist)
+	}
+}
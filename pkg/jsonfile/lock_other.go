@@ -0,0 +1,18 @@
+//go:build !unix && !windows
+
+package jsonfile
+
+import "os"
+
+// advisory locks are not supported here, so nobody wins the lock.
+func tryLock(_ *os.File) (bool, error) {
+	return false, ErrLockUnsupported
+}
+
+func release(file *os.File) {
+	_ = file.Close()
+}
+
+func unlock(_ *os.File) error {
+	return ErrLockUnsupported
+}
@@ -0,0 +1,33 @@
+//go:build unix
+
+package jsonfile
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+func tryLock(file *os.File) (bool, error) {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+
+	switch {
+	case errors.Is(err, syscall.EWOULDBLOCK):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
+
+// release removes the file before the unlock, so nobody waits on the stale file.
+func release(file *os.File) {
+	_ = os.Remove(file.Name())
+	_ = unlock(file)
+	_ = file.Close()
+}
+
+func unlock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,70 @@
+//go:build windows
+
+package jsonfile
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	lockfileFailImmediately = 0x1
+	lockfileExclusiveLock   = 0x2
+	lockWholeFile           = ^uint32(0)
+
+	errorLockViolation syscall.Errno = 33
+)
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+func tryLock(file *os.File) (bool, error) {
+	overlapped := new(syscall.Overlapped)
+
+	ok, _, err := procLockFileEx.Call(
+		file.Fd(),
+		lockfileExclusiveLock|lockfileFailImmediately,
+		0,
+		uintptr(lockWholeFile),
+		uintptr(lockWholeFile),
+		uintptr(unsafe.Pointer(overlapped)),
+	)
+
+	switch {
+	case ok != 0:
+		return true, nil
+	case errors.Is(err, errorLockViolation):
+		return false, nil
+	}
+
+	return false, err
+}
+
+// release keeps the file in place, it cannot be removed while it is open and locked,
+// and the next owner takes the lock on it anyway.
+func release(file *os.File) {
+	_ = unlock(file)
+	_ = file.Close()
+}
+
+func unlock(file *os.File) error {
+	overlapped := new(syscall.Overlapped)
+
+	ok, _, err := procUnlockFileEx.Call(
+		file.Fd(),
+		0,
+		uintptr(lockWholeFile),
+		uintptr(lockWholeFile),
+		uintptr(unsafe.Pointer(overlapped)),
+	)
+	if ok == 0 {
+		return err
+	}
+
+	return nil
+}